@@ -58,9 +58,12 @@ Handlebars.registerHelper('contains', (haystack, needle) => haystack.includes(ne
 const data = JSON.parse(fs.readFileSync(__dirname + '/../requests_schema.json', 'utf8'));
 
 
+// Actions implemented by hand in src/ instead of being generated
+const handWrittenActions = ['begin_transaction'];
+
 // Генерация методов на основе JSON
 const generateMethods = (requests) => {
-  return requests.map(request => {
+  return requests.filter(request => !handWrittenActions.includes(request.action)).map(request => {
     const allParameters = processParameters(request.parameters);
 
     const parametersList = allParameters.map(param => {
@@ -1,3 +1,3 @@
 module github.com/s00d/RocksDBFusion/rocksdb-client-go
 
-go 1.17
+go 1.23
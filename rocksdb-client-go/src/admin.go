@@ -0,0 +1,61 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AdminLogEntry is one record of the server's admin action log.
+type AdminLogEntry struct {
+	// Timestamp is when the action was performed.
+	Timestamp time.Time `json:"timestamp"`
+	// Action is the protocol action, e.g. "backup" or "drop_column_family".
+	Action string `json:"action"`
+	// Actor identifies who issued the action (token name or client address).
+	Actor string `json:"actor"`
+	// Target is the affected object, such as a column family or backup ID.
+	Target string `json:"target,omitempty"`
+	// Success reports whether the action completed.
+	Success bool `json:"success"`
+	// Details carries the server's result or error message.
+	Details string `json:"details,omitempty"`
+}
+
+// AdminLogQuery filters GetAdminLog results. Zero values are ignored.
+type AdminLogQuery struct {
+	Since  time.Time
+	Action string
+	Limit  int
+}
+
+// GetAdminLog fetches the server's audit trail of administrative actions
+// (backups, restores, column family drops, compactions) with the
+// `get_admin_log` action, newest entries first.
+func (c *RocksDBClient) GetAdminLog(query AdminLogQuery) ([]AdminLogEntry, error) {
+	request := Request{
+		Action:  ActionGetAdminLog,
+		Options: map[string]string{},
+	}
+	if !query.Since.IsZero() {
+		request.Options[OptionSince] = query.Since.UTC().Format(time.RFC3339)
+	}
+	if query.Action != "" {
+		request.Options[OptionAction] = query.Action
+	}
+	if query.Limit > 0 {
+		request.Options[OptionLimit] = strconv.Itoa(query.Limit)
+	}
+
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AdminLogEntry
+	if err := json.Unmarshal([]byte(response.Result), &entries); err != nil {
+		return nil, fmt.Errorf("error decoding admin log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,19 @@
+package rocksdbclient
+
+// PauseBackgroundWork stops the server's background flushes and compactions
+// with the `pause_background_work` action, e.g. for a maintenance window or
+// while a bulk import runs. It waits for running jobs to finish. Writes keep
+// being accepted until the memtable and L0 limits stall them, so pair every
+// pause with ContinueBackgroundWork.
+func (c *RocksDBClient) PauseBackgroundWork() error {
+	_, err := c.SendRequest(Request{Action: ActionPauseBackgroundWork, Options: map[string]string{}})
+	return err
+}
+
+// ContinueBackgroundWork resumes background work stopped by
+// PauseBackgroundWork with the `continue_background_work` action. RocksDB
+// counts pauses, so each pause needs its own continue.
+func (c *RocksDBClient) ContinueBackgroundWork() error {
+	_, err := c.SendRequest(Request{Action: ActionContinueBackgroundWork, Options: map[string]string{}})
+	return err
+}
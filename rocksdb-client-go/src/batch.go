@@ -0,0 +1,129 @@
+package rocksdbclient
+
+import "fmt"
+
+// OperationType is the kind of mutation carried by an Operation.
+type OperationType string
+
+const (
+	OpPut    OperationType = "put"
+	OpMerge  OperationType = "merge"
+	OpDelete OperationType = "delete"
+)
+
+// Operation is a single mutation applied as part of BatchWrite.
+type Operation struct {
+	Type   OperationType `json:"type"`
+	Key    string        `json:"key"`
+	Value  *string       `json:"value,omitempty"`
+	CfName *string       `json:"cf_name,omitempty"`
+}
+
+func (op Operation) validate() error {
+	if op.Key == "" {
+		return fmt.Errorf("%s operation: key must be provided", op.Type)
+	}
+	switch op.Type {
+	case OpPut, OpMerge:
+		if op.Value == nil {
+			return fmt.Errorf("%s operation on %q: value must be provided", op.Type, op.Key)
+		}
+	case OpDelete:
+	default:
+		return fmt.Errorf("unknown operation type %q", op.Type)
+	}
+	return nil
+}
+
+// BatchWrite sends all operations in one `batch_write` request. The server
+// applies them atomically in a single RocksDB write batch, so either every
+// operation is persisted or none is.
+func (c *RocksDBClient) BatchWrite(ops []Operation) (*Response, error) {
+	return c.BatchWriteWith(ops)
+}
+
+// WriteBatchOptions sets the thresholds at which a WriteBatch flushes
+// itself. Zero values disable the corresponding threshold.
+type WriteBatchOptions struct {
+	// MaxOps flushes once the batch holds this many operations.
+	MaxOps int
+	// MaxBytes flushes once the keys and values in the batch exceed this size.
+	MaxBytes int
+	// Write is sent with every flush of the batch.
+	Write WriteOptions
+}
+
+// WriteBatch buffers mutations client-side and sends them as a single
+// `batch_write` request on Commit. With thresholds configured it flushes
+// automatically; each flush is atomic on its own, but a batch that was
+// flushed several times is not atomic as a whole.
+type WriteBatch struct {
+	c    *RocksDBClient
+	opts WriteBatchOptions
+	ops  []Operation
+	size int
+}
+
+// NewWriteBatch creates an empty batch bound to the client.
+func (c *RocksDBClient) NewWriteBatch(opts WriteBatchOptions) *WriteBatch {
+	return &WriteBatch{c: c, opts: opts}
+}
+
+// Put buffers a put operation.
+func (b *WriteBatch) Put(key, value string, cfName *string) error {
+	return b.add(Operation{Type: OpPut, Key: key, Value: &value, CfName: cfName})
+}
+
+// Merge buffers a merge operation.
+func (b *WriteBatch) Merge(key, value string, cfName *string) error {
+	return b.add(Operation{Type: OpMerge, Key: key, Value: &value, CfName: cfName})
+}
+
+// Delete buffers a delete operation.
+func (b *WriteBatch) Delete(key string, cfName *string) error {
+	return b.add(Operation{Type: OpDelete, Key: key, CfName: cfName})
+}
+
+func (b *WriteBatch) add(op Operation) error {
+	if err := op.validate(); err != nil {
+		return err
+	}
+	b.ops = append(b.ops, op)
+	b.size += len(op.Key)
+	if op.Value != nil {
+		b.size += len(*op.Value)
+	}
+	if (b.opts.MaxOps > 0 && len(b.ops) >= b.opts.MaxOps) || (b.opts.MaxBytes > 0 && b.size >= b.opts.MaxBytes) {
+		return b.Commit()
+	}
+	return nil
+}
+
+// Len returns the number of buffered operations.
+func (b *WriteBatch) Len() int {
+	return len(b.ops)
+}
+
+// Size returns the number of key and value bytes buffered.
+func (b *WriteBatch) Size() int {
+	return b.size
+}
+
+// Commit sends the buffered operations in one request and empties the
+// batch. On failure the operations stay buffered so Commit can be retried.
+func (b *WriteBatch) Commit() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	if _, err := b.c.BatchWriteWith(b.ops, WithWriteOptions(b.opts.Write)); err != nil {
+		return err
+	}
+	b.Clear()
+	return nil
+}
+
+// Clear drops the buffered operations without sending them.
+func (b *WriteBatch) Clear() {
+	b.ops = nil
+	b.size = 0
+}
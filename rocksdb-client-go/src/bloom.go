@@ -0,0 +1,272 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bloomFilter is a fixed-size bloom filter using double hashing over a
+// 64-bit FNV-1a digest.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+func newBloomFilter(expected int, falsePositiveRate float64) *bloomFilter {
+	if expected < 1 {
+		expected = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	m := uint64(math.Ceil(-float64(expected) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+func (f *bloomFilter) hashes(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+	return sum, (sum >> 33) | 1
+}
+
+func (f *bloomFilter) add(key string) {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *bloomFilter) mayContain(key string) bool {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NegativeCacheOptions configures a NegativeCache.
+type NegativeCacheOptions struct {
+	// ExpectedKeys sizes the filter. Defaults to 100000.
+	ExpectedKeys int
+	// FalsePositiveRate is the target rate of absent keys that still reach
+	// the server. Defaults to 0.01.
+	FalsePositiveRate float64
+	// RefreshInterval rebuilds the filter from a key scan periodically once
+	// Start is called. Defaults to 5 minutes.
+	RefreshInterval time.Duration
+	// PageSize is the number of keys fetched per round trip while refreshing.
+	// Defaults to 1000.
+	PageSize int
+}
+
+// NegativeCache is a client-side bloom filter over the keys of the client's
+// default column family, the one set with WithDefaultCF or the server's
+// default. Installed as an interceptor, it answers `get` requests for keys
+// that are definitely absent without a network round trip and adds the keys
+// of all writes sent through the client to the filter. Requests for other
+// column families pass through.
+//
+// Keys written by other clients are only picked up on the next refresh, so a
+// get can report a missing key for up to RefreshInterval after another client
+// created it. Writes whose keys are not known, such as restore or
+// ingest_external_file, disable the cache until the next refresh.
+type NegativeCache struct {
+	c    *RocksDBClient
+	opts NegativeCacheOptions
+
+	mu     sync.RWMutex
+	filter *bloomFilter
+	ready  bool
+	// written collects keys written while a refresh is scanning, so they
+	// are not lost when the new filter is swapped in.
+	written []string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewNegativeCache creates a negative cache for c and installs it as an
+// interceptor. It only short-circuits requests after the first Refresh.
+func NewNegativeCache(c *RocksDBClient, opts NegativeCacheOptions) *NegativeCache {
+	if opts.ExpectedKeys <= 0 {
+		opts.ExpectedKeys = 100000
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 5 * time.Minute
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultSpillPageSize
+	}
+	nc := &NegativeCache{c: c, opts: opts, filter: newBloomFilter(opts.ExpectedKeys, opts.FalsePositiveRate)}
+	c.Use(nc.intercept)
+	return nc
+}
+
+// Refresh rebuilds the filter from a full key scan and swaps it in.
+func (nc *NegativeCache) Refresh() error {
+	nc.mu.Lock()
+	nc.written = []string{}
+	nc.mu.Unlock()
+
+	filter, err := nc.scan()
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	if err != nil {
+		nc.written = nil
+		return err
+	}
+	if nc.written == nil {
+		// A write with unknown keys ran during the scan, which may have
+		// missed them.
+		return nil
+	}
+	for _, key := range nc.written {
+		filter.add(key)
+	}
+	nc.written = nil
+	nc.filter = filter
+	nc.ready = true
+	return nil
+}
+
+func (nc *NegativeCache) scan() (*bloomFilter, error) {
+	filter := newBloomFilter(nc.opts.ExpectedKeys, nc.opts.FalsePositiveRate)
+	for start := 0; ; start += nc.opts.PageSize {
+		response, err := nc.c.SendRequest(Request{
+			Action: ActionKeys,
+			Options: map[string]string{
+				OptionStart: strconv.Itoa(start),
+				OptionLimit: strconv.Itoa(nc.opts.PageSize),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var keys []string
+		if err := json.Unmarshal([]byte(response.Result), &keys); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			filter.add(key)
+		}
+		if len(keys) < nc.opts.PageSize {
+			return filter, nil
+		}
+	}
+}
+
+// Start refreshes the filter in the background every RefreshInterval.
+func (nc *NegativeCache) Start() {
+	if nc.stop != nil {
+		return
+	}
+	nc.stop = make(chan struct{})
+	nc.done = make(chan struct{})
+	go func() {
+		defer close(nc.done)
+		ticker := time.NewTicker(nc.opts.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				nc.Refresh()
+			case <-nc.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background refreshing started with Start.
+func (nc *NegativeCache) Stop() {
+	if nc.stop == nil {
+		return
+	}
+	close(nc.stop)
+	<-nc.done
+	nc.stop = nil
+}
+
+// MayContain reports whether key may exist. It always returns true before
+// the first Refresh.
+func (nc *NegativeCache) MayContain(key string) bool {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return !nc.ready || nc.filter.mayContain(key)
+}
+
+func (nc *NegativeCache) add(key *string) {
+	if key == nil {
+		return
+	}
+	nc.mu.Lock()
+	nc.filter.add(*key)
+	if nc.written != nil {
+		nc.written = append(nc.written, *key)
+	}
+	nc.mu.Unlock()
+}
+
+// invalidate stops answering gets until the next Refresh, and makes a
+// running Refresh discard its scan.
+func (nc *NegativeCache) invalidate() {
+	nc.mu.Lock()
+	nc.ready = false
+	nc.written = nil
+	nc.mu.Unlock()
+}
+
+// covers reports whether cf is the column family the filter was built from.
+func (nc *NegativeCache) covers(cf *string) bool {
+	return cfLabel(cf) == cfLabel(nc.c.defaultCF)
+}
+
+func (nc *NegativeCache) intercept(request Request, next Handler) (*Response, error) {
+	switch {
+	case request.Action == ActionGet:
+		if nc.covers(request.CfName) && request.Key != nil && !nc.MayContain(*request.Key) {
+			if request.DefaultValue != nil {
+				return &Response{Success: true, Result: *request.DefaultValue}, nil
+			}
+			return nil, newServerError(request.Action, "Key not found")
+		}
+	case request.Action == ActionBatchWrite:
+		for _, op := range request.Operations {
+			if nc.covers(op.CfName) && op.Type != OpDelete {
+				nc.add(&op.Key)
+			}
+		}
+	case !isMutation(request.Action):
+	case request.Key == nil && len(request.Keys) == 0:
+		// The write may create any key. Invalidating again once it is done
+		// covers a refresh that started while it ran.
+		nc.invalidate()
+		defer nc.invalidate()
+	case nc.covers(request.CfName):
+		// Any write may create its key, e.g. put_if_absent or incr. Adding
+		// the keys of deletes as well only costs a round trip later.
+		nc.add(request.Key)
+		for _, key := range request.Keys {
+			nc.add(&key)
+		}
+	}
+	return next(request)
+}
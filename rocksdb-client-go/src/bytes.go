@@ -0,0 +1,51 @@
+package rocksdbclient
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// The protocol carries keys and values as JSON strings, which cannot hold
+// arbitrary bytes. The []byte API base64-encodes them and sets the
+// "encoding": "base64" option; servers that honor it store the raw bytes and
+// answer base64-encoded, others store the encoded text. Either way values
+// written with PutBytes round-trip through GetBytes unchanged.
+
+func bytesRequest(action string, key []byte, cfName *string) Request {
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	return Request{
+		Action:  action,
+		Key:     &encodedKey,
+		CfName:  cfName,
+		Options: map[string]string{OptionEncoding: "base64"},
+	}
+}
+
+// PutBytes stores a binary key-value pair.
+func (c *RocksDBClient) PutBytes(key, value []byte, cfName *string) error {
+	request := bytesRequest(ActionPut, key, cfName)
+	encodedValue := base64.StdEncoding.EncodeToString(value)
+	request.Value = &encodedValue
+	_, err := c.SendRequest(request)
+	return err
+}
+
+// GetBytes reads a value stored with PutBytes. Missing keys return an error
+// matching ErrKeyNotFound.
+func (c *RocksDBClient) GetBytes(key []byte, cfName *string) ([]byte, error) {
+	response, err := c.SendRequest(bytesRequest(ActionGet, key, cfName))
+	if err != nil {
+		return nil, err
+	}
+	value, err := base64.StdEncoding.DecodeString(response.Result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding binary value: %w", err)
+	}
+	return value, nil
+}
+
+// DeleteBytes removes a key stored with PutBytes.
+func (c *RocksDBClient) DeleteBytes(key []byte, cfName *string) error {
+	_, err := c.SendRequest(bytesRequest(ActionDelete, key, cfName))
+	return err
+}
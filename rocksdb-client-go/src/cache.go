@@ -0,0 +1,272 @@
+package rocksdbclient
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cache is an in-process cache the client can read through and write
+// through. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+	Delete(key string)
+	Clear()
+}
+
+// RistrettoStore is the subset of *ristretto.Cache[string, string]
+// (github.com/dgraph-io/ristretto/v2) used by RistrettoCache.
+type RistrettoStore interface {
+	Get(key string) (string, bool)
+	Set(key string, value string, cost int64) bool
+	Del(key string)
+	Clear()
+}
+
+// RistrettoCache adapts a ristretto cache. Entries are admitted with a cost
+// equal to the value length.
+func RistrettoCache(store RistrettoStore) Cache {
+	return ristrettoCache{store}
+}
+
+type ristrettoCache struct {
+	store RistrettoStore
+}
+
+func (r ristrettoCache) Get(key string) (string, bool) { return r.store.Get(key) }
+func (r ristrettoCache) Set(key, value string)         { r.store.Set(key, value, int64(len(value))) }
+func (r ristrettoCache) Delete(key string)             { r.store.Del(key) }
+func (r ristrettoCache) Clear()                        { r.store.Clear() }
+
+// BigCacheStore is the subset of *bigcache.BigCache
+// (github.com/allegro/bigcache/v3) used by BigCache.
+type BigCacheStore interface {
+	Get(key string) ([]byte, error)
+	Set(key string, entry []byte) error
+	Delete(key string) error
+	Reset() error
+}
+
+// BigCache adapts a bigcache instance. bigcache errors (including misses)
+// are treated as cache misses.
+func BigCache(store BigCacheStore) Cache {
+	return bigCache{store}
+}
+
+type bigCache struct {
+	store BigCacheStore
+}
+
+func (b bigCache) Get(key string) (string, bool) {
+	entry, err := b.store.Get(key)
+	if err != nil {
+		return "", false
+	}
+	return string(entry), true
+}
+
+func (b bigCache) Set(key, value string) { b.store.Set(key, []byte(value)) }
+func (b bigCache) Delete(key string)     { b.store.Delete(key) }
+func (b bigCache) Clear()                { b.store.Reset() }
+
+// cacheKey scopes a key by column family so that equal keys in different
+// column families do not collide.
+func cacheKey(cfName *string, key string) string {
+	if cfName == nil {
+		return key
+	}
+	return *cfName + "\x00" + key
+}
+
+// UseCache installs cache as a read-through / write-through layer. Gets are
+// answered from the cache when possible and populated on a miss, puts store
+// the written value, and every other write invalidates the affected keys, or
+// the whole cache when they are not known, e.g. for delete_range. Values
+// written with a TTL are only served from the cache until the TTL expires.
+// Requests inside a transaction bypass the cache; the keys a transaction
+// wrote are invalidated when it commits.
+func (c *RocksDBClient) UseCache(cache Cache) {
+	cc := &cachingInterceptor{cache: cache, txnWrites: map[string][]string{}, expiry: map[string]time.Time{}}
+	c.Use(cc.intercept)
+}
+
+// minExpirySweep is the number of TTL'd keys tracked before expired ones are
+// swept.
+const minExpirySweep = 1024
+
+type cachingInterceptor struct {
+	cache Cache
+
+	mu sync.Mutex
+	// txnWrites holds the cache keys written by each open transaction.
+	txnWrites map[string][]string
+	// expiry holds when keys written with a TTL expire on the server.
+	expiry  map[string]time.Time
+	sweepAt int
+}
+
+func (cc *cachingInterceptor) intercept(request Request, next Handler) (*Response, error) {
+	if request.Txn != nil && *request.Txn {
+		return cc.interceptTxn(request, next)
+	}
+
+	switch request.Action {
+	case ActionGet:
+		// Reads with options, such as conditional reads, answer in a
+		// different format and go to the server.
+		if request.Key == nil || len(request.Options) > 0 {
+			break
+		}
+		key := cacheKey(request.CfName, *request.Key)
+		if value, found := cc.cache.Get(key); found && !cc.expired(key) {
+			return &Response{Success: true, Result: value}, nil
+		}
+		response, err := next(request)
+		if err == nil && request.DefaultValue == nil {
+			cc.cache.Set(key, response.Result)
+		}
+		return response, err
+	case ActionPut:
+		response, err := next(request)
+		if request.Key != nil {
+			key := cacheKey(request.CfName, *request.Key)
+			if err == nil && request.Value != nil && cc.setExpiry(key, request.Options[OptionTTL]) {
+				cc.cache.Set(key, *request.Value)
+			} else {
+				cc.invalidate(key)
+			}
+		}
+		return response, err
+	case ActionTouch:
+		response, err := next(request)
+		for _, key := range request.Keys {
+			key = cacheKey(request.CfName, key)
+			if err != nil || !cc.setExpiry(key, request.Options[OptionTTL]) {
+				cc.invalidate(key)
+			}
+		}
+		return response, err
+	}
+	if !isMutation(request.Action) {
+		return next(request)
+	}
+	defer func() {
+		keys := writtenKeys(request)
+		if keys == nil {
+			cc.clear()
+		}
+		for _, key := range keys {
+			cc.invalidate(key)
+		}
+	}()
+	return next(request)
+}
+
+func (cc *cachingInterceptor) interceptTxn(request Request, next Handler) (*Response, error) {
+	var id string
+	if request.TxnID != nil {
+		id = *request.TxnID
+	}
+	switch request.Action {
+	case ActionCommitTransaction:
+		defer func() {
+			cc.mu.Lock()
+			keys := cc.txnWrites[id]
+			delete(cc.txnWrites, id)
+			cc.mu.Unlock()
+			for _, key := range keys {
+				cc.invalidate(key)
+			}
+		}()
+	case ActionRollbackTransaction:
+		cc.mu.Lock()
+		delete(cc.txnWrites, id)
+		cc.mu.Unlock()
+	default:
+		if isMutation(request.Action) {
+			keys := writtenKeys(request)
+			if keys == nil {
+				defer cc.clear()
+			}
+			cc.mu.Lock()
+			cc.txnWrites[id] = append(cc.txnWrites[id], keys...)
+			cc.mu.Unlock()
+		}
+	}
+	return next(request)
+}
+
+// writtenKeys returns the cache keys a write touches, or nil if they are not
+// known.
+func writtenKeys(request Request) []string {
+	var keys []string
+	if request.Key != nil {
+		keys = append(keys, cacheKey(request.CfName, *request.Key))
+	}
+	for _, key := range request.Keys {
+		keys = append(keys, cacheKey(request.CfName, key))
+	}
+	for _, op := range request.Operations {
+		keys = append(keys, cacheKey(op.CfName, op.Key))
+	}
+	return keys
+}
+
+// setExpiry records when key expires given the ttl option in seconds, or
+// forgets its expiry if ttl is empty. It reports false for an invalid ttl.
+func (cc *cachingInterceptor) setExpiry(key, ttl string) bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if ttl == "" {
+		delete(cc.expiry, key)
+		return true
+	}
+	seconds, err := strconv.ParseInt(ttl, 10, 64)
+	if err != nil {
+		delete(cc.expiry, key)
+		return false
+	}
+	now := time.Now()
+	cc.expiry[key] = now.Add(time.Duration(seconds) * time.Second)
+	if len(cc.expiry) >= max(cc.sweepAt, minExpirySweep) {
+		for k, at := range cc.expiry {
+			if !now.Before(at) {
+				delete(cc.expiry, k)
+			}
+		}
+		cc.sweepAt = 2 * len(cc.expiry)
+	}
+	return true
+}
+
+// expired reports whether key was written with a TTL that has passed, and
+// drops it from the cache if so.
+func (cc *cachingInterceptor) expired(key string) bool {
+	cc.mu.Lock()
+	at, found := cc.expiry[key]
+	if found && !time.Now().Before(at) {
+		delete(cc.expiry, key)
+	} else {
+		found = false
+	}
+	cc.mu.Unlock()
+	if found {
+		cc.cache.Delete(key)
+	}
+	return found
+}
+
+func (cc *cachingInterceptor) invalidate(key string) {
+	cc.mu.Lock()
+	delete(cc.expiry, key)
+	cc.mu.Unlock()
+	cc.cache.Delete(key)
+}
+
+func (cc *cachingInterceptor) clear() {
+	cc.mu.Lock()
+	cc.expiry = map[string]time.Time{}
+	cc.mu.Unlock()
+	cc.cache.Clear()
+}
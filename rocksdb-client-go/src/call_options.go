@@ -0,0 +1,106 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// CallOption adjusts a single request, e.g. to ask the server for extra
+// information in the response.
+type CallOption func(*Request)
+
+// WithReturnOld asks the server to return the value a write replaced, see
+// WriteResult.
+func WithReturnOld() CallOption {
+	return func(request *Request) {
+		request.Options[OptionReturnOld] = "true"
+	}
+}
+
+func applyCallOptions(request *Request, opts []CallOption) {
+	if request.Options == nil {
+		request.Options = map[string]string{}
+	}
+	for _, opt := range opts {
+		opt(request)
+	}
+}
+
+// WriteResult is the result of PutWith, DeleteWith and MergeWith.
+type WriteResult struct {
+	// Old is the value before the write, nil if the key did not exist. It is
+	// only filled in when the write was sent WithReturnOld.
+	Old *string `json:"old"`
+	// Raw is the undecoded server response.
+	Raw *Response `json:"-"`
+}
+
+// PutWith stores a key-value pair like Put, applying call options such as
+// WithReturnOld.
+func (c *RocksDBClient) PutWith(key, value string, cfName *string, opts ...CallOption) (*WriteResult, error) {
+	return c.sendWrite(Request{Action: ActionPut, Key: &key, Value: &value, CfName: cfName}, opts)
+}
+
+// DeleteWith removes key like Delete, applying call options such as
+// WithReturnOld.
+func (c *RocksDBClient) DeleteWith(key string, cfName *string, opts ...CallOption) (*WriteResult, error) {
+	return c.sendWrite(Request{Action: ActionDelete, Key: &key, CfName: cfName}, opts)
+}
+
+func (c *RocksDBClient) sendWrite(request Request, opts []CallOption) (*WriteResult, error) {
+	applyCallOptions(&request, opts)
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	result := &WriteResult{Raw: response}
+	if request.Options[OptionReturnOld] == "true" {
+		if err := json.Unmarshal([]byte(response.Result), result); err != nil {
+			return nil, fmt.Errorf("error decoding %s result: %w", request.Action, err)
+		}
+	}
+	return result, nil
+}
+
+// WithIfModifiedSince makes GetWith skip the payload when the key has not
+// been written since sequence, the ReadResult.Sequence of an earlier read.
+// The server then answers with ReadResult.NotModified set.
+func WithIfModifiedSince(sequence uint64) CallOption {
+	return func(request *Request) {
+		request.Options[OptionIfModifiedSince] = strconv.FormatUint(sequence, 10)
+	}
+}
+
+// ReadResult is the result of GetWith.
+type ReadResult struct {
+	Value string `json:"value"`
+	// Sequence is the sequence number of the latest write of the key. It is
+	// only filled in for conditional reads.
+	Sequence uint64 `json:"sequence"`
+	// NotModified reports that the key is unchanged since the sequence passed
+	// to WithIfModifiedSince; Value is empty then.
+	NotModified bool `json:"not_modified"`
+	// Raw is the undecoded server response.
+	Raw *Response `json:"-"`
+}
+
+// GetWith reads key like Get, applying call options such as
+// WithIfModifiedSince or WithReadOptions. Missing keys return an error matching ErrKeyNotFound.
+func (c *RocksDBClient) GetWith(key string, cfName *string, opts ...CallOption) (*ReadResult, error) {
+	request := Request{Action: ActionGet, Key: &key, CfName: cfName}
+	applyCallOptions(&request, opts)
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	result := &ReadResult{Raw: response}
+	if _, conditional := request.Options[OptionIfModifiedSince]; !conditional {
+		result.Value = response.Result
+		return result, nil
+	}
+	if err := json.Unmarshal([]byte(response.Result), result); err != nil {
+		return nil, fmt.Errorf("error decoding get result: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package rocksdbclient
+
+// CallerUsage is the traffic recorded for one caller label.
+type CallerUsage struct {
+	Requests      int64
+	RequestBytes  int64
+	ResponseBytes int64
+}
+
+// SetCaller tags every request sent by the client with label, e.g. the name
+// of the service or component using it. The label is forwarded to the server
+// in the "caller" option and traffic is accounted per label, see Usage. A
+// request that already sets the "caller" option keeps its own label, which
+// allows tagging individual SendRequest calls. An empty label disables
+// tagging.
+func (c *RocksDBClient) SetCaller(label string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caller = label
+}
+
+// Usage returns a snapshot of the traffic sent per caller label since the
+// client was created or ResetUsage was called. Untagged requests are
+// accounted under the empty label. Byte counts are the encoded sizes on the
+// wire.
+func (c *RocksDBClient) Usage() map[string]CallerUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := make(map[string]CallerUsage, len(c.usage))
+	for label, u := range c.usage {
+		usage[label] = *u
+	}
+	return usage
+}
+
+// ResetUsage clears the per-caller counters, e.g. after they were exported.
+func (c *RocksDBClient) ResetUsage() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage = nil
+}
+
+// labelCaller returns the caller label of request, adding the client's
+// default label when the request has none. The options map is copied before
+// it is changed since it may be shared with the caller.
+func (c *RocksDBClient) labelCaller(request *Request) string {
+	if label, found := request.Options[OptionCaller]; found {
+		return label
+	}
+	if c.caller == "" {
+		return ""
+	}
+	options := make(map[string]string, len(request.Options)+1)
+	for k, v := range request.Options {
+		options[k] = v
+	}
+	options[OptionCaller] = c.caller
+	request.Options = options
+	return c.caller
+}
+
+func (c *RocksDBClient) recordUsage(label string, requestBytes, responseBytes int) {
+	if c.usage == nil {
+		c.usage = map[string]*CallerUsage{}
+	}
+	u := c.usage[label]
+	if u == nil {
+		u = &CallerUsage{}
+		c.usage[label] = u
+	}
+	u.Requests++
+	u.RequestBytes += int64(requestBytes)
+	u.ResponseBytes += int64(responseBytes)
+}
@@ -0,0 +1,121 @@
+package rocksdbclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// capabilityPrefix marks tokens in the capability format so the server can
+// tell them apart from plain tokens.
+const capabilityPrefix = "cap1"
+
+// ErrInvalidCapability is returned by VerifyCapability for malformed,
+// tampered or expired capability tokens.
+var ErrInvalidCapability = errors.New("invalid capability token")
+
+// CapabilityClaims restricts what a capability token may be used for. The
+// server only grants requests that are allowed by both the claims and the
+// parent token. Zero values leave a dimension unrestricted.
+type CapabilityClaims struct {
+	// Parent is the fingerprint of the parent token. It is filled in when the
+	// capability is minted and lets the server find the verification key.
+	Parent string `json:"parent"`
+	// Prefix limits access to keys starting with it.
+	Prefix string `json:"prefix,omitempty"`
+	// ColumnFamilies limits access to the listed column families.
+	ColumnFamilies []string `json:"cf_names,omitempty"`
+	// ReadOnly rejects every write.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// ExpiresAt is the time after which the token is rejected.
+	ExpiresAt time.Time `json:"expires_at"`
+	// IssuedAt is set when the capability is minted.
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// TokenFingerprint identifies a token without revealing it: the first 16 hex
+// digits of its SHA-256 hash.
+func TokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// MintCapability derives a restricted token from parent. The result has the
+// form "cap1.<claims>.<signature>", where claims is the base64url JSON
+// encoding of claims and signature is its HMAC-SHA256 keyed with parent.
+// Because only holders of the parent token can sign, a capability can be
+// handed to a subcomponent without giving it the parent's access.
+func MintCapability(parent string, claims CapabilityClaims) (string, error) {
+	if parent == "" {
+		return "", errors.New("parent token is required")
+	}
+	claims.Parent = TokenFingerprint(parent)
+	claims.IssuedAt = time.Now().UTC().Truncate(time.Second)
+	if !claims.ExpiresAt.IsZero() {
+		claims.ExpiresAt = claims.ExpiresAt.UTC()
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := capabilityPrefix + "." + base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + capabilitySignature(parent, payload), nil
+}
+
+// VerifyCapability checks the signature and expiry of a capability token
+// against parent and returns its claims. It mirrors the server's check and is
+// useful for services that accept capabilities themselves.
+func VerifyCapability(parent, token string) (*CapabilityClaims, error) {
+	payload, signature, found := cutLast(token, ".")
+	if !found || !strings.HasPrefix(payload, capabilityPrefix+".") {
+		return nil, ErrInvalidCapability
+	}
+	expected := capabilitySignature(parent, payload)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrInvalidCapability
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(payload, capabilityPrefix+"."))
+	if err != nil {
+		return nil, ErrInvalidCapability
+	}
+	var claims CapabilityClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCapability, err)
+	}
+	if claims.Parent != TokenFingerprint(parent) {
+		return nil, ErrInvalidCapability
+	}
+	if !claims.ExpiresAt.IsZero() && time.Now().After(claims.ExpiresAt) {
+		return nil, fmt.Errorf("%w: expired at %s", ErrInvalidCapability, claims.ExpiresAt.Format(time.RFC3339))
+	}
+	return &claims, nil
+}
+
+// MintCapability derives a restricted token from the client's own token.
+func (c *RocksDBClient) MintCapability(claims CapabilityClaims) (string, error) {
+	if c.token == nil {
+		return "", errors.New("client has no token to derive a capability from")
+	}
+	return MintCapability(*c.token, claims)
+}
+
+func capabilitySignature(parent, payload string) string {
+	mac := hmac.New(sha256.New, []byte(parent))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,134 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ScanCheckpoint records how far a scan has progressed so that a job which
+// crashes can resume right after the last key it finished processing.
+type ScanCheckpoint struct {
+	LastKey   string    `json:"last_key"`
+	Processed int64     `json:"processed"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+}
+
+// LoadScanCheckpoint reads a checkpoint from path. A missing file yields an
+// empty checkpoint, so the first run and resumed runs share the same code.
+func LoadScanCheckpoint(path string) (*ScanCheckpoint, error) {
+	cp := &ScanCheckpoint{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("error decoding checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// Started reports whether any key has been recorded yet.
+func (cp *ScanCheckpoint) Started() bool {
+	return cp.Processed > 0
+}
+
+// Record marks key as processed. It does not persist the checkpoint.
+func (cp *ScanCheckpoint) Record(key string) {
+	cp.LastKey = key
+	cp.Processed++
+	cp.UpdatedAt = time.Now()
+}
+
+// Save atomically writes the checkpoint to its file. It is a no-op for
+// checkpoints that were not loaded from a path.
+func (cp *ScanCheckpoint) Save() error {
+	if cp.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), filepath.Base(cp.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), cp.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Reset clears the checkpoint and removes its file, e.g. after a job
+// completed and the next run should start from the beginning.
+func (cp *ScanCheckpoint) Reset() error {
+	*cp = ScanCheckpoint{path: cp.path}
+	if cp.path == "" {
+		return nil
+	}
+	if err := os.Remove(cp.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// ScanFrom iterates the default column family in key order, starting after
+// cp.LastKey, and calls fn for every entry. The checkpoint is recorded after
+// each successful call and saved every saveEvery keys (every key when
+// saveEvery <= 0) as well as when the scan stops, so a failed run can be
+// resumed by loading the same checkpoint. A panic in fn is returned as a
+// *PanicError whose Offset counts entries across resumed runs.
+func (c *RocksDBClient) ScanFrom(cp *ScanCheckpoint, saveEvery int, fn func(key, value string) error) (err error) {
+	if saveEvery <= 0 {
+		saveEvery = 1
+	}
+
+	it, err := c.NewIterator()
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	defer func() {
+		if saveErr := cp.Save(); err == nil {
+			err = saveErr
+		}
+	}()
+
+	valid := it.Seek(cp.LastKey)
+	if valid && cp.Started() && it.Key() == cp.LastKey {
+		valid = it.Next()
+	}
+
+	for pending := 0; valid; valid = it.Next() {
+		if err := callScanFunc(fn, it.Key(), it.Value(), cp.Processed); err != nil {
+			return err
+		}
+		cp.Record(it.Key())
+		if pending++; pending >= saveEvery {
+			if err := cp.Save(); err != nil {
+				return err
+			}
+			pending = 0
+		}
+	}
+	return it.Err()
+}
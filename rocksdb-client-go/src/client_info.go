@@ -0,0 +1,154 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientInfo identifies a client to the server. It is sent in the `hello`
+// handshake on every new connection and shown by ListClients.
+type ClientInfo struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// WithClientInfo makes the client introduce itself with name and version
+// when it connects. The hostname is filled in from the OS.
+func WithClientInfo(name, version string) Option {
+	return func(c *RocksDBClient) {
+		hostname, _ := os.Hostname()
+		c.clientInfo = &ClientInfo{Name: name, Version: version, Hostname: hostname}
+	}
+}
+
+// helloResult is the result of the `hello` action.
+type helloResult struct {
+	ClientID string `json:"client_id"`
+	ServerInfo
+	// WireEncoding and Framing are the encoding and framing the server
+	// switched the connection to.
+	WireEncoding WireEncoding `json:"wire_encoding"`
+	Framing      string       `json:"framing"`
+}
+
+// handshake prepares a freshly dialed connection. Every connection starts
+// with newline-delimited JSON; the hello handshake is only sent when there
+// is something to announce or negotiate.
+func (c *RocksDBClient) handshake() error {
+	c.wire = wireFormat{}
+	c.clientID = ""
+	c.serverInfo = nil
+	if c.clientInfo == nil && !c.framing && !c.discovery && (c.encoding == "" || c.encoding == WireJSON) {
+		return nil
+	}
+	return c.hello()
+}
+
+// hello sends the `hello` handshake on a freshly dialed connection. It runs
+// under c.mu before any other request, so it talks to the connection
+// directly. Servers without the action are tolerated.
+func (c *RocksDBClient) hello() error {
+	request := Request{Action: ActionHello, Token: c.token, Options: map[string]string{OptionProtocolVersion: strconv.Itoa(ProtocolVersion)}}
+	if c.clientInfo != nil {
+		data, err := json.Marshal(c.clientInfo)
+		if err != nil {
+			return fmt.Errorf("error encoding client info: %w", err)
+		}
+		info := string(data)
+		request.Value = &info
+	}
+	if c.encoding != "" && c.encoding != WireJSON {
+		request.Options[OptionWireEncoding] = string(c.encoding)
+	}
+	if c.framing {
+		request.Options[OptionFraming] = framingLength
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return fmt.Errorf("error setting deadline: %w", err)
+	}
+	// Pipelined connections only set write deadlines, so the handshake's
+	// read deadline must not outlive it.
+	defer c.conn.SetDeadline(time.Time{})
+
+	data, err := c.wire.encode(c.codec, request)
+	if err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		return wrapTimeout(ActionHello, fmt.Errorf("error sending request: %w", err))
+	}
+	payload, _, err := c.wire.read(c.reader)
+	if err != nil {
+		return wrapTimeout(ActionHello, err)
+	}
+	response := &Response{}
+	if err := c.wire.decode(c.codec, payload, response); err != nil {
+		return err
+	}
+
+	if !response.Success {
+		if strings.Contains(response.Result, "Unknown action") {
+			c.serverInfo = &ServerInfo{}
+			return nil
+		}
+		return newServerError(ActionHello, response.Result)
+	}
+	var result helloResult
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+		return fmt.Errorf("error decoding hello result: %w", err)
+	}
+	c.clientID = result.ClientID
+	c.serverInfo = &result.ServerInfo
+	if result.WireEncoding != "" && result.WireEncoding == c.encoding {
+		c.wire.encoding = result.WireEncoding
+	}
+	c.wire.lengthPrefixed = c.framing && result.Framing == framingLength
+	return nil
+}
+
+// ClientID returns the ID the server assigned to the current connection in
+// the hello handshake, or "" if there was none.
+func (c *RocksDBClient) ClientID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clientID
+}
+
+// ConnectedClient is one entry of ListClients.
+type ConnectedClient struct {
+	ID string `json:"id"`
+	ClientInfo
+	// Addr is the remote address of the connection.
+	Addr        string    `json:"addr"`
+	ConnectedAt time.Time `json:"connected_at"`
+}
+
+// ListClients returns the connections open on the server with the
+// `list_clients` action. Clients that did not send ClientInfo have an empty
+// Name.
+func (c *RocksDBClient) ListClients() ([]ConnectedClient, error) {
+	response, err := c.SendRequest(Request{Action: ActionListClients, Options: map[string]string{}})
+	if err != nil {
+		return nil, err
+	}
+	var clients []ConnectedClient
+	if err := json.Unmarshal([]byte(response.Result), &clients); err != nil {
+		return nil, fmt.Errorf("error decoding list_clients result: %w", err)
+	}
+	return clients, nil
+}
+
+// DisconnectClient closes the server connection with the given ID, see
+// ListClients, with the `disconnect_client` admin action.
+func (c *RocksDBClient) DisconnectClient(id string) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionDisconnectClient,
+		Options: map[string]string{OptionClientID: id},
+	})
+	return err
+}
@@ -0,0 +1,24 @@
+package rocksdbclient
+
+import "encoding/json"
+
+// JSONCodec encodes requests and decodes responses on the wire. Drop-in
+// encoding/json replacements such as jsoniter and sonic satisfy it with
+// their standard-library compatible configurations.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type stdJSONCodec struct{}
+
+// Marshal encodes requests with the reflection-free Request encoder and
+// falls back to encoding/json for anything else.
+func (stdJSONCodec) Marshal(v any) ([]byte, error) {
+	if r, ok := v.(Request); ok {
+		return r.AppendJSON(make([]byte, 0, 128)), nil
+	}
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
@@ -0,0 +1,72 @@
+package rocksdbclient
+
+// ColumnFamily is a view of a client bound to one column family, so call
+// sites do not have to pass a *string column family name to every call.
+//
+//	users := client.CF("users")
+//	err := users.Put("u1", "alice")
+type ColumnFamily struct {
+	c    *RocksDBClient
+	name string
+}
+
+// CF returns a view of the client that operates on the column family name.
+// The view shares the client's connection.
+func (c *RocksDBClient) CF(name string) *ColumnFamily {
+	return &ColumnFamily{c: c, name: name}
+}
+
+// Name returns the column family name.
+func (cf *ColumnFamily) Name() string {
+	return cf.name
+}
+
+// Client returns the underlying client.
+func (cf *ColumnFamily) Client() *RocksDBClient {
+	return cf.c
+}
+
+// Get returns the value of key. Missing keys return an error matching
+// ErrKeyNotFound.
+func (cf *ColumnFamily) Get(key string) (string, error) {
+	response, err := cf.c.Get(&key, &cf.name, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
+
+// Put stores a key-value pair.
+func (cf *ColumnFamily) Put(key, value string) error {
+	_, err := cf.c.Put(&key, &value, &cf.name, nil)
+	return err
+}
+
+// Delete removes key.
+func (cf *ColumnFamily) Delete(key string) error {
+	_, err := cf.c.Delete(&key, &cf.name, nil)
+	return err
+}
+
+// Merge applies a JSON Patch merge operand to key.
+func (cf *ColumnFamily) Merge(key, value string) error {
+	_, err := cf.c.Merge(&key, &value, &cf.name, nil)
+	return err
+}
+
+// MultiGet fetches several keys in a single round trip, see
+// RocksDBClient.MultiGet.
+func (cf *ColumnFamily) MultiGet(keys []string) (map[string]*string, error) {
+	return cf.c.MultiGet(keys, &cf.name)
+}
+
+// DeleteRange removes the keys in [startKey, endKey).
+func (cf *ColumnFamily) DeleteRange(startKey, endKey string) error {
+	_, err := cf.c.DeleteRange(startKey, endKey, &cf.name)
+	return err
+}
+
+// NewIterator creates a server-side iterator over the column family.
+func (cf *ColumnFamily) NewIterator() (*Iterator, error) {
+	return cf.c.NewIteratorCF(&cf.name)
+}
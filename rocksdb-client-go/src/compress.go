@@ -0,0 +1,56 @@
+package rocksdbclient
+
+// defaultCompressionThreshold is the value size below which
+// CompressionTransformer leaves values uncompressed.
+const defaultCompressionThreshold = 1024
+
+// CompressionOptions configures NewCompressionTransformer.
+type CompressionOptions struct {
+	// Threshold is the minimum value size in bytes that gets compressed.
+	// Defaults to 1 KiB.
+	Threshold int
+}
+
+// CompressionTransformer is a ValueTransformer that snappy-compresses values
+// at or above a size threshold. Smaller values, and values that do not
+// shrink, are skipped and stored unchanged; compressed ones carry the
+// "snappy" name in the transformer header, so both kinds, as well as values
+// written before compression was enabled, read back correctly. The payload
+// is a standard snappy block, readable by other snappy implementations.
+//
+//	client.UseValueTransformers(rocksdbclient.NewCompressionTransformer(rocksdbclient.CompressionOptions{}))
+//
+// Other algorithms such as zstd can be plugged in by implementing
+// ValueTransformer around their encoders.
+type CompressionTransformer struct {
+	threshold int
+}
+
+// NewCompressionTransformer returns a snappy CompressionTransformer.
+func NewCompressionTransformer(opts CompressionOptions) *CompressionTransformer {
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaultCompressionThreshold
+	}
+	return &CompressionTransformer{threshold: opts.Threshold}
+}
+
+func (t *CompressionTransformer) Name() string {
+	return "snappy"
+}
+
+func (t *CompressionTransformer) Encode(value []byte) ([]byte, error) {
+	if len(value) < t.threshold {
+		return nil, ErrSkipTransform
+	}
+	compressed := snappyEncode(value)
+	// The transformer header and base64 add about a third, so only keep the
+	// compressed form when it pays for that.
+	if len(compressed)*4/3+len(transformedPrefix)+16 >= len(value) {
+		return nil, ErrSkipTransform
+	}
+	return compressed, nil
+}
+
+func (t *CompressionTransformer) Decode(value []byte) ([]byte, error) {
+	return snappyDecode(value)
+}
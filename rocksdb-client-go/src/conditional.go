@@ -0,0 +1,155 @@
+package rocksdbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// PutIfAbsent stores value under key only if the key does not exist yet and
+// reports whether the write happened. The check and the write are performed
+// atomically by the server with the `put_if_absent` action, unlike a
+// client-side Get followed by Put.
+func (c *RocksDBClient) PutIfAbsent(key, value string, cfName *string) (bool, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionPutIfAbsent,
+		Key:     &key,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	if err != nil {
+		return false, err
+	}
+	written, err := strconv.ParseBool(response.Result)
+	if err != nil {
+		return false, fmt.Errorf("error decoding put_if_absent result: %w", err)
+	}
+	return written, nil
+}
+
+// casResult is the result of the `compare_and_swap` action. Current is the
+// stored value when the swap did not happen, or nil if the key is missing.
+type casResult struct {
+	Swapped bool    `json:"swapped"`
+	Current *string `json:"current"`
+}
+
+// CAS atomically replaces the value of key with newValue if it currently
+// equals expected. A nil expected means the key must not exist. When the
+// values differ nothing is written and CAS returns false together with the
+// value actually stored (nil for a missing key), so callers can retry with
+// it as the new expectation.
+func (c *RocksDBClient) CAS(key string, expected *string, newValue string, cfName *string) (bool, *string, error) {
+	request := Request{
+		Action:  ActionCompareAndSwap,
+		Key:     &key,
+		Value:   &newValue,
+		CfName:  cfName,
+		Options: map[string]string{},
+	}
+	if expected != nil {
+		request.Options[OptionExpected] = *expected
+	}
+
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return false, nil, err
+	}
+	var result casResult
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+		return false, nil, fmt.Errorf("error decoding compare_and_swap result: %w", err)
+	}
+	if result.Swapped {
+		return true, &newValue, nil
+	}
+	return false, result.Current, nil
+}
+
+// GetOrCreateMany returns the values of keys, creating missing ones with
+// factory. Existing keys are fetched with one MultiGet; each missing key is
+// created with PutIfAbsent, so when another client creates it first its
+// value wins and is reported as existing instead. The two returned maps
+// together hold a value for every key.
+func (c *RocksDBClient) GetOrCreateMany(keys []string, factory func(key string) (string, error), cfName *string) (existing, created map[string]string, err error) {
+	values, err := c.MultiGet(keys, cfName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing = make(map[string]string, len(keys))
+	created = map[string]string{}
+	for _, key := range keys {
+		if value := values[key]; value != nil {
+			existing[key] = *value
+			continue
+		}
+		if _, done := created[key]; done {
+			continue
+		}
+
+		value, err := factory(key)
+		if err != nil {
+			return existing, created, fmt.Errorf("error creating %q: %w", key, err)
+		}
+		written, err := c.PutIfAbsent(key, value, cfName)
+		if err != nil {
+			return existing, created, err
+		}
+		if written {
+			created[key] = value
+			continue
+		}
+		response, err := c.Get(&key, cfName, nil, nil)
+		if err != nil {
+			return existing, created, err
+		}
+		existing[key] = response.Result
+	}
+	return existing, created, nil
+}
+
+// Upsert sets key to the value fn computes from the current one (nil when the
+// key is missing) and returns the value written. It reads the key and writes
+// with CAS, calling fn again with the newer value whenever another writer got
+// in between, so fn must be free of side effects. Retries back off and are
+// capped by the client's transaction retry policy; once it is exhausted the
+// upsert fails with ErrTxnConflict. An error from fn aborts the upsert
+// without writing. It is a simpler alternative to JSON Patch merges
+// when the update is easier to express in Go.
+func (c *RocksDBClient) Upsert(key string, fn func(existing *string) (string, error), cfName *string) (string, error) {
+	response, err := c.Get(&key, cfName, nil, nil)
+	var current *string
+	switch {
+	case err == nil:
+		current = &response.Result
+	case !errors.Is(err, ErrKeyNotFound):
+		return "", err
+	}
+
+	c.mu.Lock()
+	policy := c.txnRetry
+	c.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		value, err := fn(current)
+		if err != nil {
+			return "", err
+		}
+		swapped, actual, err := c.CAS(key, current, value, cfName)
+		if err != nil {
+			return "", err
+		}
+		if swapped {
+			return value, nil
+		}
+		if attempt >= policy.MaxRetries {
+			return "", fmt.Errorf("%w: upsert of %q lost %d races", ErrTxnConflict, key, attempt+1)
+		}
+		if err := policy.wait(context.Background(), attempt); err != nil {
+			return "", err
+		}
+		current = actual
+	}
+}
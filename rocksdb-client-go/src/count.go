@@ -0,0 +1,92 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// EstimateNumKeys returns RocksDB's estimate of the number of keys in a
+// column family (the default one when cfName is nil), read from the
+// rocksdb.estimate-num-keys property. It is cheap but can be off
+// considerably, e.g. after many overwrites or deletes.
+func (c *RocksDBClient) EstimateNumKeys(cfName *string) (int64, error) {
+	property := "rocksdb.estimate-num-keys"
+	response, err := c.GetProperty(&property, cfName)
+	if err != nil {
+		return 0, err
+	}
+	estimate, err := strconv.ParseInt(response.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding %s: %w", property, err)
+	}
+	return estimate, nil
+}
+
+// CountKeys returns the exact number of keys in r, counted by the server
+// with the `count_keys` action without transferring them.
+func (c *RocksDBClient) CountKeys(r KeyRange, cfName *string) (int64, error) {
+	request := Request{
+		Action:  ActionCountKeys,
+		CfName:  cfName,
+		Options: map[string]string{OptionStart: r.Start},
+	}
+	if r.End != "" {
+		request.Options[OptionEnd] = r.End
+	}
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.ParseInt(response.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding count_keys result: %w", err)
+	}
+	return count, nil
+}
+
+// CountPrefix returns the exact number of keys starting with prefix.
+func (c *RocksDBClient) CountPrefix(prefix string, cfName *string) (int64, error) {
+	return c.CountKeys(KeyRange{Start: prefix, End: prefixEnd(prefix)}, cfName)
+}
+
+// sizeRange is the wire format of a range in `get_approximate_sizes`.
+type sizeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end,omitempty"`
+}
+
+// ApproximateSizes returns the approximate on-disk size in bytes of each
+// range, as computed by RocksDB's GetApproximateSizes, in one
+// `get_approximate_sizes` request.
+func (c *RocksDBClient) ApproximateSizes(ranges []KeyRange, cfName *string) ([]uint64, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+	wire := make([]sizeRange, len(ranges))
+	for i, r := range ranges {
+		wire[i] = sizeRange{Start: r.Start, End: r.End}
+	}
+	data, err := json.Marshal(wire)
+	if err != nil {
+		return nil, err
+	}
+	value := string(data)
+	response, err := c.SendRequest(Request{
+		Action:  ActionGetApproximateSizes,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sizes []uint64
+	if err := json.Unmarshal([]byte(response.Result), &sizes); err != nil {
+		return nil, fmt.Errorf("error decoding get_approximate_sizes result: %w", err)
+	}
+	if len(sizes) != len(ranges) {
+		return nil, fmt.Errorf("get_approximate_sizes returned %d sizes for %d ranges", len(sizes), len(ranges))
+	}
+	return sizes, nil
+}
@@ -0,0 +1,33 @@
+package rocksdbclient
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Incr atomically adds delta to the integer stored under key and returns the
+// new value. A missing key counts as 0. The addition is done by the server's
+// numeric merge operator with the `incr` action, so concurrent increments
+// from several clients are never lost.
+func (c *RocksDBClient) Incr(key string, delta int64, cfName *string) (int64, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionIncr,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{OptionDelta: strconv.FormatInt(delta, 10)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(response.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding incr result: %w", err)
+	}
+	return value, nil
+}
+
+// Decr atomically subtracts delta from the integer stored under key and
+// returns the new value.
+func (c *RocksDBClient) Decr(key string, delta int64, cfName *string) (int64, error) {
+	return c.Incr(key, -delta, cfName)
+}
@@ -0,0 +1,74 @@
+package rocksdbclient
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// CallStats is per-request debug metadata reported to the hook installed
+// with SetDebugHook.
+type CallStats struct {
+	Action string
+	// Caller is the file:line of the first stack frame outside this package.
+	Caller string
+	// RequestBytes and ResponseBytes are the sizes of the frames on the
+	// wire: the encoded message plus its newline delimiter or length prefix.
+	RequestBytes  int
+	ResponseBytes int
+	// Allocs and AllocBytes are the heap allocations observed while the
+	// request was in flight. They are process-wide counters, so allocations
+	// from other goroutines are included.
+	Allocs     uint64
+	AllocBytes uint64
+	Duration   time.Duration
+
+	start      time.Time
+	mallocs    uint64
+	totalAlloc uint64
+}
+
+// SetDebugHook reports CallStats for every request to hook, or disables
+// reporting when hook is nil. Collecting allocation stats stops the world
+// twice per request, so this is meant for profiling sessions only. The hook
+// runs while the connection is held and must not use the client.
+func (c *RocksDBClient) SetDebugHook(hook func(CallStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debugHook = hook
+}
+
+const packagePath = "github.com/s00d/RocksDBFusion/rocksdb-client-go/src."
+
+func startCallTrace(action string) *CallStats {
+	trace := &CallStats{Action: action, Caller: externalCaller()}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	trace.mallocs, trace.totalAlloc = mem.Mallocs, mem.TotalAlloc
+	trace.start = time.Now()
+	return trace
+}
+
+func (t *CallStats) finish() CallStats {
+	t.Duration = time.Since(t.start)
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	t.Allocs = mem.Mallocs - t.mallocs
+	t.AllocBytes = mem.TotalAlloc - t.totalAlloc
+	return *t
+}
+
+func externalCaller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
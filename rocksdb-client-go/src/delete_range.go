@@ -0,0 +1,21 @@
+package rocksdbclient
+
+import "fmt"
+
+// DeleteRange removes every key in [startKey, endKey) with a single
+// `delete_range` request, mapped to RocksDB's DeleteRange on the server.
+func (c *RocksDBClient) DeleteRange(startKey, endKey string, cfName *string) (*Response, error) {
+	if startKey >= endKey {
+		return nil, fmt.Errorf("invalid range: start key %q must sort before end key %q", startKey, endKey)
+	}
+
+	request := Request{
+		Action: ActionDeleteRange,
+		Options: map[string]string{
+			OptionStart: startKey,
+			OptionEnd:   endKey,
+		},
+		CfName: cfName,
+	}
+	return c.SendRequest(request)
+}
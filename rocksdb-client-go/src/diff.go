@@ -0,0 +1,71 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VersionedDoc is a JSON document together with the server's version hash
+// of it, as returned by GetDiff.
+type VersionedDoc struct {
+	Value json.RawMessage
+	// Hash identifies the stored version on the server.
+	Hash string
+	// Patched reports whether Value was rebuilt from a patch rather than
+	// transferred in full.
+	Patched bool
+}
+
+// diffResult is the result of a get with the "diff" or "diff_base" option:
+// either the full value or a patch against the base version.
+type diffResult struct {
+	Value *string       `json:"value"`
+	Patch []jsonPatchOp `json:"patch"`
+	Hash  string        `json:"hash"`
+}
+
+// GetDiff reads the JSON document stored under key. With a nil base the full
+// document is fetched; passing the VersionedDoc of an earlier call lets the
+// server answer with an RFC 6902 patch against that version, which GetDiff
+// applies locally. This keeps the transfer small for large documents that
+// are polled often. If the server no longer knows the base version it sends
+// the full document instead.
+func (c *RocksDBClient) GetDiff(key string, base *VersionedDoc, cfName *string) (*VersionedDoc, error) {
+	request := Request{
+		Action:  ActionGet,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{OptionDiff: "true"},
+	}
+	if base != nil {
+		request.Options[OptionDiffBase] = base.Hash
+	}
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var result diffResult
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+		return nil, fmt.Errorf("error decoding diff result: %w", err)
+	}
+	if result.Value != nil {
+		return &VersionedDoc{Value: json.RawMessage(*result.Value), Hash: result.Hash}, nil
+	}
+	if base == nil {
+		return nil, fmt.Errorf("diff result for %q has neither value nor base", key)
+	}
+
+	var doc any
+	if err := json.Unmarshal(base.Value, &doc); err != nil {
+		return nil, fmt.Errorf("error decoding base document: %w", err)
+	}
+	if doc, err = applyJSONPatch(doc, result.Patch); err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionedDoc{Value: value, Hash: result.Hash, Patched: true}, nil
+}
@@ -0,0 +1,158 @@
+package rocksdbclient
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MarshalJSON encodes the request without reflection. Fields are always
+// written in declaration order and options are sorted by key, so equal
+// requests produce byte-identical output.
+func (r Request) MarshalJSON() ([]byte, error) {
+	return r.AppendJSON(make([]byte, 0, 128)), nil
+}
+
+// AppendJSON appends the canonical JSON encoding of r to buf.
+func (r Request) AppendJSON(buf []byte) []byte {
+	buf = append(buf, `{"action":`...)
+	buf = appendJSONString(buf, r.Action)
+	buf = appendOptionalString(buf, "key", r.Key)
+	buf = appendOptionalString(buf, "value", r.Value)
+	buf = appendOptionalString(buf, "cf_name", r.CfName)
+	buf = appendOptionalString(buf, "default_value", r.DefaultValue)
+	if len(r.Options) > 0 {
+		keys := make([]string, 0, len(r.Options))
+		for k := range r.Options {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf = append(buf, `,"options":{`...)
+		for i, k := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, k)
+			buf = append(buf, ':')
+			buf = appendJSONString(buf, r.Options[k])
+		}
+		buf = append(buf, '}')
+	}
+	buf = appendOptionalString(buf, "token", r.Token)
+	if r.Txn != nil {
+		buf = append(buf, `,"txn":`...)
+		buf = strconv.AppendBool(buf, *r.Txn)
+	}
+	buf = appendOptionalString(buf, "txn_id", r.TxnID)
+	if len(r.Keys) > 0 {
+		buf = append(buf, `,"keys":[`...)
+		for i, k := range r.Keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, k)
+		}
+		buf = append(buf, ']')
+	}
+	if len(r.Operations) > 0 {
+		buf = append(buf, `,"operations":[`...)
+		for i, op := range r.Operations {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = op.appendJSON(buf)
+		}
+		buf = append(buf, ']')
+	}
+	if r.RequestID != "" {
+		buf = append(buf, `,"request_id":`...)
+		buf = appendJSONString(buf, r.RequestID)
+	}
+	return append(buf, '}')
+}
+
+// Hash returns a SHA-256 digest of the canonical encoding with the auth token
+// and pipelining request ID left out, suitable as an idempotency key or for
+// request signing.
+func (r Request) Hash() [sha256.Size]byte {
+	r.Token = nil
+	r.RequestID = ""
+	return sha256.Sum256(r.AppendJSON(nil))
+}
+
+// MarshalJSON encodes the operation without reflection.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	return op.appendJSON(nil), nil
+}
+
+func (op Operation) appendJSON(buf []byte) []byte {
+	buf = append(buf, `{"type":`...)
+	buf = appendJSONString(buf, string(op.Type))
+	buf = append(buf, `,"key":`...)
+	buf = appendJSONString(buf, op.Key)
+	buf = appendOptionalString(buf, "value", op.Value)
+	buf = appendOptionalString(buf, "cf_name", op.CfName)
+	return append(buf, '}')
+}
+
+func appendOptionalString(buf []byte, name string, value *string) []byte {
+	if value == nil {
+		return buf
+	}
+	buf = append(buf, ',', '"')
+	buf = append(buf, name...)
+	buf = append(buf, '"', ':')
+	return appendJSONString(buf, *value)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString quotes s the way encoding/json does, including HTML-safe
+// escaping and replacement of invalid UTF-8.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
@@ -0,0 +1,81 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"errors"
+	"net"
+)
+
+// Endpoints returns the server addresses the client may connect to.
+func (c *RocksDBClient) Endpoints() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.endpoints...)
+}
+
+// SetEndpoints replaces the server addresses (host:port) of the client.
+// It waits for the in-flight request to complete, so no request is cut off.
+// If the current endpoint is still listed the connection is kept; otherwise
+// a connection to the first new endpoint is established before the old one
+// is closed. On error the previous endpoints stay in effect.
+func (c *RocksDBClient) SetEndpoints(addrs []string) error {
+	if len(addrs) == 0 {
+		return errors.New("at least one endpoint is required")
+	}
+	for _, addr := range addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := c.endpoints[c.endpoint]
+	for i, addr := range addrs {
+		if addr == current {
+			c.endpoints, c.endpoint = append([]string(nil), addrs...), i
+			return nil
+		}
+	}
+
+	if c.conn != nil {
+		conn, err := c.dialAddr(addrs[0])
+		if err != nil {
+			return err
+		}
+		c.closeConn()
+		c.conn, c.reader = conn, bufio.NewReader(conn)
+		if err := c.handshake(); err != nil {
+			c.closeConn()
+			return err
+		}
+	}
+	c.endpoints, c.endpoint = append([]string(nil), addrs...), 0
+	return nil
+}
+
+// SetEndpoints rolls every client of the pool over to addrs. Clients are
+// spread across the endpoints round-robin; each one switches as soon as its
+// current request completes.
+func (p *Pool) SetEndpoints(addrs []string) error {
+	if len(addrs) == 0 {
+		return errors.New("at least one endpoint is required")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endpoints = append([]string(nil), addrs...)
+	for i, c := range p.all {
+		if err := c.SetEndpoints(rotate(addrs, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotate returns addrs starting at position n, wrapping around.
+func rotate(addrs []string, n int) []string {
+	n %= len(addrs)
+	return append(append([]string(nil), addrs[n:]...), addrs[:n]...)
+}
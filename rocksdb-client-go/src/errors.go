@@ -0,0 +1,92 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Sentinel errors returned by the client. Server failures are classified by
+// their message; use errors.Is to test for them and errors.As with
+// *ServerError to get the raw message.
+var (
+	ErrKeyNotFound      = errors.New("key not found")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrTxnConflict      = errors.New("transaction conflict")
+	ErrIteratorInvalid  = errors.New("iterator invalid")
+	ErrTimeout          = errors.New("request timed out")
+	ErrLockHeld         = errors.New("lock is held")
+	ErrFenced           = errors.New("stale fencing token")
+	ErrLeaseExpired     = errors.New("lease expired")
+	ErrMaintenance      = errors.New("server in maintenance mode")
+	ErrCorruption       = errors.New("data corruption")
+	ErrReplicaBehind    = errors.New("replica behind")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrUnsupportedAction is returned for actions the server does not
+	// know, either reported by the server or, with WithCapabilityCheck,
+	// detected before the request is sent.
+	ErrUnsupportedAction = errors.New("action not supported by server")
+)
+
+// ServerError is returned when the server answers with success=false.
+type ServerError struct {
+	// Action is the protocol action of the failed request.
+	Action string
+	// Message is the error text sent by the server.
+	Message string
+	// Err is the sentinel the message was classified as, or nil.
+	Err error
+}
+
+func (e *ServerError) Error() string {
+	return "server error: " + e.Message
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Err
+}
+
+// serverErrorPatterns maps fragments of server and RocksDB status messages to
+// sentinels. Order matters: the first match wins.
+var serverErrorPatterns = []struct {
+	fragment string
+	err      error
+}{
+	{"Unauthorized", ErrUnauthorized},
+	{"Key not found", ErrKeyNotFound},
+	{"Iterator ID not found", ErrIteratorInvalid},
+	{"Lock is held", ErrLockHeld},
+	{"Stale fencing token", ErrFenced},
+	{"Lease not found", ErrLeaseExpired},
+	{"Maintenance mode", ErrMaintenance},
+	{"Corruption", ErrCorruption},
+	{"Replica behind", ErrReplicaBehind},
+	{"Snapshot not found", ErrSnapshotNotFound},
+	{"Unknown action", ErrUnsupportedAction},
+	{"Resource busy", ErrTxnConflict},
+	{"Deadlock", ErrTxnConflict},
+	{"Timeout waiting to lock key", ErrTxnConflict},
+	{"Operation failed. Try again.", ErrTxnConflict},
+}
+
+func newServerError(action, message string) *ServerError {
+	serverErr := &ServerError{Action: action, Message: message}
+	for _, pattern := range serverErrorPatterns {
+		if strings.Contains(message, pattern.fragment) {
+			serverErr.Err = pattern.err
+			break
+		}
+	}
+	return serverErr
+}
+
+// wrapTimeout marks network timeouts with ErrTimeout while keeping the
+// underlying error in the chain.
+func wrapTimeout(action string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %s: %w", ErrTimeout, action, err)
+	}
+	return err
+}
@@ -0,0 +1,260 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ExportFormat selects the encoding of an export stream.
+type ExportFormat string
+
+const (
+	// ExportNDJSON writes one {"key": ..., "value": ...} object per line.
+	ExportNDJSON ExportFormat = "ndjson"
+	// ExportBinary writes a header followed by length-prefixed records, each
+	// a uvarint key length, the key, a uvarint value length and the value.
+	// It is more compact and handles values that are not valid UTF-8.
+	ExportBinary ExportFormat = "binary"
+)
+
+// exportBinaryMagic starts every ExportBinary stream, so Import can tell the
+// formats apart.
+const exportBinaryMagic = "RDBX\x00\x01"
+
+// maxExportFieldSize bounds the length prefixes Import accepts, so a corrupt
+// stream fails instead of allocating huge buffers.
+const maxExportFieldSize = 256 << 20
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Prefix restricts the export to keys starting with it.
+	Prefix string
+	// CF is the column family to export; empty means the default one.
+	CF string
+	// Format defaults to ExportNDJSON.
+	Format ExportFormat
+	// Cursor resumes an interrupted export after the last entry it wrote;
+	// it is the ExportResult.Cursor of that export, made with the same
+	// Prefix and CF.
+	Cursor string
+	// PageSize is the number of entries read per page. Defaults to 1000.
+	PageSize int
+	// ChunkSize is the number of entries per data file of an ExportArchive.
+	// Defaults to 10000.
+	ChunkSize int
+	// TempDir is where an ExportArchive is staged before it is written;
+	// os.TempDir() is used when empty.
+	TempDir string
+}
+
+// ExportResult reports the progress of Export.
+type ExportResult struct {
+	// Count is the number of entries written.
+	Count int64
+	// Cursor is set when Export failed part way through and resumes right
+	// after the last page written to w.
+	Cursor string
+	// Seq is the last sequence number covered by an ExportSince archive.
+	Seq uint64
+}
+
+// Export streams the entries selected by opts to w in key order. Entries
+// are written one page at a time; if Export fails it returns the partial
+// result along with the error, and passing result.Cursor in a new export to
+// the same w continues where it stopped. A resumed ExportBinary export does
+// not repeat the header. ExportArchive exports are written as a whole once
+// all entries were read and cannot be resumed.
+func (c *RocksDBClient) Export(w io.Writer, opts ExportOptions) (*ExportResult, error) {
+	switch opts.Format {
+	case "":
+		opts.Format = ExportNDJSON
+	case ExportNDJSON, ExportBinary:
+	case ExportArchive:
+		return c.exportArchive(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	return c.exportEntries(w, opts)
+}
+
+func (c *RocksDBClient) exportEntries(w io.Writer, opts ExportOptions) (*ExportResult, error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 1000
+	}
+	scan := ScanOptions{Prefix: opts.Prefix, CF: opts.CF, Limit: opts.PageSize}
+	result := &ExportResult{Cursor: opts.Cursor}
+
+	var buf bytes.Buffer
+	if opts.Format == ExportBinary && opts.Cursor == "" {
+		buf.WriteString(exportBinaryMagic)
+	}
+	for {
+		page, err := c.ScanPage(scan, result.Cursor)
+		if err != nil {
+			return result, err
+		}
+		for _, entry := range page.Items {
+			if err := appendExportEntry(&buf, opts.Format, entry); err != nil {
+				return result, err
+			}
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return result, fmt.Errorf("error writing export: %w", err)
+		}
+		buf.Reset()
+		result.Count += int64(len(page.Items))
+		result.Cursor = page.Next
+		if !page.HasMore() {
+			return result, nil
+		}
+	}
+}
+
+func appendExportEntry(buf *bytes.Buffer, format ExportFormat, entry KeyValue) error {
+	if format == ExportBinary {
+		buf.Write(binary.AppendUvarint(nil, uint64(len(entry.Key))))
+		buf.WriteString(entry.Key)
+		buf.Write(binary.AppendUvarint(nil, uint64(len(entry.Value))))
+		buf.WriteString(entry.Value)
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding export entry: %w", err)
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// ImportOptions configures ImportWith.
+type ImportOptions struct {
+	// CF is the column family to import into; empty means the default one.
+	CF string
+	// BatchSize is the number of entries written per batch_write. Defaults
+	// to 1000.
+	BatchSize int
+}
+
+// Import reads an export stream in either format from r and writes its
+// entries to the default column family. See ImportWith.
+func (c *RocksDBClient) Import(r io.Reader) (int64, error) {
+	return c.ImportWith(r, ImportOptions{})
+}
+
+// ImportWith reads an export stream from r, detecting its format, and puts
+// its entries in batches; incremental archives are replayed in order. The
+// chunks of an ExportArchive are verified before they are written. It
+// returns the number of entries written; each batch is atomic, but an import
+// that fails part way leaves the earlier batches in place. Importing the
+// same stream again is harmless unless it is an incremental archive holding
+// merges.
+func (c *RocksDBClient) ImportWith(r io.Reader, opts ImportOptions) (int64, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	var cfName *string
+	if opts.CF != "" {
+		cfName = &opts.CF
+	}
+	batch := c.NewWriteBatch(WriteBatchOptions{MaxOps: opts.BatchSize})
+
+	var count int64
+	apply := func(event ChangeEvent) error {
+		if event.Key == "" {
+			return errors.New("error decoding export entry: empty key")
+		}
+		if event.Type != OpDelete && event.Value == nil {
+			return fmt.Errorf("error decoding export entry: %s of %q without value", event.Type, event.Key)
+		}
+		count++
+		switch event.Type {
+		case OpPut:
+			return batch.Put(event.Key, *event.Value, cfName)
+		case OpMerge:
+			return batch.Merge(event.Key, *event.Value, cfName)
+		case OpDelete:
+			return batch.Delete(event.Key, cfName)
+		}
+		count--
+		return fmt.Errorf("error decoding export entry: unknown type %q", event.Type)
+	}
+	put := func(entry KeyValue) error {
+		return apply(ChangeEvent{Type: OpPut, Key: entry.Key, Value: &entry.Value})
+	}
+	reader := bufio.NewReader(r)
+	var err error
+	if magic, _ := reader.Peek(len(exportBinaryMagic)); string(magic) == exportBinaryMagic {
+		reader.Discard(len(magic))
+		err = readBinaryExport(reader, put)
+	} else if isArchive(reader) {
+		_, err = readArchiveExport(reader, apply)
+	} else {
+		err = readNDJSONExport(reader, put)
+	}
+	if err == nil {
+		err = batch.Commit()
+	}
+	// Entries still buffered in the batch were not written.
+	return count - int64(batch.Len()), err
+}
+
+func readNDJSONExport(r *bufio.Reader, fn func(KeyValue) error) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var entry KeyValue
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error decoding export entry: %w", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+func readBinaryExport(r *bufio.Reader, fn func(KeyValue) error) error {
+	for {
+		key, err := readExportField(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		value, err := readExportField(r)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if err := fn(KeyValue{Key: key, Value: value}); err != nil {
+			return err
+		}
+	}
+}
+
+func readExportField(r *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("error decoding export entry: %w", err)
+		}
+		return "", err
+	}
+	if n > maxExportFieldSize {
+		return "", errors.New("error decoding export entry: field too large")
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", fmt.Errorf("error decoding export entry: %w", io.ErrUnexpectedEOF)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,178 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+// defaultProbeInterval is how often a failed-over client checks whether its
+// primary is reachable again.
+const defaultProbeInterval = 5 * time.Second
+
+// FailoverOptions configures failover to standby servers, e.g. the second
+// node of a keepalived pair.
+type FailoverOptions struct {
+	// Standbys are the addresses (host:port) tried in order after the
+	// primary, which is the address the client was created with.
+	Standbys []string
+	// ProbeInterval is how often the primary is probed while the client is
+	// connected to a standby. Defaults to 5s; a negative value disables
+	// failing back.
+	ProbeInterval time.Duration
+}
+
+// WithFailover enables failover to standby servers, see SetFailover.
+func WithFailover(opts FailoverOptions) Option {
+	return func(c *RocksDBClient) {
+		c.endpoints = append(c.endpoints[:1:1], opts.Standbys...)
+		c.failover = &opts
+	}
+}
+
+// SetFailover enables failover to opts.Standbys. When a connection to the
+// current server fails, the request returns the error and the next request
+// connects to the following endpoint, trying them all in order until one
+// answers. Requests that timed out do not trigger a failover, since the
+// server may only be slow. While on a standby the primary is probed in the
+// background and the client fails back once it accepts connections again.
+//
+// Failed requests are not retried, because a write may have been applied
+// before the connection broke.
+func (c *RocksDBClient) SetFailover(opts FailoverOptions) error {
+	for _, addr := range opts.Standbys {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := c.endpoints[c.endpoint]
+	c.endpoints = append(c.endpoints[:1:1], opts.Standbys...)
+	c.failover = &opts
+	c.endpoint = slices.Index(c.endpoints, current)
+	if c.endpoint < 0 {
+		c.closeConn()
+		c.endpoint = 0
+	}
+	if c.endpoint == 0 {
+		c.stopProbe()
+	}
+	return nil
+}
+
+// CurrentEndpoint returns the address of the server the client uses.
+func (c *RocksDBClient) CurrentEndpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endpoints[c.endpoint]
+}
+
+// dialEndpoint connects to the current endpoint. With failover enabled it
+// tries every endpoint in turn, starting at the current one, until one
+// accepts the connection or the connect timeout passes.
+func (c *RocksDBClient) dialEndpoint() (net.Conn, error) {
+	if c.failover == nil || len(c.endpoints) == 1 {
+		return c.dialAddr(c.endpoints[c.endpoint])
+	}
+	start := time.Now()
+	for {
+		var errs []error
+		for i := range c.endpoints {
+			n := (c.endpoint + i) % len(c.endpoints)
+			conn, err := c.dialOnce(c.endpoints[n])
+			if err == nil {
+				c.switchEndpoint(n)
+				return conn, nil
+			}
+			c.log().Debug("connect failed, trying next endpoint", "addr", c.endpoints[n], "error", err)
+			errs = append(errs, err)
+		}
+		if time.Since(start) >= c.timeout {
+			return nil, fmt.Errorf("unable to connect to server: %w", errors.Join(errs...))
+		}
+		time.Sleep(c.retryInterval)
+	}
+}
+
+// resetConn closes a connection that failed and, with failover enabled,
+// moves on to the next endpoint unless the failure was a timeout.
+func (c *RocksDBClient) resetConn(err error) {
+	c.closeConn()
+	var netErr net.Error
+	if c.failover == nil || len(c.endpoints) == 1 || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return
+	}
+	c.switchEndpoint((c.endpoint + 1) % len(c.endpoints))
+}
+
+func (c *RocksDBClient) switchEndpoint(n int) {
+	if n == c.endpoint {
+		return
+	}
+	c.log().Warn("failing over", "from", c.endpoints[c.endpoint], "to", c.endpoints[n])
+	c.endpoint = n
+	if n == 0 {
+		c.stopProbe()
+	} else {
+		c.startProbe()
+	}
+}
+
+func (c *RocksDBClient) startProbe() {
+	if c.probeStop != nil || c.failover.ProbeInterval < 0 {
+		return
+	}
+	interval := c.failover.ProbeInterval
+	if interval == 0 {
+		interval = defaultProbeInterval
+	}
+	stop := make(chan struct{})
+	c.probeStop = stop
+	go c.probe(stop, interval)
+}
+
+func (c *RocksDBClient) stopProbe() {
+	if c.probeStop != nil {
+		close(c.probeStop)
+		c.probeStop = nil
+	}
+}
+
+// probe dials the primary every interval and, once it accepts a connection,
+// drops the standby connection so the next request goes to the primary.
+func (c *RocksDBClient) probe(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		c.mu.Lock()
+		primary := c.endpoints[0]
+		c.mu.Unlock()
+
+		conn, err := c.dialOnce(primary)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+
+		c.mu.Lock()
+		if c.probeStop == stop {
+			c.probeStop = nil
+			if c.endpoint != 0 && c.endpoints[0] == primary {
+				c.log().Warn("failing back", "from", c.endpoints[c.endpoint], "to", primary)
+				c.closeConn()
+				c.endpoint = 0
+			}
+		}
+		c.mu.Unlock()
+		return
+	}
+}
@@ -0,0 +1,27 @@
+package rocksdbclient
+
+import "strconv"
+
+// Flush writes the memtables of a column family (the default one when cfName
+// is nil) to SST files with the `flush` action and waits for it to finish.
+// Flushing before a backup or shutdown keeps the WAL short and makes the
+// backup independent of WAL replay.
+func (c *RocksDBClient) Flush(cfName *string) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionFlush,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	return err
+}
+
+// FlushWAL writes buffered WAL records to the log file with the `flush_wal`
+// action. With sync set the file is also fsynced, so every acknowledged write
+// survives a machine crash.
+func (c *RocksDBClient) FlushWAL(sync bool) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionFlushWAL,
+		Options: map[string]string{OptionSync: strconv.FormatBool(sync)},
+	})
+	return err
+}
@@ -0,0 +1,151 @@
+package rocksdbclient
+
+import (
+	"sync"
+	"time"
+)
+
+// GroupCommitOptions configures EnableGroupCommit.
+type GroupCommitOptions struct {
+	// MaxDelay is how long the first writer of a group waits for others to
+	// join before the group is flushed. Defaults to 1ms.
+	MaxDelay time.Duration
+	// MaxBatch flushes a group early once it holds this many writes.
+	// Defaults to 128.
+	MaxBatch int
+}
+
+// PutSync writes a key-value pair and asks the server to fsync the write
+// ahead log before acknowledging it.
+func (c *RocksDBClient) PutSync(key, value string, cfName *string) (*Response, error) {
+	return c.SendRequest(Request{
+		Action:  ActionPut,
+		Key:     &key,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{OptionSync: "true"},
+	})
+}
+
+// DeleteSync deletes a key and asks the server to fsync the write ahead log
+// before acknowledging it.
+func (c *RocksDBClient) DeleteSync(key string, cfName *string) (*Response, error) {
+	return c.SendRequest(Request{
+		Action:  ActionDelete,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{OptionSync: "true"},
+	})
+}
+
+// EnableGroupCommit batches sync writes issued concurrently from several
+// goroutines into a single fsync'd `batch_write`, so the server pays for one
+// WAL sync per group instead of one per write. Each caller still blocks
+// until its write is durable. Writes in a group succeed or fail together.
+func (c *RocksDBClient) EnableGroupCommit(opts GroupCommitOptions) {
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = time.Millisecond
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = 128
+	}
+	gc := &groupCommitter{opts: opts}
+	c.Use(gc.intercept)
+}
+
+type commitGroup struct {
+	requests []Request
+	full     chan struct{}
+	done     chan struct{}
+	// response is the server's response to a group of one.
+	response *Response
+	err      error
+}
+
+type groupCommitter struct {
+	opts GroupCommitOptions
+
+	mu      sync.Mutex
+	pending *commitGroup
+}
+
+// isSyncWrite reports whether request can join a group. Writes with options
+// other than sync, e.g. a TTL or a fencing token, cannot be expressed as a
+// batch operation and are sent on their own, as are transaction writes and
+// writes with their own timeout, which the group's request would not honor.
+func isSyncWrite(request Request) bool {
+	if len(request.Options) != 1 || request.Options[OptionSync] != "true" || request.Key == nil {
+		return false
+	}
+	if (request.Txn != nil && *request.Txn) || request.TxnID != nil || request.Timeout > 0 {
+		return false
+	}
+	switch request.Action {
+	case ActionPut, ActionMerge:
+		return request.Value != nil
+	case ActionDelete:
+		return true
+	}
+	return false
+}
+
+func (gc *groupCommitter) intercept(request Request, next Handler) (*Response, error) {
+	if !isSyncWrite(request) {
+		return next(request)
+	}
+
+	gc.mu.Lock()
+	group, leader := gc.pending, false
+	if group == nil {
+		group = &commitGroup{full: make(chan struct{}), done: make(chan struct{})}
+		gc.pending, leader = group, true
+	}
+	group.requests = append(group.requests, request)
+	if len(group.requests) == gc.opts.MaxBatch {
+		gc.pending = nil
+		close(group.full)
+	}
+	gc.mu.Unlock()
+
+	if leader {
+		gc.flush(group, next)
+	}
+	<-group.done
+	if group.err != nil {
+		return nil, group.err
+	}
+	if group.response != nil {
+		return group.response, nil
+	}
+	return &Response{Success: true}, nil
+}
+
+func (gc *groupCommitter) flush(group *commitGroup, next Handler) {
+	timer := time.NewTimer(gc.opts.MaxDelay)
+	select {
+	case <-timer.C:
+	case <-group.full:
+		timer.Stop()
+	}
+
+	gc.mu.Lock()
+	if gc.pending == group {
+		gc.pending = nil
+	}
+	gc.mu.Unlock()
+
+	if len(group.requests) == 1 {
+		group.response, group.err = next(group.requests[0])
+	} else {
+		ops := make([]Operation, len(group.requests))
+		for i, r := range group.requests {
+			ops[i] = Operation{Type: OperationType(r.Action), Key: *r.Key, Value: r.Value, CfName: r.CfName}
+		}
+		_, group.err = next(Request{
+			Action:     ActionBatchWrite,
+			Operations: ops,
+			Options:    map[string]string{OptionSync: "true"},
+		})
+	}
+	close(group.done)
+}
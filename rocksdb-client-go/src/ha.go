@@ -0,0 +1,232 @@
+package rocksdbclient
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HAEventType is the kind of an HAEvent.
+type HAEventType string
+
+const (
+	// HAPrimaryDown is emitted when the primary fails the health policy.
+	HAPrimaryDown HAEventType = "primary_down"
+	// HAPromoted is emitted when the standby takes over writes.
+	HAPromoted HAEventType = "promoted"
+	// HAPromotionBlocked is emitted instead of HAPromoted when the standby
+	// lags behind the primary by more than HAOptions.MaxLag, or cannot be
+	// reached either. The check is repeated on every probe.
+	HAPromotionBlocked HAEventType = "promotion_blocked"
+)
+
+// HAEvent reports a change of an HAClient.
+type HAEvent struct {
+	Type HAEventType
+	Time time.Time
+	// PrimarySeq is the last sequence number observed on the primary and
+	// StandbySeq the standby's at the time of the event.
+	PrimarySeq uint64
+	StandbySeq uint64
+	// Err is the last primary probe error for HAPrimaryDown, or why the
+	// promotion was blocked.
+	Err error
+}
+
+// HAOptions configures an HAClient. Zero values mean the defaults.
+type HAOptions struct {
+	// Interval is how often both servers are probed. Defaults to 1s.
+	Interval time.Duration
+	// ProbeTimeout bounds each probe. Defaults to Interval.
+	ProbeTimeout time.Duration
+	// FailureThreshold is how many consecutive probes the primary must fail
+	// before the standby is promoted. Defaults to 3.
+	FailureThreshold int
+	// MaxLag is how many sequence numbers the standby may be behind the last
+	// one observed on the primary and still be promoted; the default of
+	// zero requires it to have caught up completely.
+	MaxLag uint64
+	// OnEvent is called from the monitoring goroutine for every event. It
+	// must not block.
+	OnEvent func(HAEvent)
+	// ClientOptions are applied to the standby client after the settings
+	// copied from the primary (token, timeouts, TLS and codec).
+	ClientOptions []Option
+}
+
+// HAClient pairs a primary with a warm standby that replicates from it. It
+// keeps a session open to both servers, probes them with
+// get_latest_sequence_number to track the replication lag, and once the
+// primary failed HAOptions.FailureThreshold probes in a row promotes the
+// standby: from then on every request of the primary client, writes
+// included, is sent to the standby. Promotion is final, as the old primary
+// may have missed writes made meanwhile; create a new HAClient once it was
+// resynchronized.
+type HAClient struct {
+	primary *RocksDBClient
+	standby *RocksDBClient
+	opts    HAOptions
+
+	mu         sync.Mutex
+	promoted   bool
+	failures   int
+	primarySeq uint64
+	standbySeq uint64
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewHAClient creates a client for the standby at standbyAddr (host:port),
+// installs the HA client as an interceptor of primary and starts monitoring.
+func NewHAClient(primary *RocksDBClient, standbyAddr string, opts HAOptions) *HAClient {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.ProbeTimeout <= 0 {
+		opts.ProbeTimeout = opts.Interval
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 3
+	}
+	h := &HAClient{
+		primary: primary,
+		standby: primary.cloneFor(standbyAddr, opts.ClientOptions),
+		opts:    opts,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	primary.Use(h.intercept)
+	go h.monitor()
+	return h
+}
+
+func (h *HAClient) intercept(request Request, next Handler) (*Response, error) {
+	if h.Promoted() {
+		return h.standby.SendRequest(request)
+	}
+	return next(request)
+}
+
+// Promoted reports whether the standby has taken over.
+func (h *HAClient) Promoted() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.promoted
+}
+
+// Lag returns how many sequence numbers the standby was behind the primary
+// at the last probe.
+func (h *HAClient) Lag() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.standbySeq >= h.primarySeq {
+		return 0
+	}
+	return h.primarySeq - h.standbySeq
+}
+
+// Standby returns the client of the standby server.
+func (h *HAClient) Standby() *RocksDBClient {
+	return h.standby
+}
+
+// Promote hands writes to the standby right away, regardless of the health
+// policy and lag, e.g. for a planned switchover.
+func (h *HAClient) Promote() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.promote()
+}
+
+func (h *HAClient) monitor() {
+	defer close(h.done)
+	ticker := time.NewTicker(h.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+		if h.Promoted() {
+			return
+		}
+		h.check()
+	}
+}
+
+// check probes both servers once and applies the health policy.
+func (h *HAClient) check() {
+	// The probe bypasses the interceptors, including this one.
+	primarySeq, primaryErr := probeSequence(h.primary.roundTrip, h.opts.ProbeTimeout)
+	standbySeq, standbyErr := probeSequence(h.standby.SendRequest, h.opts.ProbeTimeout)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if standbyErr == nil {
+		h.standbySeq = standbySeq
+	}
+	if primaryErr == nil {
+		h.primarySeq = primarySeq
+		h.failures = 0
+		return
+	}
+	h.failures++
+	if h.failures < h.opts.FailureThreshold {
+		return
+	}
+	if h.failures == h.opts.FailureThreshold {
+		h.emit(HAEvent{Type: HAPrimaryDown, Err: primaryErr})
+	}
+	switch {
+	case standbyErr != nil:
+		h.emit(HAEvent{Type: HAPromotionBlocked, Err: fmt.Errorf("standby unreachable: %w", standbyErr)})
+	case h.primarySeq > h.standbySeq+h.opts.MaxLag:
+		h.emit(HAEvent{Type: HAPromotionBlocked, Err: fmt.Errorf("standby is %d writes behind", h.primarySeq-h.standbySeq)})
+	default:
+		h.promote()
+	}
+}
+
+func (h *HAClient) promote() {
+	if h.promoted {
+		return
+	}
+	h.promoted = true
+	h.emit(HAEvent{Type: HAPromoted})
+}
+
+func (h *HAClient) emit(event HAEvent) {
+	if h.opts.OnEvent == nil {
+		return
+	}
+	event.Time = time.Now()
+	event.PrimarySeq, event.StandbySeq = h.primarySeq, h.standbySeq
+	h.opts.OnEvent(event)
+}
+
+func probeSequence(send Handler, timeout time.Duration) (uint64, error) {
+	response, err := send(Request{Action: ActionGetLatestSequence, Options: map[string]string{}, Timeout: timeout})
+	if err != nil {
+		return 0, err
+	}
+	seq, err := strconv.ParseUint(response.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding get_latest_sequence_number result: %w", err)
+	}
+	return seq, nil
+}
+
+// Close stops monitoring and closes the standby connection. The interceptor
+// stays installed on the primary, so the HA client must not be closed while
+// the primary is used.
+func (h *HAClient) Close() {
+	select {
+	case <-h.stop:
+	default:
+		close(h.stop)
+	}
+	<-h.done
+	h.standby.Close()
+}
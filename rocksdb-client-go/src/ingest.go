@@ -0,0 +1,116 @@
+package rocksdbclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// uploadChunkSize is the number of file bytes sent per upload_chunk request,
+// before base64 encoding.
+const uploadChunkSize = 1 << 20
+
+// IngestExternalFile adds the SST files at paths on the server to the column
+// family cfName (the default one when nil) with the `ingest_external_file`
+// action, which maps to RocksDB's IngestExternalFile. Ingesting files
+// produced offline, e.g. by an SstFileWriter, is far faster than writing the
+// same keys one by one. With moveFiles the server moves (hard links) the
+// files instead of copying them.
+func (c *RocksDBClient) IngestExternalFile(paths []string, cfName *string, moveFiles bool) error {
+	if len(paths) == 0 {
+		return errors.New("no files to ingest")
+	}
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return fmt.Errorf("error encoding ingest paths: %w", err)
+	}
+	value := string(data)
+	request := Request{
+		Action:  ActionIngestExternalFile,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{},
+	}
+	if moveFiles {
+		request.Options[OptionMoveFiles] = "true"
+	}
+	_, err = c.SendRequest(request)
+	return err
+}
+
+// uploadResult is the result of the last `upload_chunk` request of a file.
+type uploadResult struct {
+	Path string `json:"path"`
+}
+
+// UploadFile streams r to the server's upload directory as the file name
+// using `upload_chunk` requests of up to 1 MiB each, and returns the path of
+// the file on the server for IngestExternalFile. Chunks carry their offset,
+// so the server can reject gaps and ignore retried chunks.
+func (c *RocksDBClient) UploadFile(r io.Reader, name string) (string, error) {
+	if name == "" || filepath.Base(name) != name {
+		return "", fmt.Errorf("invalid upload name %q", name)
+	}
+	buf := make([]byte, uploadChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return "", fmt.Errorf("error reading upload: %w", err)
+		}
+		chunk := base64.StdEncoding.EncodeToString(buf[:n])
+		request := Request{
+			Action: ActionUploadChunk,
+			Value:  &chunk,
+			Options: map[string]string{
+				OptionName:   name,
+				OptionOffset: strconv.FormatInt(offset, 10),
+			},
+		}
+		if last {
+			request.Options[OptionLast] = "true"
+		}
+		response, err := c.SendRequest(request)
+		if err != nil {
+			return "", err
+		}
+		offset += int64(n)
+		if last {
+			var result uploadResult
+			if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+				return "", fmt.Errorf("error decoding upload_chunk result: %w", err)
+			}
+			return result.Path, nil
+		}
+	}
+}
+
+// IngestLocalFiles uploads the local SST files at paths with UploadFile and
+// ingests them into cfName with IngestExternalFile in one step. The uploaded
+// copies are moved into the database, so they take no extra space.
+func (c *RocksDBClient) IngestLocalFiles(paths []string, cfName *string) error {
+	remote := make([]string, 0, len(paths))
+	for _, path := range paths {
+		remotePath, err := c.uploadLocalFile(path)
+		if err != nil {
+			return err
+		}
+		remote = append(remote, remotePath)
+	}
+	return c.IngestExternalFile(remote, cfName, true)
+}
+
+func (c *RocksDBClient) uploadLocalFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer f.Close()
+	return c.UploadFile(f, filepath.Base(path))
+}
@@ -0,0 +1,8 @@
+package rocksdbclient
+
+// Handler sends a request and returns the server response.
+type Handler func(request Request) (*Response, error)
+
+// Interceptor wraps request handling. It may inspect or modify the request,
+// answer it locally without calling next, or post-process the response.
+type Interceptor func(request Request, next Handler) (*Response, error)
@@ -0,0 +1,228 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// invalidIteratorEntry is what the server returns once an iterator runs off
+// either end of the keyspace.
+const invalidIteratorEntry = "invalid:invalid"
+
+// Iterator is a cursor over a server-side RocksDB iterator. It wraps the
+// create_iterator, iterator_seek, iterator_seek_for_prev,
+// iterator_seek_to_first, iterator_seek_to_last, iterator_next, iterator_prev
+// and destroy_iterator actions; every positioning call is one round trip.
+//
+//	it, err := client.NewIterator()
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for k, v := range it.All() {
+//		fmt.Println(k, v)
+//	}
+//	return it.Err()
+type Iterator struct {
+	c     *RocksDBClient
+	id    string
+	key   string
+	value string
+	valid bool
+	err   error
+}
+
+// NewIterator creates a server-side iterator. The iterator is not positioned
+// until Seek is called. It must be closed to release the server resources.
+func (c *RocksDBClient) NewIterator() (*Iterator, error) {
+	return c.NewIteratorWithOptions(IteratorOptions{})
+}
+
+// NewIteratorCF creates a server-side iterator over the given column family,
+// or the default one when cfName is nil.
+func (c *RocksDBClient) NewIteratorCF(cfName *string) (*Iterator, error) {
+	var opts IteratorOptions
+	if cfName != nil {
+		opts.CF = *cfName
+	}
+	return c.NewIteratorWithOptions(opts)
+}
+
+// IteratorOptions mirror the RocksDB ReadOptions that limit iteration. Zero
+// values leave a bound open.
+type IteratorOptions struct {
+	// LowerBound is the inclusive lower bound (iterate_lower_bound).
+	LowerBound string
+	// UpperBound is the exclusive upper bound (iterate_upper_bound).
+	UpperBound string
+	// PrefixSameAsStart stops the iterator once keys no longer share the
+	// prefix of the seek key, as defined by the column family's prefix
+	// extractor.
+	PrefixSameAsStart bool
+	// CF is the column family to iterate; empty means the default one.
+	CF string
+	// Read applies to every read of the iterator, e.g. to keep a full scan
+	// out of the block cache.
+	Read ReadOptions
+}
+
+// NewIteratorWithOptions creates a server-side iterator limited by opts, so
+// the server stops at the bounds instead of walking the rest of the keyspace.
+func (c *RocksDBClient) NewIteratorWithOptions(opts IteratorOptions) (*Iterator, error) {
+	request := Request{Action: ActionCreateIterator, Options: map[string]string{}}
+	if opts.CF != "" {
+		request.CfName = &opts.CF
+	}
+	if opts.LowerBound != "" {
+		request.Options[OptionLowerBound] = opts.LowerBound
+	}
+	if opts.UpperBound != "" {
+		request.Options[OptionUpperBound] = opts.UpperBound
+	}
+	if opts.PrefixSameAsStart {
+		request.Options[OptionPrefixSameAsStart] = "true"
+	}
+	opts.Read.apply(request.Options)
+
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{c: c, id: response.Result}, nil
+}
+
+func (it *Iterator) call(action string, key *string) bool {
+	if it.err != nil {
+		return false
+	}
+	request := Request{
+		Action:  action,
+		Key:     key,
+		Options: map[string]string{OptionIteratorID: it.id, OptionFormat: "json"},
+	}
+	response, err := it.c.SendRequest(request)
+	if err == nil {
+		it.key, it.value, it.valid, err = parseIteratorEntry(response.Result)
+	}
+	if err != nil {
+		it.err = err
+		it.key, it.value, it.valid = "", "", false
+	}
+	return it.valid
+}
+
+// Seek positions the iterator at the first key >= key and reports whether
+// it is valid.
+func (it *Iterator) Seek(key string) bool {
+	return it.call(ActionIteratorSeek, &key)
+}
+
+// SeekToFirst positions the iterator at the first key and reports whether it
+// is valid, i.e. whether the iterated range is not empty.
+func (it *Iterator) SeekToFirst() bool {
+	return it.call(ActionIteratorSeekToFirst, nil)
+}
+
+// SeekToLast positions the iterator at the last key and reports whether it
+// is valid.
+func (it *Iterator) SeekToLast() bool {
+	return it.call(ActionIteratorSeekToLast, nil)
+}
+
+// SeekForPrev positions the iterator at the last key <= key and reports
+// whether it is valid.
+func (it *Iterator) SeekForPrev(key string) bool {
+	return it.call(ActionIteratorSeekForPrev, &key)
+}
+
+// Next moves to the following key and reports whether the iterator is valid.
+func (it *Iterator) Next() bool {
+	return it.call(ActionIteratorNext, nil)
+}
+
+// Prev moves to the preceding key and reports whether the iterator is valid.
+func (it *Iterator) Prev() bool {
+	return it.call(ActionIteratorPrev, nil)
+}
+
+// Valid reports whether the iterator is positioned at an entry.
+func (it *Iterator) Valid() bool {
+	return it.valid
+}
+
+// Key returns the key at the current position.
+func (it *Iterator) Key() string {
+	return it.key
+}
+
+// Value returns the value at the current position.
+func (it *Iterator) Value() string {
+	return it.value
+}
+
+// Err returns the first error the iterator encountered. Once an error
+// occurred the iterator stays invalid.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close destroys the server-side iterator.
+func (it *Iterator) Close() error {
+	if it.c == nil {
+		return nil
+	}
+	_, err := it.c.SendRequest(Request{
+		Action:  ActionDestroyIterator,
+		Options: map[string]string{OptionIteratorID: it.id},
+	})
+	it.c = nil
+	it.valid = false
+	return err
+}
+
+// All seeks to the first key and yields every entry in order. Check Err
+// after the loop to tell the end of the keyspace apart from a failure.
+func (it *Iterator) All() iter.Seq2[string, string] {
+	return it.From("")
+}
+
+// From yields every entry starting at the first key >= key.
+func (it *Iterator) From(key string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for ok := it.Seek(key); ok; ok = it.Next() {
+			if !yield(it.key, it.value) {
+				return
+			}
+		}
+	}
+}
+
+// iteratorEntry is the iterator response shape requested with the
+// "format": "json" option.
+type iteratorEntry struct {
+	Key   *string `json:"key"`
+	Value string  `json:"value"`
+}
+
+// parseIteratorEntry decodes an iterator response. Servers that understand the
+// "format": "json" option answer with an iteratorEntry object; older ones send
+// an unescaped "key:value" string, which is split at the first ':' and so
+// cannot represent keys containing a colon.
+func parseIteratorEntry(result string) (string, string, bool, error) {
+	if result == invalidIteratorEntry || result == "" || result == "null" {
+		return "", "", false, nil
+	}
+	if strings.HasPrefix(result, "{") {
+		var entry iteratorEntry
+		if err := json.Unmarshal([]byte(result), &entry); err == nil && entry.Key != nil {
+			return *entry.Key, entry.Value, true, nil
+		}
+	}
+	key, value, found := strings.Cut(result, ":")
+	if !found {
+		return "", "", false, fmt.Errorf("malformed iterator entry: %q", result)
+	}
+	return key, value, true, nil
+}
@@ -0,0 +1,186 @@
+package rocksdbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Errors reported by Job.Wait for jobs that did not succeed.
+var (
+	ErrJobFailed   = errors.New("job failed")
+	ErrJobCanceled = errors.New("job canceled")
+)
+
+// jobPollInterval is how often Job.Wait polls the job status.
+const jobPollInterval = 200 * time.Millisecond
+
+// JobState is the lifecycle state of a server-side job.
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCanceled  JobState = "canceled"
+)
+
+// Done reports whether the job has finished, successfully or not.
+func (s JobState) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// JobStatus is the result of the `job_status` action.
+type JobStatus struct {
+	ID string `json:"id"`
+	// Kind is the action that started the job, e.g. "backup".
+	Kind  string   `json:"kind"`
+	State JobState `json:"state"`
+	// Progress is the completed fraction between 0 and 1, if the job reports
+	// it.
+	Progress  float64   `json:"progress"`
+	StartedAt time.Time `json:"started_at"`
+	// Client identifies who started the job: the caller label set with
+	// SetCaller, or the client address.
+	Client string `json:"client,omitempty"`
+	// Result is the job's result once it succeeded.
+	Result string `json:"result,omitempty"`
+	// Error is the failure message once it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Job is a handle to a long-running operation the server runs in the
+// background. Requests that start a job return as soon as it is queued.
+//
+//	job, err := client.BackupJob()
+//	if err != nil {
+//		return err
+//	}
+//	status, err := job.Wait(ctx)
+type Job struct {
+	c    *RocksDBClient
+	ID   string
+	Kind string
+}
+
+// startJob sends request with the "async" option and returns a handle to
+// the job the server started for it.
+func (c *RocksDBClient) startJob(request Request) (*Job, error) {
+	if request.Options == nil {
+		request.Options = map[string]string{}
+	}
+	request.Options[OptionAsync] = "true"
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Result == "" {
+		return nil, fmt.Errorf("server did not return a job id for %s", request.Action)
+	}
+	return &Job{c: c, ID: response.Result, Kind: request.Action}, nil
+}
+
+// BackupJob starts a backup like Backup, without waiting for it to finish.
+func (c *RocksDBClient) BackupJob() (*Job, error) {
+	return c.startJob(Request{Action: ActionBackup})
+}
+
+// RestoreJob starts restoring the backup with the given ID, see ListBackups,
+// without waiting for it to finish.
+func (c *RocksDBClient) RestoreJob(backupID uint32) (*Job, error) {
+	return c.startJob(Request{
+		Action:  ActionRestore,
+		Options: map[string]string{OptionBackupID: strconv.FormatUint(uint64(backupID), 10)},
+	})
+}
+
+// CompactRangeJob starts a manual compaction of the keys in [start, end) like
+// CompactRange, without waiting for it to finish. Empty bounds are open.
+func (c *RocksDBClient) CompactRangeJob(start, end string, cfName *string) (*Job, error) {
+	request := Request{Action: ActionCompactRange, CfName: cfName, Options: map[string]string{}}
+	if start != "" {
+		request.Options[OptionStart] = start
+	}
+	if end != "" {
+		request.Options[OptionEnd] = end
+	}
+	return c.startJob(request)
+}
+
+// Status fetches the current state of the job with the `job_status` action.
+func (j *Job) Status() (*JobStatus, error) {
+	response, err := j.c.SendRequest(Request{
+		Action:  ActionJobStatus,
+		Options: map[string]string{OptionJobID: j.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := &JobStatus{}
+	if err := json.Unmarshal([]byte(response.Result), status); err != nil {
+		return nil, fmt.Errorf("error decoding job_status result: %w", err)
+	}
+	return status, nil
+}
+
+// Wait polls the job until it finishes or ctx is done and returns its final
+// status. A failed job returns an error matching ErrJobFailed, a canceled one
+// ErrJobCanceled; the status is returned in both cases.
+func (j *Job) Wait(ctx context.Context) (*JobStatus, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := j.Status()
+		if err != nil {
+			return nil, err
+		}
+		switch status.State {
+		case JobSucceeded:
+			return status, nil
+		case JobFailed:
+			return status, fmt.Errorf("%w: %s %s: %s", ErrJobFailed, j.Kind, j.ID, status.Error)
+		case JobCanceled:
+			return status, fmt.Errorf("%w: %s %s", ErrJobCanceled, j.Kind, j.ID)
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListOperations returns the jobs currently running on the server, started
+// by any client, with the `list_operations` action. Pass an ID to Cancel to
+// abort one.
+func (c *RocksDBClient) ListOperations() ([]JobStatus, error) {
+	response, err := c.SendRequest(Request{Action: ActionListOperations, Options: map[string]string{}})
+	if err != nil {
+		return nil, err
+	}
+	var jobs []JobStatus
+	if err := json.Unmarshal([]byte(response.Result), &jobs); err != nil {
+		return nil, fmt.Errorf("error decoding list_operations result: %w", err)
+	}
+	return jobs, nil
+}
+
+// Cancel asks the server to abort the job, see RocksDBClient.Cancel.
+func (j *Job) Cancel() error {
+	return j.c.Cancel(j.ID)
+}
+
+// Cancel asks the server to abort the job with the given ID with the
+// `cancel_operation` action. The job stops asynchronously at its next
+// checkpoint; Job.Wait reports ErrJobCanceled once it has. Canceling a job
+// that already finished is not an error.
+func (c *RocksDBClient) Cancel(jobID string) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionCancelOperation,
+		Options: map[string]string{OptionJobID: jobID},
+	})
+	return err
+}
@@ -0,0 +1,100 @@
+package rocksdbclient
+
+// defaultJoinBatchSize is the number of primary entries JoinScan resolves per
+// MultiGetCF round trip.
+const defaultJoinBatchSize = 100
+
+// Join describes one related lookup of JoinScan.
+type Join struct {
+	// CF is the column family holding the related keys.
+	CF string
+	// Key derives the related key from a primary entry. Returning false skips
+	// the lookup for that entry.
+	Key func(key, value string) (string, bool)
+}
+
+// JoinRow is a primary entry together with its related values. Related has
+// one element per Join, nil when the lookup was skipped or the key does not
+// exist.
+type JoinRow struct {
+	Key     string
+	Value   string
+	Related []*string
+}
+
+// JoinScan iterates primaryCF (the default column family when nil) from the
+// first key >= start, resolves the joins of batchSize entries at a time with
+// a single MultiGetCF, and calls fn for every row in key order. A batchSize
+// <= 0 defaults to 100. Errors returned by fn stop the scan; panics are
+// returned as a *PanicError.
+func (c *RocksDBClient) JoinScan(primaryCF *string, start string, joins []Join, batchSize int, fn func(JoinRow) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultJoinBatchSize
+	}
+
+	it, err := c.NewIteratorCF(primaryCF)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var (
+		rows   = make([]JoinRow, 0, batchSize)
+		offset int64
+	)
+	flush := func() error {
+		if err := c.resolveJoins(rows, joins); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			err := callScanFunc(func(string, string) error { return fn(row) }, row.Key, row.Value, offset)
+			if err != nil {
+				return err
+			}
+			offset++
+		}
+		rows = rows[:0]
+		return nil
+	}
+
+	for key, value := range it.From(start) {
+		rows = append(rows, JoinRow{Key: key, Value: value})
+		if len(rows) == batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (c *RocksDBClient) resolveJoins(rows []JoinRow, joins []Join) error {
+	var keys []CFKey
+	refs := make([][]*CFKey, len(rows))
+	for i, row := range rows {
+		refs[i] = make([]*CFKey, len(joins))
+		for j, join := range joins {
+			if key, ok := join.Key(row.Key, row.Value); ok {
+				refs[i][j] = &CFKey{Key: key, CF: join.CF}
+				keys = append(keys, *refs[i][j])
+			}
+		}
+	}
+
+	values, err := c.MultiGetCF(keys)
+	if err != nil {
+		return err
+	}
+	for i := range rows {
+		rows[i].Related = make([]*string, len(joins))
+		for j, ref := range refs[i] {
+			if ref != nil {
+				rows[i].Related[j] = values[*ref]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Journal defaults used by NewJournal.
+const (
+	DefaultJournalMaxSize  = 64 << 20
+	DefaultJournalMaxFiles = 5
+)
+
+// JournalOptions configures NewJournal.
+type JournalOptions struct {
+	// Path is the active journal file. Rotated files are named Path.1 (the
+	// newest) to Path.N.
+	Path string
+	// MaxSize rotates the journal once it would grow beyond this many bytes.
+	// Defaults to 64 MiB.
+	MaxSize int64
+	// MaxFiles is how many rotated files are kept. Defaults to 5.
+	MaxFiles int
+	// RedactValues stores the SHA-256 of every value (see ValueHash) instead
+	// of the value, e.g. for datasets with personal data. Redacted journals
+	// show what was written where, but cannot be replayed.
+	RedactValues bool
+}
+
+// JournalEntry is one line of a journal.
+type JournalEntry struct {
+	Time    time.Time `json:"time"`
+	Request Request   `json:"request"`
+	// Error is the error the request failed with, empty on success.
+	Error    string `json:"error,omitempty"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// Journal appends every mutating request sent through a client to a local
+// log, one JSON-encoded JournalEntry per line, so incidents can be
+// reconstructed and replayed against a test server with ReplayJournal.
+// Failed requests are journaled too, with their error. Auth tokens are never
+// written.
+type Journal struct {
+	opts JournalOptions
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	err  error
+}
+
+// NewJournal opens or creates the journal at opts.Path and installs it as an
+// interceptor. Requests are journaled as interceptors installed earlier
+// passed them on, e.g. after WithCFRoutes filled in column families.
+func NewJournal(c *RocksDBClient, opts JournalOptions) (*Journal, error) {
+	if opts.Path == "" {
+		return nil, errors.New("journal path must be provided")
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultJournalMaxSize
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = DefaultJournalMaxFiles
+	}
+	j := &Journal{opts: opts}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	c.Use(j.intercept)
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	file, err := os.OpenFile(j.opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening journal: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening journal: %w", err)
+	}
+	j.file, j.size = file, info.Size()
+	return nil
+}
+
+func (j *Journal) intercept(request Request, next Handler) (*Response, error) {
+	if !isMutation(request.Action) {
+		return next(request)
+	}
+	response, err := next(request)
+	entry := JournalEntry{Time: time.Now().UTC(), Request: request}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	j.append(entry)
+	return response, err
+}
+
+// append writes entry. A write error is kept for Err and does not fail the
+// request, which has already been sent.
+func (j *Journal) append(entry JournalEntry) {
+	entry.Request.Token = nil
+	entry.Request.RequestID = ""
+	if j.opts.RedactValues {
+		entry.Request = redactRequest(entry.Request)
+		entry.Redacted = true
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		j.setErr(fmt.Errorf("error encoding journal entry: %w", err))
+		return
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return
+	}
+	if j.size > 0 && j.size+int64(len(line)) > j.opts.MaxSize {
+		if err := j.rotate(); err != nil {
+			j.err = err
+			return
+		}
+	}
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	if err != nil && j.err == nil {
+		j.err = fmt.Errorf("error writing journal: %w", err)
+	}
+}
+
+func (j *Journal) setErr(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err == nil {
+		j.err = err
+	}
+}
+
+// rotate shifts Path.N-1 to Path.N, dropping the oldest file, and moves the
+// active file to Path.1.
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("error rotating journal: %w", err)
+	}
+	j.file = nil
+	name := func(n int) string { return j.opts.Path + "." + strconv.Itoa(n) }
+	os.Remove(name(j.opts.MaxFiles))
+	for n := j.opts.MaxFiles - 1; n >= 1; n-- {
+		if err := os.Rename(name(n), name(n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error rotating journal: %w", err)
+		}
+	}
+	if err := os.Rename(j.opts.Path, name(1)); err != nil {
+		return fmt.Errorf("error rotating journal: %w", err)
+	}
+	return j.open()
+}
+
+// Err returns the first error writing the journal.
+func (j *Journal) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Close closes the journal file. Later requests are no longer journaled.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func redactRequest(request Request) Request {
+	if request.Value != nil {
+		hash := ValueHash(*request.Value)
+		request.Value = &hash
+	}
+	if request.Operations != nil {
+		ops := make([]Operation, len(request.Operations))
+		for i, op := range request.Operations {
+			if op.Value != nil {
+				hash := ValueHash(*op.Value)
+				op.Value = &hash
+			}
+			ops[i] = op
+		}
+		request.Operations = ops
+	}
+	if _, found := request.Options[OptionExpected]; found {
+		options := make(map[string]string, len(request.Options))
+		for k, v := range request.Options {
+			options[k] = v
+		}
+		options[OptionExpected] = ValueHash(options[OptionExpected])
+		request.Options = options
+	}
+	return request
+}
+
+// ReadJournal calls fn for every entry of a journal file in order.
+func ReadJournal(r io.Reader, fn func(JournalEntry) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry JournalEntry
+			if decodeErr := json.Unmarshal(line, &entry); decodeErr != nil {
+				return fmt.Errorf("error decoding journal entry: %w", decodeErr)
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading journal: %w", err)
+		}
+	}
+}
+
+// ReplayJournal sends the successful requests of a journal to c, e.g. a test
+// server, and returns how many were applied. Writes made in a transaction are
+// applied without one once the journal shows the transaction committed;
+// those of transactions that never committed are skipped. Rotated files have
+// to be replayed oldest first. Redacted journals cannot be replayed.
+func ReplayJournal(c *RocksDBClient, r io.Reader) (int, error) {
+	applied := 0
+	txns := map[string][]Request{}
+	send := func(request Request) error {
+		request.Txn, request.TxnID = nil, nil
+		if _, err := c.SendRequest(request); err != nil {
+			return fmt.Errorf("error replaying %s: %w", request.Action, err)
+		}
+		applied++
+		return nil
+	}
+	err := ReadJournal(r, func(entry JournalEntry) error {
+		if entry.Redacted {
+			return errors.New("redacted journals cannot be replayed")
+		}
+		if entry.Error != "" {
+			return nil
+		}
+		request := entry.Request
+		if request.TxnID == nil {
+			return send(request)
+		}
+		id := *request.TxnID
+		if request.Action != ActionCommitTransaction {
+			txns[id] = append(txns[id], request)
+			return nil
+		}
+		for _, pending := range txns[id] {
+			if err := send(pending); err != nil {
+				return err
+			}
+		}
+		delete(txns, id)
+		return nil
+	})
+	return applied, err
+}
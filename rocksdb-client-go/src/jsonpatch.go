@@ -0,0 +1,226 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOp is one RFC 6902 operation as sent by the server.
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies an RFC 6902 patch to doc, a value decoded by
+// encoding/json, and returns the patched document.
+func applyJSONPatch(doc any, patch []jsonPatchOp) (any, error) {
+	for _, op := range patch {
+		var err error
+		switch op.Op {
+		case "add", "replace", "test":
+			var value any
+			if err = json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fmt.Errorf("json patch %s %s: %w", op.Op, op.Path, err)
+			}
+			switch op.Op {
+			case "add":
+				doc, err = jsonPointerAdd(doc, op.Path, value)
+			case "replace":
+				if doc, _, err = jsonPointerRemove(doc, op.Path); err == nil {
+					doc, err = jsonPointerAdd(doc, op.Path, value)
+				}
+			case "test":
+				var current any
+				if current, err = jsonPointerGet(doc, op.Path); err == nil && !reflect.DeepEqual(current, value) {
+					err = fmt.Errorf("test failed")
+				}
+			}
+		case "remove":
+			doc, _, err = jsonPointerRemove(doc, op.Path)
+		case "move":
+			var value any
+			if doc, value, err = jsonPointerRemove(doc, op.From); err == nil {
+				doc, err = jsonPointerAdd(doc, op.Path, value)
+			}
+		case "copy":
+			var value any
+			if value, err = jsonPointerGet(doc, op.From); err == nil {
+				doc, err = jsonPointerAdd(doc, op.Path, deepCopyJSON(value))
+			}
+		default:
+			err = fmt.Errorf("unsupported operation")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("json patch %s %s: %w", op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// splitJSONPointer decodes an RFC 6901 pointer into reference tokens.
+func splitJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > length || (!allowEnd && index == length) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+func jsonPointerGet(doc any, pointer string) (any, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, found := node[token]
+			if !found {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("cannot traverse %T", doc)
+		}
+	}
+	return doc, nil
+}
+
+// jsonPointerAdd inserts value at pointer. Arrays are rebuilt rather than
+// modified in place, so the parent is updated with the new slice.
+func jsonPointerAdd(doc any, pointer string, value any) (any, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return updateParent(doc, tokens, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[last] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(last, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node[:index], append([]any{value}, node[index:]...)...)
+			return node, nil
+		}
+		return nil, fmt.Errorf("cannot add to %T", parent)
+	})
+}
+
+// jsonPointerRemove removes the value at pointer and returns it.
+func jsonPointerRemove(doc any, pointer string) (any, any, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	var removed any
+	doc, err = updateParent(doc, tokens, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			value, found := node[last]
+			if !found {
+				return nil, fmt.Errorf("member %q not found", last)
+			}
+			removed = value
+			delete(node, last)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(last, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return append(node[:index:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove from %T", parent)
+	})
+	return doc, removed, err
+}
+
+// updateParent walks to the parent of the last token, lets fn replace it and
+// writes the result back up the path.
+func updateParent(doc any, tokens []string, fn func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	switch node := doc.(type) {
+	case map[string]any:
+		child, found := node[tokens[0]]
+		if !found {
+			return nil, fmt.Errorf("member %q not found", tokens[0])
+		}
+		updated, err := updateParent(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+		return node, nil
+	case []any:
+		index, err := arrayIndex(tokens[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := updateParent(node[index], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	}
+	return nil, fmt.Errorf("cannot traverse %T", doc)
+}
+
+func deepCopyJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for k, item := range v {
+			copied[k] = deepCopyJSON(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyJSON(item)
+		}
+		return copied
+	}
+	return value
+}
@@ -0,0 +1,152 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// defaultKeyStreamPageSize is the number of keys KeyStream requests per page.
+const defaultKeyStreamPageSize = 1000
+
+// keysCursorPage is the result of the `keys_cursor` action. An empty Cursor
+// marks the last page.
+type keysCursorPage struct {
+	Keys   []string `json:"keys"`
+	Cursor string   `json:"cursor"`
+}
+
+// KeyStream pages through the keys with a prefix using a server-supplied
+// cursor, holding only one page in memory at a time. Unlike start/limit
+// paging, the cursor stays correct when keys are inserted or deleted while
+// streaming.
+//
+//	stream := client.KeysStream("user:", 0)
+//	for key := range stream.All() {
+//		fmt.Println(key)
+//	}
+//	return stream.Err()
+type KeyStream struct {
+	c        *RocksDBClient
+	prefix   string
+	pageSize int
+	cursor   string
+	done     bool
+	err      error
+}
+
+// KeysStream returns a stream over the keys starting with prefix in key
+// order. A pageSize <= 0 defaults to 1000.
+func (c *RocksDBClient) KeysStream(prefix string, pageSize int) *KeyStream {
+	if pageSize <= 0 {
+		pageSize = defaultKeyStreamPageSize
+	}
+	return &KeyStream{c: c, prefix: prefix, pageSize: pageSize}
+}
+
+// ResumeKeysStream continues a stream from a cursor previously returned by
+// KeyStream.Cursor, e.g. in another process. A cursor issued for another
+// prefix fails the stream with ErrInvalidCursor.
+func (c *RocksDBClient) ResumeKeysStream(prefix, cursor string, pageSize int) *KeyStream {
+	s := c.KeysStream(prefix, pageSize)
+	s.cursor, s.err = decodeCursorFor(cursor, keysFilterHash(prefix))
+	return s
+}
+
+// KeysPage returns one page of up to limit keys starting with prefix, after
+// cursor (empty for the first page). A limit <= 0 defaults to 1000.
+func (c *RocksDBClient) KeysPage(prefix string, limit int, cursor string) (*Page[string], error) {
+	if limit <= 0 {
+		limit = defaultKeyStreamPageSize
+	}
+	hash := keysFilterHash(prefix)
+	after, err := decodeCursorFor(cursor, hash)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.keysCursor(prefix, limit, after)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[string]{Items: result.Keys}
+	if result.Cursor != "" {
+		page.Next = EncodeCursor(result.Cursor, hash)
+	}
+	return page, nil
+}
+
+func keysFilterHash(prefix string) uint64 {
+	return FilterHash("keys", prefix)
+}
+
+// keysCursor fetches the page after cursor with the `keys_cursor` action.
+func (c *RocksDBClient) keysCursor(prefix string, limit int, cursor string) (*keysCursorPage, error) {
+	request := Request{
+		Action: ActionKeysCursor,
+		Options: map[string]string{
+			OptionPrefix: prefix,
+			OptionLimit:  strconv.Itoa(limit),
+		},
+	}
+	if cursor != "" {
+		request.Options[OptionCursor] = cursor
+	}
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	page := &keysCursorPage{}
+	if err := json.Unmarshal([]byte(response.Result), page); err != nil {
+		return nil, fmt.Errorf("error decoding keys_cursor result: %w", err)
+	}
+	return page, nil
+}
+
+// nextPage fetches the page at the current cursor.
+func (s *KeyStream) nextPage() ([]string, bool) {
+	if s.done || s.err != nil {
+		return nil, false
+	}
+	page, err := s.c.keysCursor(s.prefix, s.pageSize, s.cursor)
+	if err != nil {
+		s.err = err
+		return nil, false
+	}
+	s.cursor = page.Cursor
+	s.done = page.Cursor == ""
+	return page.Keys, true
+}
+
+// All yields the remaining keys, fetching pages as needed. Check Err after
+// the loop.
+func (s *KeyStream) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for {
+			keys, ok := s.nextPage()
+			if !ok {
+				return
+			}
+			for _, key := range keys {
+				if !yield(key) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Cursor returns an opaque token for the next page, empty once the stream is
+// done. Stopping All early discards the rest of the current page, so resuming
+// from Cursor is only exact at page boundaries.
+func (s *KeyStream) Cursor() string {
+	if s.cursor == "" {
+		return ""
+	}
+	return EncodeCursor(s.cursor, keysFilterHash(s.prefix))
+}
+
+// Err returns the error that ended the stream, if any.
+func (s *KeyStream) Err() error {
+	return s.err
+}
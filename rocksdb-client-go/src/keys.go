@@ -0,0 +1,84 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// KeysResult is the decoded result of the `keys` and `all` actions.
+type KeysResult struct {
+	Keys []string
+	// Raw is the undecoded server response.
+	Raw *Response
+}
+
+// ColumnFamiliesResult is the decoded result of the `list_column_families`
+// action.
+type ColumnFamiliesResult struct {
+	Names []string
+	// Raw is the undecoded server response.
+	Raw *Response
+}
+
+// ListKeys returns up to limit keys, skipping the first start, of the keys or
+// values containing query (all keys when query is empty). It is the typed
+// counterpart of Keys.
+func (c *RocksDBClient) ListKeys(start, limit int, query string) (*KeysResult, error) {
+	request := Request{
+		Action: ActionKeys,
+		Options: map[string]string{
+			OptionStart: strconv.Itoa(start),
+			OptionLimit: strconv.Itoa(limit),
+		},
+	}
+	if query != "" {
+		request.Options[OptionQuery] = query
+	}
+	return c.sendKeys(request)
+}
+
+// ListAllKeys returns every key whose key or value contains query. It is the
+// typed counterpart of All.
+func (c *RocksDBClient) ListAllKeys(query string) (*KeysResult, error) {
+	request := Request{Action: ActionAll, Options: map[string]string{}}
+	if query != "" {
+		request.Options[OptionQuery] = query
+	}
+	return c.sendKeys(request)
+}
+
+// ColumnFamilies returns the names of all column families. It is the typed
+// counterpart of ListColumnFamilies.
+func (c *RocksDBClient) ColumnFamilies() (*ColumnFamiliesResult, error) {
+	response, err := c.SendRequest(Request{Action: ActionListColumnFamilies, Options: map[string]string{}})
+	if err != nil {
+		return nil, err
+	}
+	result := &ColumnFamiliesResult{Raw: response}
+	if err := json.Unmarshal([]byte(response.Result), &result.Names); err != nil {
+		return nil, fmt.Errorf("error decoding column families: %w", err)
+	}
+	return result, nil
+}
+
+func (c *RocksDBClient) sendKeys(request Request) (*KeysResult, error) {
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	result := &KeysResult{Raw: response}
+	if err := json.Unmarshal([]byte(response.Result), &result.Keys); err != nil {
+		return nil, fmt.Errorf("error decoding keys: %w", err)
+	}
+	return result, nil
+}
+
+// keysPage fetches one page of keys containing query with the `keys` action.
+func (c *RocksDBClient) keysPage(query string, start, limit int) ([]string, error) {
+	result, err := c.ListKeys(start, limit, query)
+	if err != nil {
+		return nil, err
+	}
+	return result.Keys, nil
+}
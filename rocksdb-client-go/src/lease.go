@@ -0,0 +1,140 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Lease keeps ephemeral keys alive. The server deletes every key attached to
+// a lease when the lease is revoked, when it is not kept alive within its
+// TTL, or when the connection that granted it closes.
+type Lease struct {
+	c *RocksDBClient
+	// ID identifies the lease in PutEphemeral.
+	ID string
+	// TTL is how long the lease lives without a keep-alive.
+	TTL time.Duration
+
+	mu   sync.Mutex
+	err  error
+	stop chan struct{}
+	done chan struct{}
+}
+
+// leaseGrant is the result of the `lease_grant` action.
+type leaseGrant struct {
+	ID  string `json:"id"`
+	TTL int64  `json:"ttl"`
+}
+
+// GrantLease creates a lease with the given TTL using the `lease_grant`
+// action. The server may round the TTL up; Lease.TTL holds the granted value.
+func (c *RocksDBClient) GrantLease(ttl time.Duration) (*Lease, error) {
+	seconds, err := ttlOption(ttl)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.SendRequest(Request{
+		Action:  ActionLeaseGrant,
+		Options: map[string]string{OptionTTL: seconds},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var grant leaseGrant
+	if err := json.Unmarshal([]byte(response.Result), &grant); err != nil {
+		return nil, fmt.Errorf("error decoding lease_grant result: %w", err)
+	}
+	return &Lease{c: c, ID: grant.ID, TTL: time.Duration(grant.TTL) * time.Second}, nil
+}
+
+// PutEphemeral stores a key-value pair attached to the lease leaseID. The key
+// is removed together with the lease, which makes it suitable for presence
+// tracking such as service registries.
+func (c *RocksDBClient) PutEphemeral(key, value, leaseID string, cfName *string) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionPut,
+		Key:     &key,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{OptionLeaseID: leaseID},
+	})
+	return err
+}
+
+// KeepAlive renews the lease for another TTL. It fails with ErrLeaseExpired
+// once the server dropped the lease.
+func (l *Lease) KeepAlive() error {
+	return l.send(ActionLeaseKeepAlive)
+}
+
+// Revoke ends the lease and deletes its keys.
+func (l *Lease) Revoke() error {
+	l.Stop()
+	return l.send(ActionLeaseRevoke)
+}
+
+func (l *Lease) send(action string) error {
+	_, err := l.c.SendRequest(Request{
+		Action:  action,
+		Options: map[string]string{OptionLeaseID: l.ID},
+	})
+	return err
+}
+
+// Start keeps the lease alive in the background, renewing it three times per
+// TTL. Renewal stops after the first error, which Err reports.
+func (l *Lease) Start() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stop != nil {
+		return
+	}
+	interval := l.TTL / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	l.stop, l.done = stop, done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.KeepAlive(); err != nil {
+					l.mu.Lock()
+					l.err = err
+					l.mu.Unlock()
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends background renewal started with Start. The lease then expires
+// after its TTL unless KeepAlive is called.
+func (l *Lease) Stop() {
+	l.mu.Lock()
+	stop, done := l.stop, l.done
+	l.stop, l.done = nil, nil
+	l.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Err returns the error that stopped background renewal, if any.
+func (l *Lease) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
@@ -0,0 +1,100 @@
+package rocksdbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// patchOperation is one JSON Patch (RFC 6902) operation as understood by the
+// server's json_merge operator.
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// AppendToList appends element to the JSON array stored under key and
+// returns the list as read back after the append. The append is a merge
+// with an "add /-" patch, so concurrent appends from several clients are all
+// kept; a missing key starts as an empty array.
+func AppendToList[T any](c *RocksDBClient, key string, element T, cfName *string) ([]T, error) {
+	patch, err := json.Marshal([]patchOperation{{Op: "add", Path: "/-", Value: element}})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding element of %q: %w", key, err)
+	}
+	encoded := string(patch)
+	if _, err := c.Merge(&key, &encoded, cfName, nil); err != nil {
+		return nil, err
+	}
+	return GetList[T](c, key, cfName)
+}
+
+// GetList reads the JSON array stored under key. A missing key yields an
+// empty list.
+func GetList[T any](c *RocksDBClient, key string, cfName *string) ([]T, error) {
+	list, err := GetAs[[]T](c, key, cfName)
+	if errors.Is(err, ErrKeyNotFound) {
+		return []T{}, nil
+	}
+	return list, err
+}
+
+// AddToSet adds element to the JSON array stored under key unless an equal
+// element is already present, and reports whether it was added. Elements are
+// compared by their JSON encoding. JSON Patch cannot express a conditional
+// add, so the update is a CAS loop: it retries with the stored value CAS
+// returns until no other writer interferes. Like Upsert, retries back off
+// and are capped by the client's transaction retry policy, after which
+// AddToSet fails with ErrTxnConflict.
+func AddToSet[T any](c *RocksDBClient, key string, element T, cfName *string) (bool, error) {
+	encoded, err := json.Marshal(element)
+	if err != nil {
+		return false, fmt.Errorf("error encoding element of %q: %w", key, err)
+	}
+
+	response, err := c.Get(&key, cfName, nil, nil)
+	var current *string
+	switch {
+	case err == nil:
+		current = &response.Result
+	case !errors.Is(err, ErrKeyNotFound):
+		return false, err
+	}
+
+	c.mu.Lock()
+	policy := c.txnRetry
+	c.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		var members []json.RawMessage
+		if current != nil {
+			if err := json.Unmarshal([]byte(*current), &members); err != nil {
+				return false, fmt.Errorf("error decoding set %q: %w", key, err)
+			}
+		}
+		for _, member := range members {
+			var compact bytes.Buffer
+			if json.Compact(&compact, member) == nil && bytes.Equal(compact.Bytes(), encoded) {
+				return false, nil
+			}
+		}
+
+		updated, err := json.Marshal(append(members, encoded))
+		if err != nil {
+			return false, fmt.Errorf("error encoding set %q: %w", key, err)
+		}
+		swapped, actual, err := c.CAS(key, current, string(updated), cfName)
+		if err != nil || swapped {
+			return swapped, err
+		}
+		if attempt >= policy.MaxRetries {
+			return false, fmt.Errorf("%w: add to set %q lost %d races", ErrTxnConflict, key, attempt+1)
+		}
+		if err := policy.wait(context.Background(), attempt); err != nil {
+			return false, err
+		}
+		current = actual
+	}
+}
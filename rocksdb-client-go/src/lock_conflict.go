@@ -0,0 +1,89 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrLockConflict matches the *LockConflictError returned when a
+// transactional operation fails on a lock held by another transaction.
+var ErrLockConflict = errors.New("lock conflict")
+
+// LockConflictError describes a transactional operation that failed on lock
+// contention: a lock wait timeout, a busy key or a detected deadlock. It
+// matches ErrLockConflict and ErrTxnConflict, so WithTransaction retries it,
+// and errors.As with *ServerError yields the raw server message.
+type LockConflictError struct {
+	// Key and CF identify the key the operation tried to lock; Key is empty
+	// for operations without a single key, such as commits.
+	Key string
+	CF  string
+	// TxnID is the transaction whose operation failed.
+	TxnID string
+	// Holder is the ID of the transaction holding the lock, if the server
+	// reported it with a "holder=<id>" fragment in the message.
+	Holder string
+	// Deadlock reports that the server detected a deadlock rather than a
+	// lock wait timeout.
+	Deadlock bool
+	Err      *ServerError
+}
+
+func (e *LockConflictError) Error() string {
+	var b strings.Builder
+	b.WriteString("lock conflict")
+	if e.Deadlock {
+		b.WriteString(" (deadlock)")
+	}
+	if e.Key != "" {
+		fmt.Fprintf(&b, " on key %q", e.Key)
+		if e.CF != "" {
+			fmt.Fprintf(&b, " in %s", e.CF)
+		}
+	}
+	if e.Holder != "" {
+		fmt.Fprintf(&b, " held by transaction %s", e.Holder)
+	}
+	fmt.Fprintf(&b, ": %s", e.Err.Message)
+	return b.String()
+}
+
+func (e *LockConflictError) Unwrap() []error {
+	return []error{ErrLockConflict, e.Err}
+}
+
+// lockConflict turns a conflict error of a transactional request into a
+// *LockConflictError and returns other errors unchanged.
+func lockConflict(err error, request Request, txnID string) error {
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || !errors.Is(serverErr, ErrTxnConflict) {
+		return err
+	}
+	conflict := &LockConflictError{
+		TxnID:    txnID,
+		Holder:   messageField(serverErr.Message, "holder="),
+		Deadlock: strings.Contains(serverErr.Message, "Deadlock"),
+		Err:      serverErr,
+	}
+	if request.Key != nil {
+		conflict.Key = *request.Key
+	}
+	if request.CfName != nil {
+		conflict.CF = *request.CfName
+	}
+	return conflict
+}
+
+// messageField returns the value following prefix in message, up to the next
+// space, comma or closing parenthesis.
+func messageField(message, prefix string) string {
+	_, rest, found := strings.Cut(message, prefix)
+	if !found {
+		return ""
+	}
+	if end := strings.IndexAny(rest, " ,)"); end >= 0 {
+		rest = rest[:end]
+	}
+	return rest
+}
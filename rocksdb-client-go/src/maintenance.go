@@ -0,0 +1,178 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// While the server is in maintenance (read-only) mode it rejects writes with
+// a "Maintenance mode" error, classified as ErrMaintenance, and keeps serving
+// reads. Callers can handle ErrMaintenance themselves or install a
+// MaintenanceQueue to hold blind writes until maintenance ends.
+
+// ErrWriteQueued is returned by writes that a MaintenanceQueue accepted for
+// replay instead of sending. It matches ErrMaintenance as well.
+var ErrWriteQueued = fmt.Errorf("%w: write queued for replay", ErrMaintenance)
+
+// MaintenanceQueueOptions configures a MaintenanceQueue.
+type MaintenanceQueueOptions struct {
+	// MaxQueued caps the number of held writes; once full, writes fail with
+	// ErrMaintenance. Defaults to 10000.
+	MaxQueued int
+	// RetryInterval is how often the oldest held write is retried to detect
+	// the end of maintenance. Defaults to 1s.
+	RetryInterval time.Duration
+	// OnError is called for held writes that fail with an error other than
+	// ErrMaintenance when replayed; they are dropped afterwards.
+	OnError func(Request, error)
+}
+
+type queuedWrite struct {
+	request Request
+	next    Handler
+}
+
+// MaintenanceQueue holds blind writes (put, merge, delete, delete_range and
+// batch_write outside transactions) rejected with ErrMaintenance in memory
+// and replays them in order once the server accepts writes again. Writes
+// issued while others are held are queued behind them without being sent,
+// so the order of writes is preserved. Held writes are lost if the process
+// exits; writes whose result matters, like CAS, are never queued.
+type MaintenanceQueue struct {
+	opts MaintenanceQueueOptions
+
+	mu        sync.Mutex
+	queue     []queuedWrite
+	replaying bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewMaintenanceQueue creates a maintenance queue and installs it as an
+// interceptor of c.
+func NewMaintenanceQueue(c *RocksDBClient, opts MaintenanceQueueOptions) *MaintenanceQueue {
+	if opts.MaxQueued <= 0 {
+		opts.MaxQueued = 10000
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+	q := &MaintenanceQueue{opts: opts, stop: make(chan struct{})}
+	c.Use(q.intercept)
+	return q
+}
+
+func isBlindWrite(request Request) bool {
+	if request.Txn != nil && *request.Txn {
+		return false
+	}
+	switch request.Action {
+	case ActionPut, ActionMerge, ActionDelete, ActionDeleteRange, ActionBatchWrite:
+		return true
+	}
+	return false
+}
+
+func (q *MaintenanceQueue) intercept(request Request, next Handler) (*Response, error) {
+	if !isBlindWrite(request) {
+		return next(request)
+	}
+
+	q.mu.Lock()
+	held := len(q.queue) > 0
+	q.mu.Unlock()
+	if !held {
+		response, err := next(request)
+		if !errors.Is(err, ErrMaintenance) {
+			return response, err
+		}
+	}
+	return nil, q.enqueue(request, next)
+}
+
+func (q *MaintenanceQueue) enqueue(request Request, next Handler) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.queue) >= q.opts.MaxQueued {
+		return fmt.Errorf("%w: queue full", ErrMaintenance)
+	}
+	q.queue = append(q.queue, queuedWrite{request, next})
+	if !q.replaying {
+		q.replaying = true
+		q.done = make(chan struct{})
+		go q.replay()
+	}
+	return ErrWriteQueued
+}
+
+// replay retries the oldest held write every RetryInterval and drains the
+// queue once it succeeds.
+func (q *MaintenanceQueue) replay() {
+	defer close(q.done)
+	ticker := time.NewTicker(q.opts.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		}
+		for {
+			q.mu.Lock()
+			if len(q.queue) == 0 {
+				q.replaying = false
+				q.mu.Unlock()
+				return
+			}
+			w := q.queue[0]
+			q.mu.Unlock()
+
+			_, err := w.next(w.request)
+			if errors.Is(err, ErrMaintenance) {
+				break
+			}
+			if err != nil && q.opts.OnError != nil {
+				q.opts.OnError(w.request, err)
+			}
+			q.mu.Lock()
+			q.queue = q.queue[1:]
+			q.mu.Unlock()
+		}
+	}
+}
+
+// Len returns the number of held writes.
+func (q *MaintenanceQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
+
+// Close stops replaying. Writes still held are discarded and returned, e.g.
+// to be persisted elsewhere. The interceptor stays installed, so later
+// writes rejected for maintenance fail with ErrMaintenance.
+func (q *MaintenanceQueue) Close() []Request {
+	q.mu.Lock()
+	select {
+	case <-q.stop:
+	default:
+		close(q.stop)
+	}
+	done := q.done
+	q.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	held := make([]Request, len(q.queue))
+	for i, w := range q.queue {
+		held[i] = w.request
+	}
+	q.queue = nil
+	q.opts.MaxQueued = 0
+	return held
+}
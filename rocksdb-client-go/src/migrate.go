@@ -0,0 +1,241 @@
+package rocksdbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// DefaultMigrationVersionKey is the key that stores the schema version.
+const DefaultMigrationVersionKey = "__schema_version"
+
+// Migration is one step of evolving the key layout or value schema of a
+// dataset. Up reads through tx.Client and must write through tx, so that
+// writes are fenced by the migration lock and recorded in dry runs. Up may
+// run again if it fails part way, so it should be idempotent.
+type Migration struct {
+	// Version orders migrations; it must be positive and unique.
+	Version int
+	Name    string
+	Up      func(ctx context.Context, tx *MigrationTx) error
+}
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// VersionKey stores the version of the last applied migration; it also
+	// names the migration lock. Defaults to DefaultMigrationVersionKey.
+	VersionKey string
+	// CF is the column family of the version key; empty means the default
+	// one.
+	CF string
+	// LockTTL is how long the migration lock is held; the whole run must
+	// finish within it. Defaults to 10m.
+	LockTTL time.Duration
+}
+
+// Migrator applies pending migrations in order. Concurrent deployments
+// coordinate through a lock on the version key, so only one of them runs
+// the migrations and the others find them applied.
+type Migrator struct {
+	c          *RocksDBClient
+	opts       MigratorOptions
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator for migrations, which must be listed in
+// ascending Version order.
+func NewMigrator(c *RocksDBClient, opts MigratorOptions, migrations ...Migration) (*Migrator, error) {
+	if opts.VersionKey == "" {
+		opts.VersionKey = DefaultMigrationVersionKey
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 10 * time.Minute
+	}
+	last := 0
+	for _, m := range migrations {
+		if m.Version <= last {
+			return nil, fmt.Errorf("migration %d (%s): versions must be positive and ascending", m.Version, m.Name)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d (%s): Up must be set", m.Version, m.Name)
+		}
+		last = m.Version
+	}
+	return &Migrator{c: c, opts: opts, migrations: migrations}, nil
+}
+
+func (m *Migrator) cfName() *string {
+	if m.opts.CF == "" {
+		return nil
+	}
+	return &m.opts.CF
+}
+
+// Version returns the version of the last applied migration, 0 if none was.
+func (m *Migrator) Version() (int, error) {
+	response, err := m.c.Get(&m.opts.VersionKey, m.cfName(), nil, nil)
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(response.Result)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding schema version %q: %w", response.Result, err)
+	}
+	return version, nil
+}
+
+// Pending returns the migrations newer than the current version.
+func (m *Migrator) Pending() ([]Migration, error) {
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	for i, migration := range m.migrations {
+		if migration.Version > version {
+			return m.migrations[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// MigrationRun reports one migration run by Up or DryRun.
+type MigrationRun struct {
+	Version  int
+	Name     string
+	Duration time.Duration
+	// Planned lists the writes of a dry run.
+	Planned []Operation
+}
+
+// Up takes the migration lock and applies the pending migrations in order,
+// storing the version after each one. It stops at the first failure; the
+// failed migration runs again on the next Up. If another process holds the
+// lock, Up fails with ErrLockHeld. A panic in a migration is returned as a
+// *PanicError wrapped with the migration's version and name.
+func (m *Migrator) Up(ctx context.Context) ([]MigrationRun, error) {
+	lock, err := m.c.AcquireLock(m.opts.VersionKey, m.opts.LockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+	writer := lock.Fenced()
+
+	// Read the version under the lock, as another process may have just
+	// finished migrating.
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	var runs []MigrationRun
+	for _, migration := range pending {
+		run, err := m.run(ctx, migration, &MigrationTx{Client: m.c, writer: writer})
+		if err != nil {
+			return runs, err
+		}
+		if err := writer.Put(m.opts.VersionKey, strconv.Itoa(migration.Version), m.cfName()); err != nil {
+			return runs, fmt.Errorf("error storing schema version %d: %w", migration.Version, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// DryRun runs the pending migrations without the lock, recording their
+// writes in MigrationRun.Planned instead of applying them. Migrations that
+// read what earlier ones wrote see the data unchanged.
+func (m *Migrator) DryRun(ctx context.Context) ([]MigrationRun, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	var runs []MigrationRun
+	for _, migration := range pending {
+		tx := &MigrationTx{Client: m.c, dryRun: true}
+		run, err := m.run(ctx, migration, tx)
+		run.Planned = tx.planned
+		runs = append(runs, run)
+		if err != nil {
+			return runs, err
+		}
+	}
+	return runs, nil
+}
+
+func (m *Migrator) run(ctx context.Context, migration Migration, tx *MigrationTx) (MigrationRun, error) {
+	run := MigrationRun{Version: migration.Version, Name: migration.Name}
+	if err := ctx.Err(); err != nil {
+		return run, err
+	}
+	start := time.Now()
+	err := callMigration(ctx, migration, tx)
+	run.Duration = time.Since(start)
+	if err != nil {
+		return run, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	return run, nil
+}
+
+// callMigration calls migration.Up and converts a panic into a *PanicError,
+// so the version is not stored and the lock is released as for any failure.
+func callMigration(ctx context.Context, migration Migration, tx *MigrationTx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Offset: -1, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return migration.Up(ctx, tx)
+}
+
+// MigrationTx is passed to Migration.Up. Its writes carry the migration
+// lock's fencing token, or are only recorded in a dry run.
+type MigrationTx struct {
+	// Client reads the data being migrated.
+	Client *RocksDBClient
+
+	writer  *FencedWriter
+	dryRun  bool
+	planned []Operation
+}
+
+// DryRun reports whether writes are only recorded.
+func (tx *MigrationTx) DryRun() bool {
+	return tx.dryRun
+}
+
+func (tx *MigrationTx) plan(op Operation) error {
+	if err := op.validate(); err != nil {
+		return err
+	}
+	tx.planned = append(tx.planned, op)
+	return nil
+}
+
+// Put stores a key-value pair.
+func (tx *MigrationTx) Put(key, value string, cfName *string) error {
+	if tx.dryRun {
+		return tx.plan(Operation{Type: OpPut, Key: key, Value: &value, CfName: cfName})
+	}
+	return tx.writer.Put(key, value, cfName)
+}
+
+// Merge applies a merge operand to key.
+func (tx *MigrationTx) Merge(key, value string, cfName *string) error {
+	if tx.dryRun {
+		return tx.plan(Operation{Type: OpMerge, Key: key, Value: &value, CfName: cfName})
+	}
+	return tx.writer.Merge(key, value, cfName)
+}
+
+// Delete removes key.
+func (tx *MigrationTx) Delete(key string, cfName *string) error {
+	if tx.dryRun {
+		return tx.plan(Operation{Type: OpDelete, Key: key, CfName: cfName})
+	}
+	return tx.writer.Delete(key, cfName)
+}
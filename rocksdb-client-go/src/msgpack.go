@@ -0,0 +1,514 @@
+package rocksdbclient
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// MsgpackCodec encodes requests and responses as MessagePack maps with the
+// same field names as the JSON protocol. Strings that are not valid UTF-8,
+// such as binary values, are sent as msgpack bin instead of being escaped.
+// It only handles Request and Response values and is meant for the wire
+// encoding negotiated with WithWireEncoding; it is exported for servers and
+// tests that speak the protocol.
+type MsgpackCodec struct{}
+
+var errMsgpackTruncated = errors.New("msgpack: unexpected end of data")
+
+// Marshal encodes a Request or Response.
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case Request:
+		return appendMsgpackRequest(make([]byte, 0, 128), &v), nil
+	case *Request:
+		return appendMsgpackRequest(make([]byte, 0, 128), v), nil
+	case Response:
+		return appendMsgpackResponse(make([]byte, 0, 64+len(v.Result)), &v), nil
+	case *Response:
+		return appendMsgpackResponse(make([]byte, 0, 64+len(v.Result)), v), nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type %T", v)
+}
+
+// Unmarshal decodes into a *Request or *Response. Unknown fields are
+// skipped.
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	r := &msgpackReader{data: data}
+	var err error
+	switch v := v.(type) {
+	case *Request:
+		err = r.readRequest(v)
+	case *Response:
+		err = r.readResponse(v)
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding msgpack: %w", err)
+	}
+	return nil
+}
+
+func appendMsgpackRequest(buf []byte, r *Request) []byte {
+	n := 1
+	for _, set := range []bool{
+		r.Key != nil, r.Value != nil, r.CfName != nil, r.DefaultValue != nil, len(r.Options) > 0,
+		r.Token != nil, r.Txn != nil, r.TxnID != nil, len(r.Keys) > 0, len(r.Operations) > 0, r.RequestID != "",
+	} {
+		if set {
+			n++
+		}
+	}
+	buf = appendMsgpackMapHeader(buf, n)
+	buf = appendMsgpackString(appendMsgpackString(buf, "action"), r.Action)
+	buf = appendMsgpackOptional(buf, "key", r.Key)
+	buf = appendMsgpackOptional(buf, "value", r.Value)
+	buf = appendMsgpackOptional(buf, "cf_name", r.CfName)
+	buf = appendMsgpackOptional(buf, "default_value", r.DefaultValue)
+	if len(r.Options) > 0 {
+		buf = appendMsgpackString(buf, "options")
+		buf = appendMsgpackMapHeader(buf, len(r.Options))
+		keys := make([]string, 0, len(r.Options))
+		for k := range r.Options {
+			keys = append(keys, k)
+		}
+		// Sorted like AppendJSON, so equal requests encode identically.
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf = appendMsgpackString(appendMsgpackString(buf, k), r.Options[k])
+		}
+	}
+	buf = appendMsgpackOptional(buf, "token", r.Token)
+	if r.Txn != nil {
+		buf = appendMsgpackBool(appendMsgpackString(buf, "txn"), *r.Txn)
+	}
+	buf = appendMsgpackOptional(buf, "txn_id", r.TxnID)
+	if len(r.Keys) > 0 {
+		buf = appendMsgpackString(buf, "keys")
+		buf = appendMsgpackArrayHeader(buf, len(r.Keys))
+		for _, k := range r.Keys {
+			buf = appendMsgpackString(buf, k)
+		}
+	}
+	if len(r.Operations) > 0 {
+		buf = appendMsgpackString(buf, "operations")
+		buf = appendMsgpackArrayHeader(buf, len(r.Operations))
+		for _, op := range r.Operations {
+			n := 2
+			if op.Value != nil {
+				n++
+			}
+			if op.CfName != nil {
+				n++
+			}
+			buf = appendMsgpackMapHeader(buf, n)
+			buf = appendMsgpackString(appendMsgpackString(buf, "type"), string(op.Type))
+			buf = appendMsgpackString(appendMsgpackString(buf, "key"), op.Key)
+			buf = appendMsgpackOptional(buf, "value", op.Value)
+			buf = appendMsgpackOptional(buf, "cf_name", op.CfName)
+		}
+	}
+	if r.RequestID != "" {
+		buf = appendMsgpackString(appendMsgpackString(buf, "request_id"), r.RequestID)
+	}
+	return buf
+}
+
+func appendMsgpackResponse(buf []byte, r *Response) []byte {
+	n := 2
+	if r.RequestID != "" {
+		n++
+	}
+	buf = appendMsgpackMapHeader(buf, n)
+	buf = appendMsgpackBool(appendMsgpackString(buf, "success"), r.Success)
+	buf = appendMsgpackString(appendMsgpackString(buf, "result"), r.Result)
+	if r.RequestID != "" {
+		buf = appendMsgpackString(appendMsgpackString(buf, "request_id"), r.RequestID)
+	}
+	return buf
+}
+
+func appendMsgpackOptional(buf []byte, name string, value *string) []byte {
+	if value == nil {
+		return buf
+	}
+	return appendMsgpackString(appendMsgpackString(buf, name), *value)
+}
+
+// appendMsgpackString writes s as str, or as bin when it is not valid UTF-8.
+func appendMsgpackString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case !utf8.ValidString(s):
+		switch {
+		case n <= math.MaxUint8:
+			buf = append(buf, 0xc4, byte(n))
+		case n <= math.MaxUint16:
+			buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+		default:
+			buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+		}
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+func appendMsgpackBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 0xc3)
+	}
+	return append(buf, 0xc2)
+}
+
+func appendMsgpackMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, 0xdf), uint32(n))
+}
+
+func appendMsgpackArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, 0xdd), uint32(n))
+}
+
+// msgpackReader decodes the subset of MessagePack used by the protocol and
+// skips anything else.
+type msgpackReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *msgpackReader) next(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, errMsgpackTruncated
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *msgpackReader) byte() (byte, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *msgpackReader) uint(size int) (int, error) {
+	b, err := r.next(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+// peekNil consumes a nil and reports whether there was one.
+func (r *msgpackReader) peekNil() bool {
+	if r.pos < len(r.data) && r.data[r.pos] == 0xc0 {
+		r.pos++
+		return true
+	}
+	return false
+}
+
+func (r *msgpackReader) mapLen() (int, error) {
+	t, err := r.byte()
+	n := 0
+	switch {
+	case err != nil:
+		return 0, err
+	case t&0xf0 == 0x80:
+		n = int(t & 0x0f)
+	case t == 0xde:
+		n, err = r.uint(2)
+	case t == 0xdf:
+		n, err = r.uint(4)
+	default:
+		return 0, fmt.Errorf("msgpack: expected map, got 0x%02x", t)
+	}
+	// Every entry takes at least two bytes.
+	return r.checkLen(n, 2, err)
+}
+
+func (r *msgpackReader) arrayLen() (int, error) {
+	t, err := r.byte()
+	n := 0
+	switch {
+	case err != nil:
+		return 0, err
+	case t&0xf0 == 0x90:
+		n = int(t & 0x0f)
+	case t == 0xdc:
+		n, err = r.uint(2)
+	case t == 0xdd:
+		n, err = r.uint(4)
+	default:
+		return 0, fmt.Errorf("msgpack: expected array, got 0x%02x", t)
+	}
+	return r.checkLen(n, 1, err)
+}
+
+// checkLen rejects a length of n elements of at least size bytes each that
+// the remaining input cannot hold, before anything is allocated for them.
+func (r *msgpackReader) checkLen(n, size int, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	if n > (len(r.data)-r.pos)/size {
+		return 0, errMsgpackTruncated
+	}
+	return n, nil
+}
+
+// string reads a str or bin value.
+func (r *msgpackReader) string() (string, error) {
+	t, err := r.byte()
+	if err != nil {
+		return "", err
+	}
+	var n int
+	switch {
+	case t&0xe0 == 0xa0:
+		n = int(t & 0x1f)
+	case t == 0xd9 || t == 0xc4:
+		n, err = r.uint(1)
+	case t == 0xda || t == 0xc5:
+		n, err = r.uint(2)
+	case t == 0xdb || t == 0xc6:
+		n, err = r.uint(4)
+	default:
+		return "", fmt.Errorf("msgpack: expected string, got 0x%02x", t)
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := r.next(n)
+	return string(b), err
+}
+
+func (r *msgpackReader) optionalString() (*string, error) {
+	if r.peekNil() {
+		return nil, nil
+	}
+	s, err := r.string()
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *msgpackReader) bool() (bool, error) {
+	t, err := r.byte()
+	switch {
+	case err != nil:
+		return false, err
+	case t == 0xc3:
+		return true, nil
+	case t == 0xc2:
+		return false, nil
+	}
+	return false, fmt.Errorf("msgpack: expected bool, got 0x%02x", t)
+}
+
+// skip consumes one value of any type.
+func (r *msgpackReader) skip() error {
+	t, err := r.byte()
+	if err != nil {
+		return err
+	}
+	var size, items int
+	switch {
+	case t <= 0x7f || t >= 0xe0 || t == 0xc0 || t == 0xc2 || t == 0xc3:
+		return nil
+	case t&0xf0 == 0x80:
+		items = 2 * int(t&0x0f)
+	case t&0xf0 == 0x90:
+		items = int(t & 0x0f)
+	case t&0xe0 == 0xa0:
+		size = int(t & 0x1f)
+	case t == 0xcc || t == 0xd0:
+		size = 1
+	case t == 0xcd || t == 0xd1:
+		size = 2
+	case t == 0xca || t == 0xce || t == 0xd2:
+		size = 4
+	case t == 0xcb || t == 0xcf || t == 0xd3:
+		size = 8
+	case t >= 0xd4 && t <= 0xd8:
+		size = 1 + 1<<(t-0xd4)
+	case t == 0xc4 || t == 0xd9:
+		size, err = r.uint(1)
+	case t == 0xc5 || t == 0xda:
+		size, err = r.uint(2)
+	case t == 0xc6 || t == 0xdb:
+		size, err = r.uint(4)
+	case t == 0xc7 || t == 0xc8 || t == 0xc9:
+		size, err = r.uint(1 << (t - 0xc7))
+		size++
+	case t == 0xdc:
+		items, err = r.uint(2)
+	case t == 0xdd:
+		items, err = r.uint(4)
+	case t == 0xde:
+		items, err = r.uint(2)
+		items *= 2
+	case t == 0xdf:
+		items, err = r.uint(4)
+		items *= 2
+	default:
+		return fmt.Errorf("msgpack: invalid type 0x%02x", t)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := r.next(size); err != nil {
+		return err
+	}
+	for range items {
+		if err := r.skip(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fields calls fn with the name of every field of a map.
+func (r *msgpackReader) fields(fn func(name string) error) error {
+	n, err := r.mapLen()
+	if err != nil {
+		return err
+	}
+	for range n {
+		name, err := r.string()
+		if err != nil {
+			return err
+		}
+		if err := fn(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *msgpackReader) readResponse(response *Response) error {
+	return r.fields(func(name string) (err error) {
+		switch name {
+		case "success":
+			response.Success, err = r.bool()
+		case "result":
+			if !r.peekNil() {
+				response.Result, err = r.string()
+			}
+		case "request_id":
+			response.RequestID, err = r.string()
+		default:
+			err = r.skip()
+		}
+		return err
+	})
+}
+
+func (r *msgpackReader) readRequest(request *Request) error {
+	return r.fields(func(name string) (err error) {
+		switch name {
+		case "action":
+			request.Action, err = r.string()
+		case "key":
+			request.Key, err = r.optionalString()
+		case "value":
+			request.Value, err = r.optionalString()
+		case "cf_name":
+			request.CfName, err = r.optionalString()
+		case "default_value":
+			request.DefaultValue, err = r.optionalString()
+		case "token":
+			request.Token, err = r.optionalString()
+		case "txn_id":
+			request.TxnID, err = r.optionalString()
+		case "request_id":
+			request.RequestID, err = r.string()
+		case "txn":
+			var txn bool
+			if txn, err = r.bool(); err == nil {
+				request.Txn = &txn
+			}
+		case "options":
+			request.Options = map[string]string{}
+			err = r.fields(func(key string) error {
+				value, err := r.string()
+				request.Options[key] = value
+				return err
+			})
+		case "keys":
+			var n int
+			if n, err = r.arrayLen(); err != nil {
+				return err
+			}
+			request.Keys = make([]string, n)
+			for i := range request.Keys {
+				if request.Keys[i], err = r.string(); err != nil {
+					return err
+				}
+			}
+		case "operations":
+			var n int
+			if n, err = r.arrayLen(); err != nil {
+				return err
+			}
+			request.Operations = make([]Operation, n)
+			for i := range request.Operations {
+				op := &request.Operations[i]
+				err = r.fields(func(name string) (err error) {
+					switch name {
+					case "type":
+						var t string
+						t, err = r.string()
+						op.Type = OperationType(t)
+					case "key":
+						op.Key, err = r.string()
+					case "value":
+						op.Value, err = r.optionalString()
+					case "cf_name":
+						op.CfName, err = r.optionalString()
+					default:
+						err = r.skip()
+					}
+					return err
+				})
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			err = r.skip()
+		}
+		return err
+	})
+}
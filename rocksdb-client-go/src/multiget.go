@@ -0,0 +1,88 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MultiGet fetches several keys in a single round trip using the `multi_get`
+// action. Keys that do not exist map to nil in the returned map.
+func (c *RocksDBClient) MultiGet(keys []string, cfName *string) (map[string]*string, error) {
+	return c.MultiGetWith(keys, cfName)
+}
+
+// MultiGetWith is MultiGet with call options such as WithReadOptions.
+func (c *RocksDBClient) MultiGetWith(keys []string, cfName *string, opts ...CallOption) (map[string]*string, error) {
+	values := make(map[string]*string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	request := Request{
+		Action: ActionMultiGet,
+		Keys:   keys,
+		CfName: cfName,
+	}
+	applyCallOptions(&request, opts)
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(response.Result), &values); err != nil {
+		return nil, fmt.Errorf("error decoding multi_get result: %w", err)
+	}
+	for _, key := range keys {
+		if _, found := values[key]; !found {
+			values[key] = nil
+		}
+	}
+	return values, nil
+}
+
+// CFKey addresses a key in a column family. An empty CF means the default
+// column family.
+type CFKey struct {
+	Key string `json:"key"`
+	CF  string `json:"cf_name,omitempty"`
+}
+
+// MultiGetCF fetches keys from several column families in a single round
+// trip using the `multi_get_cf` action. The keys are sent in Request.Keys,
+// like for MultiGet, and their column families as a JSON array in the
+// cf_names option, "" for the default one; the server answers with an array
+// of values in the same order, null for missing keys. Keys that do not exist
+// map to nil in the returned map.
+func (c *RocksDBClient) MultiGetCF(keys []CFKey) (map[CFKey]*string, error) {
+	values := make(map[CFKey]*string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	names := make([]string, len(keys))
+	request := Request{Action: ActionMultiGetCF, Keys: make([]string, len(keys)), Options: map[string]string{}}
+	for i, key := range keys {
+		request.Keys[i], names[i] = key.Key, key.CF
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	request.Options[OptionCFNames] = string(data)
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*string
+	if err := json.Unmarshal([]byte(response.Result), &results); err != nil {
+		return nil, fmt.Errorf("error decoding multi_get_cf result: %w", err)
+	}
+	if len(results) != len(keys) {
+		return nil, fmt.Errorf("multi_get_cf returned %d values for %d keys", len(results), len(keys))
+	}
+	for i, key := range keys {
+		values[key] = results[i]
+	}
+	return values, nil
+}
@@ -0,0 +1,88 @@
+package rocksdbclient
+
+import "strings"
+
+// defaultNamespacePageSize is the page size Namespace.Keys uses when paging
+// through the `keys` action.
+const defaultNamespacePageSize = 1000
+
+// Namespace is a view of a client that confines keys to a prefix, e.g. one
+// tenant of a multi-tenant application. Keys passed to it are relative to
+// the prefix, which is prepended on the way to the server and stripped from
+// keys it returns.
+//
+//	tenant := client.Namespace("tenant:42:")
+//	err := tenant.Put("user:1", "alice", nil) // stores "tenant:42:user:1"
+type Namespace struct {
+	c      *RocksDBClient
+	prefix string
+}
+
+// Namespace returns a view of the client whose keys are prefixed with prefix.
+func (c *RocksDBClient) Namespace(prefix string) *Namespace {
+	return &Namespace{c: c, prefix: prefix}
+}
+
+// Prefix returns the namespace prefix.
+func (ns *Namespace) Prefix() string {
+	return ns.prefix
+}
+
+// Key returns the full server-side key of key.
+func (ns *Namespace) Key(key string) string {
+	return ns.prefix + key
+}
+
+// Namespace returns a nested namespace, e.g. for per-tenant sub-areas.
+func (ns *Namespace) Namespace(prefix string) *Namespace {
+	return &Namespace{c: ns.c, prefix: ns.prefix + prefix}
+}
+
+// Get returns the value of key. Missing keys return an error matching
+// ErrKeyNotFound.
+func (ns *Namespace) Get(key string, cfName *string) (string, error) {
+	full := ns.Key(key)
+	response, err := ns.c.Get(&full, cfName, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
+
+// Put stores a key-value pair.
+func (ns *Namespace) Put(key, value string, cfName *string) error {
+	full := ns.Key(key)
+	_, err := ns.c.Put(&full, &value, cfName, nil)
+	return err
+}
+
+// Delete removes key.
+func (ns *Namespace) Delete(key string, cfName *string) error {
+	full := ns.Key(key)
+	_, err := ns.c.Delete(&full, cfName, nil)
+	return err
+}
+
+// Keys returns the keys of the namespace that contain query, without the
+// prefix. The server's `keys` action matches substrings anywhere in a key,
+// so keys that merely contain the prefix further in, such as
+// "tenant:1:tenant:42:x" for "tenant:42:", are filtered out on the client;
+// for the same reason all pages are fetched before returning.
+func (ns *Namespace) Keys(query string) ([]string, error) {
+	var keys []string
+	for start := 0; ; start += defaultNamespacePageSize {
+		page, err := ns.c.keysPage(ns.prefix, start, defaultNamespacePageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range page {
+			key, found := strings.CutPrefix(key, ns.prefix)
+			if found && strings.Contains(key, query) {
+				keys = append(keys, key)
+			}
+		}
+		if len(page) < defaultNamespacePageSize {
+			return keys, nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package rocksdbclient
+
+import (
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Defaults used by NewClient.
+const (
+	DefaultTimeout       = 5 * time.Second
+	DefaultRetryInterval = 100 * time.Millisecond
+)
+
+// Option configures a client created with NewClient.
+type Option func(*RocksDBClient)
+
+// NewClient creates a client for the server at addr (host:port). The
+// connection is established lazily on the first request or by Connect.
+//
+//	client := rocksdbclient.NewClient("127.0.0.1:12345",
+//		rocksdbclient.WithToken(token),
+//		rocksdbclient.WithRequestTimeout(2*time.Second),
+//	)
+func NewClient(addr string, opts ...Option) *RocksDBClient {
+	c := &RocksDBClient{
+		endpoints:     []string{addr},
+		timeout:       DefaultTimeout,
+		retryInterval: DefaultRetryInterval,
+		codec:         stdJSONCodec{},
+		txnRetry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithToken authenticates every request with token.
+func WithToken(token string) Option {
+	return func(c *RocksDBClient) {
+		c.token = &token
+	}
+}
+
+// WithTimeout sets how long connecting may take, including retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *RocksDBClient) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetryInterval sets the pause between connection attempts.
+func WithRetryInterval(interval time.Duration) Option {
+	return func(c *RocksDBClient) {
+		c.retryInterval = interval
+	}
+}
+
+// WithRequestTimeout bounds each round trip, see SetRequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *RocksDBClient) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithTLS connects over TLS with the given configuration, e.g. to a server
+// behind a TLS-terminating proxy.
+func WithTLS(config *tls.Config) Option {
+	return func(c *RocksDBClient) {
+		c.tlsConfig = config
+	}
+}
+
+// WithRetryPolicy sets how WithTransaction retries conflicting transactions,
+// see SetTransactionRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *RocksDBClient) {
+		c.txnRetry = policy
+	}
+}
+
+// WithTransactionHeartbeat keeps every transaction run by WithTransaction
+// alive in the background, see Transaction.StartHeartbeat.
+func WithTransactionHeartbeat(opts HeartbeatOptions) Option {
+	return func(c *RocksDBClient) {
+		c.txnHeartbeat = &opts
+	}
+}
+
+// WithDefaultCF sends requests that do not name a column family to cf
+// instead of the server's default column family. Operations passed to
+// BatchWrite keep their own column family.
+func WithDefaultCF(cf string) Option {
+	return func(c *RocksDBClient) {
+		c.defaultCF = &cf
+	}
+}
+
+// WithDefaultColumnFamily is an alias of WithDefaultCF. To use several
+// column families without passing names around, see RocksDBClient.CF.
+func WithDefaultColumnFamily(cf string) Option {
+	return WithDefaultCF(cf)
+}
+
+// WithLogger reports connection retries (debug) and connection resets
+// (warn) to logger. By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *RocksDBClient) {
+		c.logger = logger
+	}
+}
+
+// WithJSONCodec replaces encoding/json, see SetJSONCodec.
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(c *RocksDBClient) {
+		c.codec = codec
+	}
+}
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func (c *RocksDBClient) log() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
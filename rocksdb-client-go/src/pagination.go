@@ -0,0 +1,70 @@
+package rocksdbclient
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+)
+
+// ErrInvalidCursor is returned when a cursor token is malformed or was issued
+// for a different listing than the one it is passed to.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page is one page of a paginated listing. Next is an opaque cursor token for
+// the following page and is empty after the last one; pass it back unchanged
+// to the same call to continue.
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+// HasMore reports whether another page follows.
+func (p *Page[T]) HasMore() bool {
+	return p.Next != ""
+}
+
+// EncodeCursor builds an opaque cursor token from the last key of a page and
+// the hash of the filter that produced it. Tokens are URL-safe, so they can
+// be handed to API consumers as is.
+func EncodeCursor(lastKey string, filterHash uint64) string {
+	buf := make([]byte, 8, 8+len(lastKey))
+	binary.BigEndian.PutUint64(buf, filterHash)
+	return base64.RawURLEncoding.EncodeToString(append(buf, lastKey...))
+}
+
+// DecodeCursor is the inverse of EncodeCursor.
+func DecodeCursor(token string) (lastKey string, filterHash uint64, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < 8 {
+		return "", 0, ErrInvalidCursor
+	}
+	return string(data[8:]), binary.BigEndian.Uint64(data), nil
+}
+
+// FilterHash hashes the parameters that define a listing. Cursors carry it so
+// a token cannot silently be reused with a different prefix or filter.
+func FilterHash(parts ...string) uint64 {
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// decodeCursorFor returns the last key of a token issued for filterHash. An
+// empty token starts at the beginning.
+func decodeCursorFor(token string, filterHash uint64) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	lastKey, hash, err := DecodeCursor(token)
+	if err != nil {
+		return "", err
+	}
+	if hash != filterHash {
+		return "", ErrInvalidCursor
+	}
+	return lastKey, nil
+}
@@ -0,0 +1,209 @@
+package rocksdbclient
+
+import (
+	"sync"
+)
+
+// KeyRange is a half-open key interval [Start, End). An empty End means the
+// range is unbounded above.
+type KeyRange struct {
+	Start string
+	End   string
+}
+
+// Contains reports whether key falls inside the range.
+func (r KeyRange) Contains(key string) bool {
+	return key >= r.Start && (r.End == "" || key < r.End)
+}
+
+// prefixEnd returns the smallest key greater than every key starting with
+// prefix, or "" when no such key exists.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+// splitFanout is the number of slices a range is cut into per byte of key;
+// split points stay within ASCII so they survive the JSON protocol.
+const splitFanout = 128
+
+// maxSplitDepth bounds how many bytes past the prefix SplitRanges looks at.
+const maxSplitDepth = 4
+
+// sizedSlice is a candidate range of SplitRanges. All its keys start with
+// base, unless it holds the keys beyond the ASCII range of its parent.
+type sizedSlice struct {
+	r         KeyRange
+	base      string
+	size      uint64
+	divisible bool
+}
+
+// subdivide cuts s into one slice per next byte of key.
+func (s sizedSlice) subdivide() []sizedSlice {
+	cuts := make([]sizedSlice, splitFanout)
+	for i := range cuts {
+		start := s.base + string(rune(i))
+		cuts[i] = sizedSlice{r: KeyRange{Start: start, End: prefixEnd(start)}, base: start, divisible: true}
+	}
+	cuts[0].r.Start = s.r.Start
+	last := &cuts[splitFanout-1]
+	last.r.End, last.divisible = s.r.End, false
+	return cuts
+}
+
+// SplitRanges splits the keys starting with prefix into up to parts ranges of
+// roughly equal size on disk, as estimated by get_approximate_sizes. The key
+// space is cut at the byte following the prefix, and slices bigger than a
+// part are cut further, a few bytes deep; the ranges are therefore only
+// approximately balanced. When the server reports no data a single range is
+// returned.
+func (c *RocksDBClient) SplitRanges(prefix string, parts int) ([]KeyRange, error) {
+	whole := KeyRange{Start: prefix, End: prefixEnd(prefix)}
+	if parts <= 1 {
+		return []KeyRange{whole}, nil
+	}
+
+	cuts := sizedSlice{r: whole, base: prefix}.subdivide()
+	if err := c.measure(cuts); err != nil {
+		return nil, err
+	}
+	var total uint64
+	for _, s := range cuts {
+		total += s.size
+	}
+	if total == 0 {
+		return []KeyRange{whole}, nil
+	}
+	target := total / uint64(parts)
+
+	for depth := 1; depth < maxSplitDepth; depth++ {
+		split := func(s sizedSlice) bool { return s.divisible && s.size > target }
+		var children []sizedSlice
+		for _, s := range cuts {
+			if split(s) {
+				children = append(children, s.subdivide()...)
+			}
+		}
+		if children == nil {
+			break
+		}
+		if err := c.measure(children); err != nil {
+			return nil, err
+		}
+		refined := make([]sizedSlice, 0, len(cuts)+len(children))
+		for _, s := range cuts {
+			if split(s) {
+				refined = append(refined, children[:splitFanout]...)
+				children = children[splitFanout:]
+			} else {
+				refined = append(refined, s)
+			}
+		}
+		cuts = refined
+	}
+
+	var bounds []string
+	var sum uint64
+	next := 1
+	for _, s := range cuts {
+		if next < parts && sum >= total*uint64(next)/uint64(parts) {
+			if s.r.Start > whole.Start {
+				bounds = append(bounds, s.r.Start)
+			}
+			for next < parts && sum >= total*uint64(next)/uint64(parts) {
+				next++
+			}
+		}
+		sum += s.size
+	}
+
+	ranges := make([]KeyRange, 0, len(bounds)+1)
+	start := whole.Start
+	for _, bound := range bounds {
+		ranges = append(ranges, KeyRange{Start: start, End: bound})
+		start = bound
+	}
+	return append(ranges, KeyRange{Start: start, End: whole.End}), nil
+}
+
+// measure fills in the approximate sizes of slices.
+func (c *RocksDBClient) measure(cuts []sizedSlice) error {
+	ranges := make([]KeyRange, len(cuts))
+	for i, s := range cuts {
+		ranges[i] = s.r
+	}
+	sizes, err := c.ApproximateSizes(ranges, nil)
+	if err != nil {
+		return err
+	}
+	for i := range cuts {
+		cuts[i].size = sizes[i]
+	}
+	return nil
+}
+
+// ParallelScan runs one iterator per range, each on its own pooled
+// connection, and calls fn for every entry. fn is invoked concurrently and
+// must be safe for concurrent use. The first error stops all scans and is
+// returned; a panic in fn is returned as a *PanicError whose Offset is the
+// position within the entry's range.
+func (p *Pool) ParallelScan(ranges []KeyRange, fn func(key, value string) error) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		stop     = make(chan struct{})
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(stop)
+		})
+	}
+
+	for _, r := range ranges {
+		wg.Add(1)
+		go func(r KeyRange) {
+			defer wg.Done()
+			c := p.Get()
+			defer p.Put(c)
+			if err := c.scanRange(r, stop, fn); err != nil {
+				fail(err)
+			}
+		}(r)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (c *RocksDBClient) scanRange(r KeyRange, stop <-chan struct{}, fn func(key, value string) error) error {
+	it, err := c.NewIterator()
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var offset int64
+	for key, value := range it.From(r.Start) {
+		if !r.Contains(key) {
+			break
+		}
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if err := callScanFunc(fn, key, value, offset); err != nil {
+			return err
+		}
+		offset++
+	}
+	return it.Err()
+}
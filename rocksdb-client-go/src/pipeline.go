@@ -0,0 +1,261 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithPipelining lets concurrent requests share the connection without
+// waiting for each other: every request carries a request_id and is written
+// as soon as it is sent, and a background reader hands each response to the
+// request with the same ID. Responses without an ID, from servers that do not
+// echo it, are matched in order, which is correct as long as the server
+// answers the requests of a connection in order.
+//
+// A request that times out fails with ErrTimeout without resetting the
+// connection; its late response is discarded. Interceptors and transactions
+// work as without pipelining.
+func WithPipelining() Option {
+	return func(c *RocksDBClient) {
+		c.pipelining = true
+	}
+}
+
+// pipelineResult is a response delivered by the pipeline reader.
+type pipelineResult struct {
+	response *Response
+	size     int
+	err      error
+}
+
+// pipeline matches responses on one connection to the requests waiting for
+// them.
+type pipeline struct {
+	conn net.Conn
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[string]chan pipelineResult
+	// order lists the IDs of pending requests in the order they were sent,
+	// for responses that do not carry an ID.
+	order []string
+	// echoes is set once the server echoed a request ID.
+	echoes bool
+	err    error
+}
+
+func newPipeline(conn net.Conn, reader *bufio.Reader, codec JSONCodec, wire wireFormat) *pipeline {
+	p := &pipeline{conn: conn, pending: map[string]chan pipelineResult{}}
+	go p.read(reader, codec, wire)
+	return p
+}
+
+// register assigns the next request ID and returns the channel its response
+// will be delivered on.
+func (p *pipeline) register() (string, chan pipelineResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return "", nil, p.err
+	}
+	p.nextID++
+	id := strconv.FormatUint(p.nextID, 10)
+	// Buffered, so the reader never blocks on a request that gave up.
+	ch := make(chan pipelineResult, 1)
+	p.pending[id] = ch
+	p.order = append(p.order, id)
+	return id, ch, nil
+}
+
+func (p *pipeline) error() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *pipeline) read(reader *bufio.Reader, codec JSONCodec, wire wireFormat) {
+	for {
+		payload, size, err := wire.read(reader)
+		if err != nil {
+			p.fail(err)
+			return
+		}
+		response := &Response{}
+		if err := wire.decode(codec, payload, response); err != nil {
+			// The response cannot be attributed, so the stream is unusable.
+			p.fail(err)
+			p.conn.Close()
+			return
+		}
+		p.deliver(response, size)
+	}
+}
+
+func (p *pipeline) deliver(response *Response, size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	id := response.RequestID
+	if id == "" && len(p.order) > 0 {
+		id = p.order[0]
+	}
+	if response.RequestID != "" {
+		p.echoes = true
+	}
+	ch, found := p.pending[id]
+	if !found {
+		return
+	}
+	p.remove(id)
+	if ch != nil {
+		ch <- pipelineResult{response: response, size: size}
+	}
+}
+
+// abandon gives up on the response to id after a timeout. A server that
+// echoes request IDs sends the late response with its ID, so the request is
+// forgotten right away; otherwise its place in order is kept, with no
+// channel, so the late response is not handed to the next request.
+func (p *pipeline) abandon(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, found := p.pending[id]; !found {
+		return
+	}
+	if p.echoes {
+		p.remove(id)
+	} else {
+		p.pending[id] = nil
+	}
+}
+
+func (p *pipeline) remove(id string) {
+	delete(p.pending, id)
+	for i, pending := range p.order {
+		if pending == id {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// fail delivers err to every pending request and to all later ones.
+func (p *pipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+	for id, ch := range p.pending {
+		if ch != nil {
+			ch <- pipelineResult{err: p.err}
+		}
+		delete(p.pending, id)
+	}
+	p.order = nil
+}
+
+// roundTripPipelined writes request under the client lock and waits for its
+// response without holding it, so other requests can be written meanwhile.
+func (c *RocksDBClient) roundTripPipelined(request Request) (*Response, error) {
+	c.mu.Lock()
+	var trace *CallStats
+	if hook := c.debugHook; hook != nil {
+		trace = startCallTrace(request.Action)
+		defer func() { hook(trace.finish()) }()
+	}
+	if c.pipe != nil {
+		if err := c.pipe.error(); err != nil && c.pipe.conn == c.conn {
+			c.resetConn(err)
+		}
+		if c.pipe.conn != c.conn || c.conn == nil {
+			c.pipe = nil
+		}
+	}
+	if c.conn == nil {
+		if err := c.dial(); err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+	}
+	if err := c.checkSupported(request.Action); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if c.pipe == nil {
+		c.pipe = newPipeline(c.conn, c.reader, c.codec, c.wire)
+	}
+	pipe := c.pipe
+
+	if c.token != nil {
+		request.Token = c.token
+	}
+	label := c.labelCaller(&request)
+	timeout := c.requestTimeout
+	if request.Timeout > 0 {
+		timeout = request.Timeout
+	}
+
+	id, ch, err := pipe.register()
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	request.RequestID = id
+	data, err := c.wire.encode(c.codec, request)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if trace != nil {
+		trace.RequestBytes = len(data)
+	}
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		c.closeConn()
+		c.mu.Unlock()
+		return nil, fmt.Errorf("error setting deadline: %w", err)
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		c.log().Warn("connection reset", "action", request.Action, "error", err)
+		c.resetConn(err)
+		c.mu.Unlock()
+		return nil, wrapTimeout(request.Action, fmt.Errorf("error sending request: %w", err))
+	}
+	c.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	var result pipelineResult
+	select {
+	case result = <-ch:
+	case <-expired:
+		pipe.abandon(id)
+		return nil, fmt.Errorf("%w: %s: no response within %s", ErrTimeout, request.Action, timeout)
+	}
+	if result.err != nil {
+		c.log().Warn("connection reset", "action", request.Action, "error", result.err)
+		return nil, wrapTimeout(request.Action, result.err)
+	}
+	if trace != nil {
+		trace.ResponseBytes = result.size
+	}
+	c.mu.Lock()
+	c.recordUsage(label, len(data), result.size)
+	c.mu.Unlock()
+
+	if !result.response.Success {
+		return nil, newServerError(request.Action, result.response.Result)
+	}
+	return result.response, nil
+}
@@ -0,0 +1,66 @@
+package rocksdbclient
+
+import "sync"
+
+// Pool hands out clients with dedicated connections so that helpers can run
+// requests concurrently. Requests sent through a single RocksDBClient are
+// serialized over its one connection.
+type Pool struct {
+	factory func() *RocksDBClient
+	clients chan *RocksDBClient
+
+	mu        sync.Mutex
+	all       []*RocksDBClient
+	endpoints []string
+	closed    bool
+}
+
+// NewPool creates a pool of up to size clients built lazily by factory.
+func NewPool(size int, factory func() *RocksDBClient) *Pool {
+	if size <= 0 {
+		size = 1
+	}
+	p := &Pool{factory: factory, clients: make(chan *RocksDBClient, size)}
+	for i := 0; i < size; i++ {
+		p.clients <- nil
+	}
+	return p
+}
+
+// Size returns the maximum number of clients in the pool.
+func (p *Pool) Size() int {
+	return cap(p.clients)
+}
+
+// Get blocks until a client is available.
+func (p *Pool) Get() *RocksDBClient {
+	c := <-p.clients
+	if c == nil {
+		c = p.factory()
+		p.mu.Lock()
+		if p.endpoints != nil {
+			c.SetEndpoints(rotate(p.endpoints, len(p.all)))
+		}
+		p.all = append(p.all, c)
+		p.mu.Unlock()
+	}
+	return c
+}
+
+// Put returns a client obtained from Get.
+func (p *Pool) Put(c *RocksDBClient) {
+	p.clients <- c
+}
+
+// Close closes the connections of every client created by the pool.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, c := range p.all {
+		c.Close()
+	}
+}
@@ -0,0 +1,281 @@
+package rocksdbclient
+
+// Actions of the server protocol, sent in Request.Action.
+const (
+	ActionPut                    = "put"
+	ActionGet                    = "get"
+	ActionDelete                 = "delete"
+	ActionMerge                  = "merge"
+	ActionMultiGet               = "multi_get"
+	ActionMultiGetCF             = "multi_get_cf"
+	ActionExists                 = "exists"
+	ActionStat                   = "stat"
+	ActionPutIfAbsent            = "put_if_absent"
+	ActionCompareAndSwap         = "compare_and_swap"
+	ActionIncr                   = "incr"
+	ActionTouch                  = "touch"
+	ActionDeleteRange            = "delete_range"
+	ActionBatchWrite             = "batch_write"
+	ActionCountKeys              = "count_keys"
+	ActionGetApproximateSizes    = "get_approximate_sizes"
+	ActionGetProperty            = "get_property"
+	ActionKeys                   = "keys"
+	ActionAll                    = "all"
+	ActionKeysCursor             = "keys_cursor"
+	ActionQuery                  = "query"
+	ActionListColumnFamilies     = "list_column_families"
+	ActionCreateColumnFamily     = "create_column_family"
+	ActionDropColumnFamily       = "drop_column_family"
+	ActionCompactRange           = "compact_range"
+	ActionWriteBatchPut          = "write_batch_put"
+	ActionWriteBatchMerge        = "write_batch_merge"
+	ActionWriteBatchDelete       = "write_batch_delete"
+	ActionWriteBatchWrite        = "write_batch_write"
+	ActionWriteBatchClear        = "write_batch_clear"
+	ActionWriteBatchDestroy      = "write_batch_destroy"
+	ActionCreateIterator         = "create_iterator"
+	ActionDestroyIterator        = "destroy_iterator"
+	ActionIteratorSeek           = "iterator_seek"
+	ActionIteratorSeekForPrev    = "iterator_seek_for_prev"
+	ActionIteratorSeekToFirst    = "iterator_seek_to_first"
+	ActionIteratorSeekToLast     = "iterator_seek_to_last"
+	ActionIteratorNext           = "iterator_next"
+	ActionIteratorPrev           = "iterator_prev"
+	ActionBeginTransaction       = "begin_transaction"
+	ActionCommitTransaction      = "commit_transaction"
+	ActionRollbackTransaction    = "rollback_transaction"
+	ActionTransactionKeepAlive   = "transaction_keepalive"
+	ActionBackup                 = "backup"
+	ActionRestore                = "restore"
+	ActionRestoreLatest          = "restore_latest"
+	ActionGetBackupInfo          = "get_backup_info"
+	ActionPurgeOldBackups        = "purge_old_backups"
+	ActionDeleteBackup           = "delete_backup"
+	ActionVerifyBackup           = "verify_backup"
+	ActionCreateCheckpoint       = "create_checkpoint"
+	ActionIngestExternalFile     = "ingest_external_file"
+	ActionUploadChunk            = "upload_chunk"
+	ActionFlush                  = "flush"
+	ActionFlushWAL               = "flush_wal"
+	ActionSetOptions             = "set_options"
+	ActionSetDBOptions           = "set_db_options"
+	ActionPauseBackgroundWork    = "pause_background_work"
+	ActionContinueBackgroundWork = "continue_background_work"
+	ActionJobStatus              = "job_status"
+	ActionCancelOperation        = "cancel_operation"
+	ActionListOperations         = "list_operations"
+	ActionGetAdminLog            = "get_admin_log"
+	ActionCreateToken            = "create_token"
+	ActionRevokeToken            = "revoke_token"
+	ActionSetTokenScopes         = "set_token_scopes"
+	ActionListTokens             = "list_tokens"
+	ActionAcquireLock            = "acquire_lock"
+	ActionReleaseLock            = "release_lock"
+	ActionLeaseGrant             = "lease_grant"
+	ActionLeaseKeepAlive         = "lease_keepalive"
+	ActionLeaseRevoke            = "lease_revoke"
+	ActionHello                  = "hello"
+	ActionListClients            = "list_clients"
+	ActionDisconnectClient       = "disconnect_client"
+	ActionSubscribe              = "subscribe"
+	ActionGetUpdatesSince        = "get_updates_since"
+	ActionGetLatestSequence      = "get_latest_sequence_number"
+	ActionRetainSnapshot         = "retain_snapshot"
+	ActionReleaseSnapshot        = "release_snapshot"
+	ActionListSnapshots          = "list_snapshots"
+	ActionSetSnapshotPolicy      = "set_snapshot_policy"
+)
+
+// Request options, the keys of Request.Options.
+const (
+	OptionTTL               = "ttl"
+	OptionSync              = "sync"
+	OptionDisableWAL        = "disable_wal"
+	OptionLowPri            = "low_pri"
+	OptionFillCache         = "fill_cache"
+	OptionVerifyChecksums   = "verify_checksums"
+	OptionReadTier          = "read_tier"
+	OptionForUpdate         = "for_update"
+	OptionReturnOld         = "return_old"
+	OptionIfModifiedSince   = "if_modified_since"
+	OptionDiff              = "diff"
+	OptionDiffBase          = "diff_base"
+	OptionEncoding          = "encoding"
+	OptionCaller            = "caller"
+	OptionExpected          = "expected"
+	OptionDelta             = "delta"
+	OptionStart             = "start"
+	OptionEnd               = "end"
+	OptionLimit             = "limit"
+	OptionQuery             = "query"
+	OptionPrefix            = "prefix"
+	OptionCursor            = "cursor"
+	OptionFilter            = "filter"
+	OptionOrder             = "order"
+	OptionLowerBound        = "lower_bound"
+	OptionUpperBound        = "upper_bound"
+	OptionPrefixSameAsStart = "prefix_same_as_start"
+	OptionIteratorID        = "iterator_id"
+	OptionFormat            = "format"
+	OptionAsync             = "async"
+	OptionJobID             = "job_id"
+	OptionBackupID          = "backup_id"
+	OptionBackupDir         = "backup_dir"
+	OptionFlushBeforeBackup = "flush_before_backup"
+	OptionIncremental       = "incremental"
+	OptionNumBackupsToKeep  = "num_backups_to_keep"
+	OptionDBDir             = "db_dir"
+	OptionWALDir            = "wal_dir"
+	OptionPath              = "path"
+	OptionSince             = "since"
+	OptionSinceSeq          = "since_seq"
+	OptionFollow            = "follow"
+	OptionMinSeq            = "min_seq"
+	OptionLabel             = "label"
+	OptionSnapshot          = "snapshot"
+	OptionMaxSnapshots      = "max_snapshots"
+	OptionMaxAge            = "max_age"
+	OptionAction            = "action"
+	OptionLeaseID           = "lease_id"
+	OptionFenceResource     = "fence_resource"
+	OptionFenceToken        = "fence_token"
+	OptionClientID          = "client_id"
+	OptionMoveFiles         = "move_files"
+	OptionName              = "name"
+	OptionOffset            = "offset"
+	OptionLast              = "last"
+	OptionWireEncoding      = "wire_encoding"
+	OptionFraming           = "framing"
+	OptionProtocolVersion   = "protocol_version"
+	OptionCFNames           = "cf_names"
+)
+
+// ActionInfo describes one protocol action as used by this client.
+type ActionInfo struct {
+	Name string
+	// Options lists the request options the client may send with it.
+	Options []string
+	// Mutates reports whether the action changes data, as opposed to reads
+	// and administrative queries.
+	Mutates bool
+}
+
+// writeOptions are accepted by every single-key write.
+var writeOptions = []string{OptionSync, OptionDisableWAL, OptionLowPri, OptionReturnOld, OptionLeaseID, OptionFenceResource, OptionFenceToken}
+
+// readOptions are accepted by point reads and iterators.
+var readOptions = []string{OptionFillCache, OptionVerifyChecksums, OptionReadTier}
+
+var iteratorOptions = []string{OptionIteratorID, OptionFormat}
+
+var supportedActions = []ActionInfo{
+	{ActionPut, append([]string{OptionTTL}, writeOptions...), true},
+	{ActionGet, append([]string{OptionIfModifiedSince, OptionDiff, OptionDiffBase, OptionForUpdate, OptionEncoding, OptionSnapshot}, readOptions...), false},
+	{ActionDelete, writeOptions, true},
+	{ActionMerge, writeOptions, true},
+	{ActionMultiGet, readOptions, false},
+	{ActionMultiGetCF, append([]string{OptionCFNames}, readOptions...), false},
+	{ActionExists, nil, false},
+	{ActionStat, nil, false},
+	{ActionPutIfAbsent, nil, true},
+	{ActionCompareAndSwap, []string{OptionExpected}, true},
+	{ActionIncr, []string{OptionDelta}, true},
+	{ActionTouch, []string{OptionTTL}, true},
+	{ActionDeleteRange, []string{OptionStart, OptionEnd}, true},
+	{ActionBatchWrite, []string{OptionSync, OptionDisableWAL, OptionLowPri}, true},
+	{ActionCountKeys, []string{OptionStart, OptionEnd}, false},
+	{ActionGetApproximateSizes, nil, false},
+	{ActionGetProperty, nil, false},
+	{ActionKeys, []string{OptionStart, OptionLimit, OptionQuery}, false},
+	{ActionAll, []string{OptionQuery}, false},
+	{ActionKeysCursor, []string{OptionPrefix, OptionLimit, OptionCursor}, false},
+	{ActionQuery, []string{OptionPrefix, OptionFilter, OptionOrder, OptionLimit, OptionCursor}, false},
+	{ActionListColumnFamilies, nil, false},
+	{ActionCreateColumnFamily, nil, true},
+	{ActionDropColumnFamily, nil, true},
+	{ActionCompactRange, []string{OptionStart, OptionEnd, OptionAsync}, false},
+	{ActionWriteBatchPut, nil, true},
+	{ActionWriteBatchMerge, nil, true},
+	{ActionWriteBatchDelete, nil, true},
+	{ActionWriteBatchWrite, nil, true},
+	{ActionWriteBatchClear, nil, false},
+	{ActionWriteBatchDestroy, nil, false},
+	{ActionCreateIterator, append([]string{OptionLowerBound, OptionUpperBound, OptionPrefixSameAsStart}, readOptions...), false},
+	{ActionDestroyIterator, []string{OptionIteratorID}, false},
+	{ActionIteratorSeek, iteratorOptions, false},
+	{ActionIteratorSeekForPrev, iteratorOptions, false},
+	{ActionIteratorSeekToFirst, iteratorOptions, false},
+	{ActionIteratorSeekToLast, iteratorOptions, false},
+	{ActionIteratorNext, iteratorOptions, false},
+	{ActionIteratorPrev, iteratorOptions, false},
+	{ActionBeginTransaction, nil, false},
+	{ActionCommitTransaction, nil, true},
+	{ActionRollbackTransaction, nil, false},
+	{ActionTransactionKeepAlive, nil, false},
+	{ActionBackup, []string{OptionBackupDir, OptionFlushBeforeBackup, OptionIncremental, OptionAsync}, false},
+	{ActionRestore, []string{OptionBackupID, OptionDBDir, OptionWALDir, OptionAsync}, true},
+	{ActionRestoreLatest, []string{OptionDBDir, OptionWALDir}, true},
+	{ActionGetBackupInfo, nil, false},
+	{ActionPurgeOldBackups, []string{OptionNumBackupsToKeep}, false},
+	{ActionDeleteBackup, []string{OptionBackupID}, false},
+	{ActionVerifyBackup, []string{OptionBackupID}, false},
+	{ActionCreateCheckpoint, []string{OptionPath}, false},
+	{ActionIngestExternalFile, []string{OptionMoveFiles}, true},
+	{ActionUploadChunk, []string{OptionName, OptionOffset, OptionLast}, false},
+	{ActionFlush, nil, false},
+	{ActionFlushWAL, []string{OptionSync}, false},
+	{ActionSetOptions, nil, false},
+	{ActionSetDBOptions, nil, false},
+	{ActionPauseBackgroundWork, nil, false},
+	{ActionContinueBackgroundWork, nil, false},
+	{ActionJobStatus, []string{OptionJobID}, false},
+	{ActionCancelOperation, []string{OptionJobID}, false},
+	{ActionListOperations, nil, false},
+	{ActionGetAdminLog, []string{OptionSince, OptionAction, OptionLimit}, false},
+	{ActionCreateToken, nil, false},
+	{ActionRevokeToken, nil, false},
+	{ActionSetTokenScopes, nil, false},
+	{ActionListTokens, nil, false},
+	{ActionAcquireLock, []string{OptionTTL}, false},
+	{ActionReleaseLock, nil, false},
+	{ActionLeaseGrant, []string{OptionTTL}, false},
+	{ActionLeaseKeepAlive, []string{OptionLeaseID}, false},
+	{ActionLeaseRevoke, []string{OptionLeaseID}, true},
+	{ActionHello, []string{OptionWireEncoding, OptionFraming, OptionProtocolVersion}, false},
+	{ActionListClients, nil, false},
+	{ActionDisconnectClient, []string{OptionClientID}, false},
+	{ActionSubscribe, []string{OptionPrefix, OptionSinceSeq}, false},
+	{ActionGetUpdatesSince, []string{OptionSinceSeq, OptionFollow}, false},
+	{ActionGetLatestSequence, nil, false},
+	{ActionRetainSnapshot, []string{OptionLabel, OptionTTL}, false},
+	{ActionReleaseSnapshot, []string{OptionLabel}, false},
+	{ActionListSnapshots, nil, false},
+	{ActionSetSnapshotPolicy, []string{OptionMaxSnapshots, OptionMaxAge}, false},
+}
+
+// SupportedActions describes every action this client can send, e.g. for
+// middleware that treats writes differently or for generating bindings.
+// OptionCaller may be sent with any action. The returned slice is a copy.
+func SupportedActions() []ActionInfo {
+	actions := make([]ActionInfo, len(supportedActions))
+	for i, a := range supportedActions {
+		a.Options = append([]string(nil), a.Options...)
+		actions[i] = a
+	}
+	return actions
+}
+
+var mutatingActions = func() map[string]bool {
+	m := map[string]bool{}
+	for _, a := range supportedActions {
+		if a.Mutates {
+			m[a.Name] = true
+		}
+	}
+	return m
+}()
+
+// isMutation reports whether action changes data.
+func isMutation(action string) bool {
+	return mutatingActions[action]
+}
@@ -0,0 +1,190 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterOp is a comparison used by Query.Where.
+type FilterOp string
+
+const (
+	FilterEq       FilterOp = "eq"
+	FilterNe       FilterOp = "ne"
+	FilterLt       FilterOp = "lt"
+	FilterLte      FilterOp = "lte"
+	FilterGt       FilterOp = "gt"
+	FilterGte      FilterOp = "gte"
+	FilterContains FilterOp = "contains"
+	FilterExists   FilterOp = "exists"
+)
+
+func (op FilterOp) valid() bool {
+	switch op {
+	case FilterEq, FilterNe, FilterLt, FilterLte, FilterGt, FilterGte, FilterContains, FilterExists:
+		return true
+	}
+	return false
+}
+
+// queryFilter is the wire format of one Where condition.
+type queryFilter struct {
+	Path  string          `json:"path"`
+	Op    FilterOp        `json:"op"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Query describes a read over JSON values: the keys with a prefix whose
+// values match every Where condition, in key order, up to a limit. It is
+// built with chained calls and validated before anything is sent; the first
+// invalid call is reported by RocksDBClient.Query.
+//
+//	q := rocksdbclient.NewQuery().
+//		Prefix("user:").
+//		Where("$.age", rocksdbclient.FilterGte, 18).
+//		OrderDesc().
+//		Limit(50)
+//	page, err := client.Query(q)
+type Query struct {
+	prefix  string
+	filters []queryFilter
+	desc    bool
+	limit   int
+	cursor  string
+	cf      string
+	err     error
+}
+
+// NewQuery returns an empty query matching every key.
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Prefix restricts the query to keys starting with prefix.
+func (q *Query) Prefix(prefix string) *Query {
+	q.prefix = prefix
+	return q
+}
+
+// Where adds a condition on the JSON value at path, a JSONPath expression
+// such as "$.address.city". value is ignored for FilterExists.
+func (q *Query) Where(path string, op FilterOp, value any) *Query {
+	if q.err != nil {
+		return q
+	}
+	if !strings.HasPrefix(path, "$") {
+		q.err = fmt.Errorf("invalid query path %q: must start with $", path)
+		return q
+	}
+	if !op.valid() {
+		q.err = fmt.Errorf("invalid query operator %q", op)
+		return q
+	}
+	filter := queryFilter{Path: path, Op: op}
+	if op != FilterExists {
+		data, err := json.Marshal(value)
+		if err != nil {
+			q.err = fmt.Errorf("invalid query value for %s: %w", path, err)
+			return q
+		}
+		filter.Value = data
+	}
+	q.filters = append(q.filters, filter)
+	return q
+}
+
+// OrderDesc returns matches in descending key order.
+func (q *Query) OrderDesc() *Query {
+	q.desc = true
+	return q
+}
+
+// Limit caps the number of matches per call; 0 means the server default.
+func (q *Query) Limit(limit int) *Query {
+	if limit < 0 && q.err == nil {
+		q.err = errors.New("query limit must not be negative")
+	}
+	q.limit = limit
+	return q
+}
+
+// Cursor continues a previous query from the Next token of its last page.
+func (q *Query) Cursor(cursor string) *Query {
+	q.cursor = cursor
+	return q
+}
+
+// CF runs the query on a column family other than the default one.
+func (q *Query) CF(cf string) *Query {
+	q.cf = cf
+	return q
+}
+
+// request compiles the query into a `query` request and returns it with the
+// hash that cursors for this query carry.
+func (q *Query) request() (Request, uint64, error) {
+	if q.err != nil {
+		return Request{}, 0, q.err
+	}
+	request := Request{Action: ActionQuery, Options: map[string]string{}}
+	if q.cf != "" {
+		request.CfName = &q.cf
+	}
+	if q.prefix != "" {
+		request.Options[OptionPrefix] = q.prefix
+	}
+	if len(q.filters) > 0 {
+		data, err := json.Marshal(q.filters)
+		if err != nil {
+			return Request{}, 0, err
+		}
+		request.Options[OptionFilter] = string(data)
+	}
+	if q.desc {
+		request.Options[OptionOrder] = "desc"
+	}
+	hash := FilterHash("query", q.prefix, request.Options[OptionFilter], request.Options[OptionOrder], q.cf)
+
+	if q.limit > 0 {
+		request.Options[OptionLimit] = strconv.Itoa(q.limit)
+	}
+	cursor, err := decodeCursorFor(q.cursor, hash)
+	if err != nil {
+		return Request{}, 0, err
+	}
+	if cursor != "" {
+		request.Options[OptionCursor] = cursor
+	}
+	return request, hash, nil
+}
+
+// queryResult is the result of the `query` action.
+type queryResult struct {
+	Entries []KeyValue `json:"entries"`
+	Cursor  string     `json:"cursor"`
+}
+
+// Query runs q with the `query` action, which filters values on the server,
+// and returns one page of matches. Continue with q.Cursor(page.Next).
+func (c *RocksDBClient) Query(q *Query) (*Page[KeyValue], error) {
+	request, hash, err := q.request()
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	var result queryResult
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+		return nil, fmt.Errorf("error decoding query result: %w", err)
+	}
+	page := &Page[KeyValue]{Items: result.Entries}
+	if result.Cursor != "" {
+		page.Next = EncodeCursor(result.Cursor, hash)
+	}
+	return page, nil
+}
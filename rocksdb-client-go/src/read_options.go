@@ -0,0 +1,48 @@
+package rocksdbclient
+
+// ReadTier limits which storage tiers a read may touch.
+type ReadTier string
+
+const (
+	// ReadTierAll reads from memtables, the block cache and SST files.
+	ReadTierAll ReadTier = "read_all"
+	// ReadTierBlockCache only reads data already in memtables or the block
+	// cache; other keys fail with an Incomplete status instead of doing IO.
+	ReadTierBlockCache ReadTier = "block_cache"
+	// ReadTierPersisted only reads data that has been persisted, skipping
+	// memtable entries whose WAL was disabled.
+	ReadTierPersisted ReadTier = "persisted"
+	// ReadTierMemtable only reads from memtables.
+	ReadTierMemtable ReadTier = "memtable"
+)
+
+// ReadOptions mirror the RocksDB ReadOptions of a single read. The zero
+// value uses the server defaults.
+type ReadOptions struct {
+	// NoFillCache keeps the blocks read out of the block cache, so large
+	// analytical scans do not evict the working set (fill_cache=false).
+	NoFillCache bool
+	// VerifyChecksums verifies the checksum of every block read.
+	VerifyChecksums bool
+	// Tier restricts the read to some storage tiers; empty reads all.
+	Tier ReadTier
+}
+
+func (ro ReadOptions) apply(options map[string]string) {
+	if ro.NoFillCache {
+		options[OptionFillCache] = "false"
+	}
+	if ro.VerifyChecksums {
+		options[OptionVerifyChecksums] = "true"
+	}
+	if ro.Tier != "" {
+		options[OptionReadTier] = string(ro.Tier)
+	}
+}
+
+// WithReadOptions sends ro with a read made by GetWith or MultiGetWith.
+func WithReadOptions(ro ReadOptions) CallOption {
+	return func(request *Request) {
+		ro.apply(request.Options)
+	}
+}
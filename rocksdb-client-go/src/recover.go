@@ -0,0 +1,44 @@
+package rocksdbclient
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by scan helpers and migrations when a user callback
+// panics. It records which entry was being processed so a long-running job
+// fails with an actionable error instead of crashing the process.
+type PanicError struct {
+	// Key is the key passed to the callback.
+	Key string
+	// Offset is the position of the entry within the scan, starting at 0,
+	// or -1 if the callback does not process entries, like Migration.Up.
+	Offset int64
+	// Value is the value the callback panicked with.
+	Value any
+	// Stack is the stack trace captured when the panic was recovered.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("panic: %v", e.Value)
+	}
+	return fmt.Sprintf("panic processing key %q at offset %d: %v", e.Key, e.Offset, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// callScanFunc calls fn and converts a panic into a *PanicError.
+func callScanFunc(fn func(key, value string) error, key, value string, offset int64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Key: key, Offset: offset, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(key, value)
+}
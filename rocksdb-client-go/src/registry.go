@@ -0,0 +1,120 @@
+package rocksdbclient
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RegistryOptions configures NewRegistry.
+type RegistryOptions struct {
+	// Prefix is prepended to registry keys. Defaults to "services/".
+	Prefix string
+	// TTL is how long registrations survive without keep-alives, e.g. after
+	// the process died. Defaults to 10 seconds.
+	TTL time.Duration
+}
+
+// Registry is a small service registry built on ephemeral keys. Instances
+// register as "<prefix><service>/<addr>" attached to one lease that is kept
+// alive in the background, so registrations disappear when the process
+// stops renewing it.
+type Registry struct {
+	c    *RocksDBClient
+	opts RegistryOptions
+
+	mu    sync.Mutex
+	lease *Lease
+}
+
+// NewRegistry creates a registry using c. No request is sent until the first
+// Register.
+func NewRegistry(c *RocksDBClient, opts RegistryOptions) *Registry {
+	if opts.Prefix == "" {
+		opts.Prefix = "services/"
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Second
+	}
+	return &Registry{c: c, opts: opts}
+}
+
+func (r *Registry) namespace(service string) *Namespace {
+	return r.c.Namespace(r.opts.Prefix + service + "/")
+}
+
+// Register announces addr as an instance of service. The lease backing the
+// registrations is granted on first use.
+func (r *Registry) Register(service, addr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lease == nil {
+		lease, err := r.c.GrantLease(r.opts.TTL)
+		if err != nil {
+			return err
+		}
+		lease.Start()
+		r.lease = lease
+	}
+	return r.c.PutEphemeral(r.namespace(service).Key(addr), addr, r.lease.ID, nil)
+}
+
+// Deregister removes addr from service.
+func (r *Registry) Deregister(service, addr string) error {
+	return r.namespace(service).Delete(addr, nil)
+}
+
+// Discover returns the registered addresses of service in sorted order.
+func (r *Registry) Discover(service string) ([]string, error) {
+	addrs, err := r.namespace(service).Keys("")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(addrs)
+	return addrs, nil
+}
+
+// Watch polls service every interval and sends the address list whenever it
+// changes, starting with the current one. Lookup errors are skipped, so a
+// temporarily unreachable server does not end the watch. The channel is
+// closed when ctx is done.
+func (r *Registry) Watch(ctx context.Context, service string, interval time.Duration) <-chan []string {
+	ch := make(chan []string, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last []string
+		first := true
+		for {
+			if addrs, err := r.Discover(service); err == nil && (first || !slices.Equal(addrs, last)) {
+				select {
+				case ch <- addrs:
+					last, first = addrs, false
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Close revokes the registry lease, which removes all registrations made
+// through it.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lease == nil {
+		return nil
+	}
+	err := r.lease.Revoke()
+	r.lease = nil
+	return err
+}
@@ -0,0 +1,218 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaStrategy selects the replica that serves a read.
+type ReplicaStrategy int
+
+const (
+	// RoundRobin spreads reads evenly across the replicas.
+	RoundRobin ReplicaStrategy = iota
+	// LowestLatency sends reads to the replica with the lowest moving
+	// average latency.
+	LowestLatency
+)
+
+// ReplicaOptions configures a ReplicaRouter.
+type ReplicaOptions struct {
+	Strategy ReplicaStrategy
+	// ReadYourWrites makes reads observe the client's own writes: after
+	// every write the router fetches the primary's latest sequence number,
+	// one extra round trip, and sends it with reads as the min_seq option.
+	// A replica that has not applied it yet rejects the read, which is then
+	// served by the primary.
+	ReadYourWrites bool
+	// RetryAfter is how long a replica that could not be reached is skipped.
+	// Defaults to 5s.
+	RetryAfter time.Duration
+	// ClientOptions are applied to the replica clients after the settings
+	// copied from the primary (token, timeouts, TLS and codec).
+	ClientOptions []Option
+}
+
+// ReplicaRouter sends the plain reads of a client to read replicas (get,
+// multi_get, exists, keys, query and similar) and leaves everything else,
+// including transactional and locking reads and iterators, on the primary.
+// A read whose replica cannot be reached is retried on the primary, and the
+// replica is skipped for ReplicaOptions.RetryAfter.
+type ReplicaRouter struct {
+	primary  *RocksDBClient
+	replicas []*replica
+	opts     ReplicaOptions
+	next     atomic.Uint64
+	// minSeq is the primary's sequence number after the last write.
+	minSeq atomic.Uint64
+}
+
+type replica struct {
+	c *RocksDBClient
+
+	mu sync.Mutex
+	// latency is an exponentially weighted moving average.
+	latency   time.Duration
+	downUntil time.Time
+}
+
+// routedReads are the stateless reads a replica can serve.
+var routedReads = map[string]bool{
+	ActionGet:         true,
+	ActionMultiGet:    true,
+	ActionMultiGetCF:  true,
+	ActionExists:      true,
+	ActionStat:        true,
+	ActionKeys:        true,
+	ActionAll:         true,
+	ActionKeysCursor:  true,
+	ActionQuery:       true,
+	ActionCountKeys:   true,
+	ActionGetProperty: true,
+}
+
+// NewReplicaRouter creates clients for the replicas at addrs (host:port) and
+// installs the router as an interceptor of primary, so every helper of
+// primary routes its reads.
+func NewReplicaRouter(primary *RocksDBClient, addrs []string, opts ReplicaOptions) *ReplicaRouter {
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = 5 * time.Second
+	}
+	r := &ReplicaRouter{primary: primary, opts: opts}
+	for _, addr := range addrs {
+		r.replicas = append(r.replicas, &replica{c: primary.cloneFor(addr, opts.ClientOptions)})
+	}
+	primary.Use(r.intercept)
+	return r
+}
+
+// cloneFor creates a client for addr with the connection settings of c.
+func (c *RocksDBClient) cloneFor(addr string, opts []Option) *RocksDBClient {
+	c.mu.Lock()
+	clone := NewClient(addr)
+	clone.token = c.token
+	clone.timeout, clone.retryInterval, clone.requestTimeout = c.timeout, c.retryInterval, c.requestTimeout
+	clone.tlsConfig, clone.codec, clone.logger = c.tlsConfig, c.codec, c.logger
+	c.mu.Unlock()
+	for _, opt := range opts {
+		opt(clone)
+	}
+	return clone
+}
+
+func (r *ReplicaRouter) intercept(request Request, next Handler) (*Response, error) {
+	if !r.routed(request) {
+		response, err := next(request)
+		if err == nil && r.opts.ReadYourWrites && isMutation(request.Action) {
+			if seq, err := r.primary.LatestSequenceNumber(); err == nil {
+				r.minSeq.Store(seq)
+			}
+		}
+		return response, err
+	}
+
+	if seq := r.minSeq.Load(); seq > 0 {
+		options := make(map[string]string, len(request.Options)+1)
+		for k, v := range request.Options {
+			options[k] = v
+		}
+		options[OptionMinSeq] = strconv.FormatUint(seq, 10)
+		request.Options = options
+	}
+	rep := r.pick()
+	if rep == nil {
+		return next(request)
+	}
+	start := time.Now()
+	response, err := rep.c.SendRequest(request)
+	var serverErr *ServerError
+	if err == nil || errors.As(err, &serverErr) {
+		rep.observe(time.Since(start))
+		if !errors.Is(err, ErrReplicaBehind) {
+			return response, err
+		}
+	} else {
+		rep.markDown(r.opts.RetryAfter)
+	}
+	// The replica is unreachable or behind; the primary has every write.
+	delete(request.Options, OptionMinSeq)
+	return next(request)
+}
+
+func (r *ReplicaRouter) routed(request Request) bool {
+	if len(r.replicas) == 0 || !routedReads[request.Action] || request.Txn != nil || request.TxnID != nil {
+		return false
+	}
+	// Retained snapshots live on the primary only.
+	return request.Options[OptionForUpdate] == "" && request.Options[OptionSnapshot] == ""
+}
+
+// pick returns the replica to serve a read, or nil if all are down.
+func (r *ReplicaRouter) pick() *replica {
+	now := time.Now()
+	if r.opts.Strategy == LowestLatency {
+		var best *replica
+		for _, rep := range r.replicas {
+			if rep.up(now) && (best == nil || rep.averageLatency() < best.averageLatency()) {
+				best = rep
+			}
+		}
+		return best
+	}
+	start := r.next.Add(1) - 1
+	for i := range uint64(len(r.replicas)) {
+		if rep := r.replicas[(start+i)%uint64(len(r.replicas))]; rep.up(now) {
+			return rep
+		}
+	}
+	return nil
+}
+
+func (rep *replica) up(now time.Time) bool {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	return !now.Before(rep.downUntil)
+}
+
+func (rep *replica) markDown(d time.Duration) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	rep.downUntil = time.Now().Add(d)
+}
+
+func (rep *replica) observe(latency time.Duration) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if rep.latency == 0 {
+		rep.latency = latency
+		return
+	}
+	rep.latency += (latency - rep.latency) / 5
+}
+
+func (rep *replica) averageLatency() time.Duration {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	return rep.latency
+}
+
+// Latencies returns the moving average read latency of each replica, by
+// address. Replicas that served no read yet report zero.
+func (r *ReplicaRouter) Latencies() map[string]time.Duration {
+	latencies := make(map[string]time.Duration, len(r.replicas))
+	for _, rep := range r.replicas {
+		latencies[rep.c.Endpoints()[0]] = rep.averageLatency()
+	}
+	return latencies
+}
+
+// Close closes the replica connections. The interceptor stays installed on
+// the primary, so the router must not be closed while the primary is used.
+func (r *ReplicaRouter) Close() {
+	for _, rep := range r.replicas {
+		rep.c.Close()
+	}
+}
@@ -0,0 +1,52 @@
+package rocksdbclient
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy describes how often and how fast an operation is retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every retry. Values below 1 keep the
+	// delay constant.
+	Multiplier float64
+}
+
+// DefaultRetryPolicy retries up to 5 times with exponential backoff from
+// 10ms to 1s.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     5,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+// Backoff returns the delay before retry number attempt (starting at 0).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 0; i < attempt && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(delay)
+}
+
+// wait sleeps for the backoff of attempt or until ctx is done.
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,9 +2,12 @@ package rocksdbclient
 
 import (
 	"bufio"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -17,78 +20,252 @@ type Request struct {
 	Options      map[string]string `json:"options,omitempty"`
 	Token        *string           `json:"token,omitempty"`
 	Txn          *bool             `json:"txn,omitempty"`
+	TxnID        *string           `json:"txn_id,omitempty"`
+	Keys         []string          `json:"keys,omitempty"`
+	Operations   []Operation       `json:"operations,omitempty"`
+	// RequestID correlates a pipelined request with its response.
+	RequestID string `json:"request_id,omitempty"`
+	// Timeout overrides the client request timeout for this request only.
+	Timeout time.Duration `json:"-"`
 }
 
 type Response struct {
-	Success bool   `json:"success"`
-	Result  string `json:"result"`
+	Success   bool   `json:"success"`
+	Result    string `json:"result"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type RocksDBClient struct {
-	host          string
-	port          int
+	// endpoints lists server addresses as host:port; endpoint indexes the
+	// one the client connects to.
+	endpoints     []string
+	endpoint      int
 	token         *string
 	timeout       time.Duration
 	retryInterval time.Duration
-	conn          net.Conn
+	// requestTimeout bounds each round trip; zero means no deadline.
+	requestTimeout time.Duration
+	conn           net.Conn
+	reader         *bufio.Reader
+	codec          JSONCodec
+	mu             sync.Mutex
+	interceptors   []Interceptor
+	debugHook      func(CallStats)
+	txnRetry       RetryPolicy
+	// txnHeartbeat, when set, keeps transactions run by WithTransaction
+	// alive in the background.
+	txnHeartbeat *HeartbeatOptions
+	// caller is the default caller label; usage accumulates traffic per
+	// label.
+	caller string
+	usage  map[string]*CallerUsage
+	// tlsConfig enables TLS when set; defaultCF is used for requests that
+	// do not name a column family.
+	tlsConfig *tls.Config
+	defaultCF *string
+	logger    *slog.Logger
+	// cfRoutes maps key prefixes to column families, longest prefix first.
+	cfRoutes []CFRoute
+	// clientInfo is sent in the hello handshake on every new connection;
+	// clientID is the ID the server assigned to the current one.
+	clientInfo *ClientInfo
+	clientID   string
+	// encoding and framing are requested in the handshake; wire is the
+	// format negotiated for the current connection.
+	encoding WireEncoding
+	framing  bool
+	wire     wireFormat
+	// failover, when set, moves the client to the next endpoint when a
+	// connection fails; probeStop stops the probe that fails back.
+	failover  *FailoverOptions
+	probeStop chan struct{}
+	// pipelining sends requests without waiting for earlier responses; pipe
+	// matches the responses on the current connection.
+	pipelining bool
+	pipe       *pipeline
+	// discovery checks requests against the actions the server advertised
+	// in the handshake; serverInfo is what it advertised on the current
+	// connection.
+	discovery  bool
+	serverInfo *ServerInfo
+}
+
+// NewRocksDBClient creates a client for host:port. New code should prefer
+// NewClient, which takes functional options.
+func NewRocksDBClient(host string, port int, token *string, timeout, retryInterval time.Duration) *RocksDBClient {
+	c := NewClient(net.JoinHostPort(host, strconv.Itoa(port)), WithTimeout(timeout), WithRetryInterval(retryInterval))
+	c.token = token
+	return c
 }
 
-func NewRocksDBClient(host string, port int, token *string, timeout, retryInterval time.Duration) *RocksDBClient {
-	return &RocksDBClient{
-		host:          host,
-		port:          port,
-		token:         token,
-		timeout:       timeout,
-		retryInterval: retryInterval,
+func (c *RocksDBClient) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dial()
+}
+
+func (c *RocksDBClient) dial() error {
+	conn, err := c.dialEndpoint()
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+	if err := c.handshake(); err != nil {
+		c.closeConn()
+		return err
 	}
+	return nil
 }
 
-func (c *RocksDBClient) Connect() error {
+func (c *RocksDBClient) dialAddr(addr string) (net.Conn, error) {
 	start := time.Now()
 	for {
-		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", c.host, c.port), c.timeout)
+		conn, err := c.dialOnce(addr)
 		if err == nil {
-			c.conn = conn
-			return nil
+			return conn, nil
 		}
 		if time.Since(start) >= c.timeout {
-			return fmt.Errorf("unable to connect to server: %w", err)
+			return nil, fmt.Errorf("unable to connect to server: %w", err)
 		}
+		c.log().Debug("connect failed, retrying", "addr", addr, "error", err)
 		time.Sleep(c.retryInterval)
 	}
 }
 
+func (c *RocksDBClient) dialOnce(addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	if c.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", addr, c.tlsConfig)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
 func (c *RocksDBClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopProbe()
+	c.closeConn()
+}
+
+func (c *RocksDBClient) closeConn() {
 	if c.conn != nil {
 		c.conn.Close()
 		c.conn = nil
+		c.reader = nil
 	}
 }
 
+// SetRequestTimeout sets the read/write deadline applied around every
+// request. A request that exceeds it fails with ErrTimeout and the
+// connection is reset. Zero disables the deadline.
+func (c *RocksDBClient) SetRequestTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requestTimeout = timeout
+}
+
+// SetJSONCodec replaces encoding/json for request and response bodies, e.g.
+// with jsoniter.ConfigCompatibleWithStandardLibrary or sonic.ConfigStd.
+func (c *RocksDBClient) SetJSONCodec(codec JSONCodec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codec = codec
+}
+
+// Use appends interceptors to the request chain. The first interceptor
+// registered is the outermost one.
+func (c *RocksDBClient) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
 func (c *RocksDBClient) SendRequest(request Request) (*Response, error) {
+	if err := c.routeCF(&request); err != nil {
+		return nil, err
+	}
+	handler := Handler(c.roundTrip)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.interceptors[i], handler
+		handler = func(request Request) (*Response, error) {
+			return interceptor(request, next)
+		}
+	}
+	return handler(request)
+}
+
+func (c *RocksDBClient) roundTrip(request Request) (*Response, error) {
+	if c.pipelining {
+		return c.roundTripPipelined(request)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var trace *CallStats
+	if c.debugHook != nil {
+		trace = startCallTrace(request.Action)
+		defer func() { c.debugHook(trace.finish()) }()
+	}
+
 	if c.conn == nil {
-		if err := c.Connect(); err != nil {
+		if err := c.dial(); err != nil {
 			return nil, err
 		}
 	}
+	if err := c.checkSupported(request.Action); err != nil {
+		return nil, err
+	}
 
 	if c.token != nil {
 		request.Token = c.token
 	}
+	label := c.labelCaller(&request)
+
+	timeout := c.requestTimeout
+	if request.Timeout > 0 {
+		timeout = request.Timeout
+	}
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		c.closeConn()
+		return nil, fmt.Errorf("error setting deadline: %w", err)
+	}
 
-	encoder := json.NewEncoder(c.conn)
-	if err := encoder.Encode(request); err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+	data, err := c.wire.encode(c.codec, request)
+	if err != nil {
+		return nil, err
 	}
+	if trace != nil {
+		trace.RequestBytes = len(data)
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		c.log().Warn("connection reset", "action", request.Action, "error", err)
+		c.resetConn(err)
+		return nil, wrapTimeout(request.Action, fmt.Errorf("error sending request: %w", err))
+	}
+
+	payload, size, err := c.wire.read(c.reader)
+	if err != nil {
+		// The stream position is unknown after a failed read, so the
+		// connection cannot be reused.
+		c.log().Warn("connection reset", "action", request.Action, "error", err)
+		c.resetConn(err)
+		return nil, wrapTimeout(request.Action, err)
+	}
+	if trace != nil {
+		trace.ResponseBytes = size
+	}
+	c.recordUsage(label, len(data), size)
 
 	response := &Response{}
-	decoder := json.NewDecoder(bufio.NewReader(c.conn))
-	if err := decoder.Decode(response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := c.wire.decode(c.codec, payload, response); err != nil {
+		return nil, err
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("server error: %s", response.Result)
+		return nil, newServerError(request.Action, response.Result)
 	}
 
 	return response, nil
@@ -662,23 +839,6 @@ func (c *RocksDBClient) GetBackupInfo() (*Response, error) {
 	return c.SendRequest(request)
 }
 
-/**
-* Begins a new transaction.
-    * This function handles the `begin_transaction` action which begins a new transaction in the RocksDB database.
-*
-*
-* @return {Promise<any>} The result of the operation.
-* @throws {Error} If the operation fails.
-*/
-func (c *RocksDBClient) BeginTransaction() (*Response, error) {
-	request := Request{
-		Action:  "begin_transaction",
-		Options: map[string]string{},
-	}
-
-	return c.SendRequest(request)
-}
-
 /**
 * Commits an existing transaction.
     * This function handles the `commit_transaction` action which commits an existing transaction in the RocksDB database.
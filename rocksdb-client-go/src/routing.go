@@ -0,0 +1,122 @@
+package rocksdbclient
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CFRoute sends keys starting with Prefix to the column family CF.
+type CFRoute struct {
+	Prefix string
+	CF     string
+}
+
+// routedKeyActions are the single-key actions whose column family is chosen
+// by the routing table.
+var routedKeyActions = map[string]bool{
+	ActionPut:              true,
+	ActionGet:              true,
+	ActionDelete:           true,
+	ActionMerge:            true,
+	ActionExists:           true,
+	ActionStat:             true,
+	ActionPutIfAbsent:      true,
+	ActionCompareAndSwap:   true,
+	ActionIncr:             true,
+	ActionTouch:            true,
+	ActionWriteBatchPut:    true,
+	ActionWriteBatchMerge:  true,
+	ActionWriteBatchDelete: true,
+}
+
+// WithCFRoutes partitions one logical keyspace across column families:
+// requests that do not name a column family go to the column family of the
+// longest route prefix their key starts with, and to the default one (see
+// WithDefaultCF) when no route matches. An explicit column family always
+// wins.
+//
+// Single-key actions, the operations of batch_write and multi_get are
+// routed. Like without routes, batch_write operations that match no route
+// keep their own column family rather than the default one. A multi_get
+// whose keys route to different column families fails,
+// use MultiGetCF with RouteCF instead. Scans, iterators and range actions
+// are not routed and use the default column family.
+//
+//	client := rocksdbclient.NewClient(addr, rocksdbclient.WithCFRoutes(
+//		rocksdbclient.CFRoute{Prefix: "session:", CF: "sessions"},
+//		rocksdbclient.CFRoute{Prefix: "blob:", CF: "blobs"},
+//	))
+func WithCFRoutes(routes ...CFRoute) Option {
+	return func(c *RocksDBClient) {
+		c.cfRoutes = append(c.cfRoutes, routes...)
+		slices.SortStableFunc(c.cfRoutes, func(a, b CFRoute) int {
+			return cmp.Compare(len(b.Prefix), len(a.Prefix))
+		})
+	}
+}
+
+// RouteCF returns the column family the routing table sends key to, or nil
+// for the default column family.
+func (c *RocksDBClient) RouteCF(key string) *string {
+	if cf := c.matchRoute(key); cf != nil {
+		return cf
+	}
+	return c.defaultCF
+}
+
+// matchRoute returns the column family of the longest route prefix of key,
+// or nil if no route matches.
+func (c *RocksDBClient) matchRoute(key string) *string {
+	for i := range c.cfRoutes {
+		if strings.HasPrefix(key, c.cfRoutes[i].Prefix) {
+			return &c.cfRoutes[i].CF
+		}
+	}
+	return nil
+}
+
+// routeCF fills in the column family of a request that does not name one.
+func (c *RocksDBClient) routeCF(request *Request) error {
+	if request.CfName != nil {
+		return nil
+	}
+	if len(c.cfRoutes) == 0 {
+		request.CfName = c.defaultCF
+		return nil
+	}
+
+	switch {
+	case routedKeyActions[request.Action] && request.Key != nil:
+		request.CfName = c.RouteCF(*request.Key)
+	case request.Action == ActionBatchWrite:
+		ops := make([]Operation, len(request.Operations))
+		for i, op := range request.Operations {
+			if op.CfName == nil {
+				op.CfName = c.matchRoute(op.Key)
+			}
+			ops[i] = op
+		}
+		request.Operations = ops
+		request.CfName = c.defaultCF
+	case request.Action == ActionMultiGet && len(request.Keys) > 0:
+		cf := c.RouteCF(request.Keys[0])
+		for _, key := range request.Keys[1:] {
+			if other := c.RouteCF(key); cfLabel(other) != cfLabel(cf) {
+				return fmt.Errorf("multi_get keys route to column families %s and %s, use MultiGetCF", cfLabel(cf), cfLabel(other))
+			}
+		}
+		request.CfName = cf
+	default:
+		request.CfName = c.defaultCF
+	}
+	return nil
+}
+
+func cfLabel(cf *string) string {
+	if cf == nil {
+		return "default"
+	}
+	return *cf
+}
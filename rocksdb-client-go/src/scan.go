@@ -0,0 +1,111 @@
+package rocksdbclient
+
+import "strconv"
+
+// ScanOptions selects the entries returned by Scan. Zero values leave a
+// bound open.
+type ScanOptions struct {
+	// Prefix restricts the scan to keys starting with it.
+	Prefix string
+	// Start is the inclusive lower bound.
+	Start string
+	// End is the exclusive upper bound.
+	End string
+	// Reverse returns entries in descending key order.
+	Reverse bool
+	// Limit caps the number of entries; 0 means no limit.
+	Limit int
+	// CF is the column family to scan; empty means the default one.
+	CF string
+	// Read is passed to the underlying iterator.
+	Read ReadOptions
+}
+
+// KeyValue is one entry returned by Scan and Query.
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Scan returns the entries matching opts in key order (descending with
+// Reverse). It runs a server-side iterator bounded to the requested range,
+// one round trip per entry, and releases it before returning.
+func (c *RocksDBClient) Scan(opts ScanOptions) ([]KeyValue, error) {
+	r := KeyRange{Start: opts.Start, End: opts.End}
+	if opts.Prefix > r.Start {
+		r.Start = opts.Prefix
+	}
+	if end := prefixEnd(opts.Prefix); opts.Prefix != "" && end != "" && (r.End == "" || end < r.End) {
+		r.End = end
+	}
+
+	it, err := c.NewIteratorWithOptions(IteratorOptions{LowerBound: r.Start, UpperBound: r.End, CF: opts.CF, Read: opts.Read})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var entries []KeyValue
+	collect := func() bool {
+		entries = append(entries, KeyValue{Key: it.Key(), Value: it.Value()})
+		return opts.Limit <= 0 || len(entries) < opts.Limit
+	}
+
+	if !opts.Reverse {
+		valid := it.Seek(r.Start)
+		for valid && r.Contains(it.Key()) && collect() {
+			valid = it.Next()
+		}
+		return entries, it.Err()
+	}
+
+	var valid bool
+	if r.End == "" {
+		valid = it.SeekToLast()
+	} else if valid = it.SeekForPrev(r.End); valid && it.Key() == r.End {
+		valid = it.Prev()
+	}
+	for valid && r.Contains(it.Key()) && collect() {
+		valid = it.Prev()
+	}
+	return entries, it.Err()
+}
+
+// hash identifies the range selected by opts; Limit is left out so the page
+// size may change between pages.
+func (opts ScanOptions) hash() uint64 {
+	return FilterHash("scan", opts.Prefix, opts.Start, opts.End, strconv.FormatBool(opts.Reverse), opts.CF)
+}
+
+// ScanPage returns one page of up to opts.Limit entries starting after
+// cursor, a token from a previous page's Next (empty for the first page).
+// With a zero Limit the whole range is returned as a single page.
+func (c *RocksDBClient) ScanPage(opts ScanOptions, cursor string) (*Page[KeyValue], error) {
+	hash := opts.hash()
+	lastKey, err := decodeCursorFor(cursor, hash)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		if opts.Reverse {
+			opts.End = lastKey
+		} else {
+			// The smallest key sorting after lastKey.
+			opts.Start = lastKey + "\x00"
+		}
+	}
+	if opts.Limit > 0 {
+		opts.Limit++
+	}
+
+	entries, err := c.Scan(opts)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[KeyValue]{Items: entries}
+	if opts.Limit > 0 && len(entries) == opts.Limit {
+		page.Items = entries[:len(entries)-1]
+		page.Next = EncodeCursor(page.Items[len(page.Items)-1].Key, hash)
+	}
+	return page, nil
+}
@@ -0,0 +1,93 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ProtocolVersion is the protocol version this client speaks. It is sent in
+// the hello handshake.
+const ProtocolVersion = 1
+
+// ServerInfo is what a server advertises in the hello handshake.
+type ServerInfo struct {
+	// ProtocolVersion is 0 for servers without the handshake.
+	ProtocolVersion int    `json:"protocol_version"`
+	Version         string `json:"server_version"`
+	// Actions lists the actions the server supports. It is empty if the
+	// server did not advertise them.
+	Actions []string `json:"actions"`
+}
+
+// Supports reports whether the server supports action. Servers that do not
+// advertise their actions are assumed to support every action.
+func (s *ServerInfo) Supports(action string) bool {
+	return len(s.Actions) == 0 || slices.Contains(s.Actions, action)
+}
+
+// WithCapabilityCheck sends the hello handshake on every new connection and
+// fails requests for actions the server did not advertise with
+// ErrUnsupportedAction before they are sent, instead of with the server's
+// "Unknown action" message after a round trip.
+func WithCapabilityCheck() Option {
+	return func(c *RocksDBClient) {
+		c.discovery = true
+	}
+}
+
+// ServerInfo returns what the server of the current connection advertised,
+// connecting if needed. If the handshake was not sent when the connection
+// was opened, it is sent now. Callers can use ServerInfo.Supports to fall
+// back to older actions on older servers.
+func (c *RocksDBClient) ServerInfo() (*ServerInfo, error) {
+	c.mu.Lock()
+	if c.conn == nil {
+		if err := c.dial(); err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+	}
+	info := c.serverInfo
+	c.mu.Unlock()
+	if info != nil {
+		return info.clone(), nil
+	}
+
+	// The probe bypasses the interceptors, like the handshake.
+	response, err := c.roundTrip(Request{Action: ActionHello, Options: map[string]string{OptionProtocolVersion: strconv.Itoa(ProtocolVersion)}})
+	var result helloResult
+	switch {
+	case errors.Is(err, ErrUnsupportedAction):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal([]byte(response.Result), &result); err != nil {
+			return nil, fmt.Errorf("error decoding hello result: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.serverInfo == nil {
+		c.serverInfo = &result.ServerInfo
+	}
+	return c.serverInfo.clone(), nil
+}
+
+func (s *ServerInfo) clone() *ServerInfo {
+	clone := *s
+	clone.Actions = slices.Clone(s.Actions)
+	return &clone
+}
+
+// checkSupported fails actions the server did not advertise when
+// WithCapabilityCheck is set. It runs under c.mu.
+func (c *RocksDBClient) checkSupported(action string) error {
+	if !c.discovery || c.serverInfo == nil || c.serverInfo.Supports(action) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (server protocol version %d)", ErrUnsupportedAction, action, c.serverInfo.ProtocolVersion)
+}
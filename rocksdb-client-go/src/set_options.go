@@ -0,0 +1,40 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// SetOptions changes mutable column family options, such as
+// write_buffer_size or level0_slowdown_writes_trigger, on the running server
+// with the `set_options` action (RocksDB's DB::SetOptions). cfName nil
+// targets the default column family. The new values are not persisted in
+// the server configuration and revert on restart.
+func (c *RocksDBClient) SetOptions(cfName *string, options map[string]string) error {
+	return c.sendSetOptions(ActionSetOptions, cfName, options)
+}
+
+// SetDBOptions changes mutable DB-wide options, such as
+// max_background_jobs, with the `set_db_options` action (RocksDB's
+// DB::SetDBOptions). Like SetOptions, changes last until the server restarts.
+func (c *RocksDBClient) SetDBOptions(options map[string]string) error {
+	return c.sendSetOptions(ActionSetDBOptions, nil, options)
+}
+
+func (c *RocksDBClient) sendSetOptions(action string, cfName *string, options map[string]string) error {
+	if len(options) == 0 {
+		return errors.New(action + ": no options given")
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	_, err = c.SendRequest(Request{
+		Action:  action,
+		Value:   &encoded,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	return err
+}
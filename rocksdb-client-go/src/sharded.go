@@ -0,0 +1,215 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// shardVirtualNodes is the number of points each shard owns on the hash
+// ring; more points spread keys more evenly.
+const shardVirtualNodes = 160
+
+// HashFunc maps a key, or a shard point name, to a position on the ring.
+type HashFunc func(key string) uint64
+
+// FNV64a is the default HashFunc of ShardedClient.
+func FNV64a(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+type ringPoint struct {
+	hash  uint64
+	shard int
+}
+
+// ShardedClient spreads keys over several independent servers by consistent
+// hashing, so adding a server moves only about 1/N of the keys. Single-key
+// operations go to the key's shard; MultiGet and Keys fan out to every shard
+// involved in parallel and merge the results. Operations spanning shards,
+// such as batches and transactions, are not atomic across them, so use
+// Shard to run them against one server.
+type ShardedClient struct {
+	shards []*RocksDBClient
+	hash   HashFunc
+	ring   []ringPoint
+}
+
+// NewShardedClient creates a client per endpoint (host:port), applying opts
+// to each, and places them on a hash ring using hash, FNV64a when nil. The
+// ring depends on the endpoints only, not their order, so every process
+// configured with the same set routes keys alike.
+func NewShardedClient(endpoints []string, hash HashFunc, opts ...Option) (*ShardedClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+	if hash == nil {
+		hash = FNV64a
+	}
+	endpoints = slices.Clone(endpoints)
+	slices.Sort(endpoints)
+	if len(slices.Compact(slices.Clone(endpoints))) != len(endpoints) {
+		return nil, errors.New("duplicate endpoint")
+	}
+
+	s := &ShardedClient{hash: hash}
+	for i, addr := range endpoints {
+		s.shards = append(s.shards, NewClient(addr, opts...))
+		for v := 0; v < shardVirtualNodes; v++ {
+			s.ring = append(s.ring, ringPoint{hash: s.position(addr + "#" + strconv.Itoa(v)), shard: i})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool { return s.ring[i].hash < s.ring[j].hash })
+	return s, nil
+}
+
+// position places key on the ring. The hash is passed through the 64-bit
+// MurmurHash3 finalizer, since FNV and similar hashes of near-identical
+// strings, such as the shard point names, differ mostly in the low bits and
+// would otherwise cluster.
+func (s *ShardedClient) position(key string) uint64 {
+	h := s.hash(key)
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func (s *ShardedClient) shardIndex(key string) int {
+	h := s.position(key)
+	i := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= h })
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.ring[i].shard
+}
+
+// Shard returns the client of the server that owns key.
+func (s *ShardedClient) Shard(key string) *RocksDBClient {
+	return s.shards[s.shardIndex(key)]
+}
+
+// Shards returns the clients of all servers, ordered by endpoint.
+func (s *ShardedClient) Shards() []*RocksDBClient {
+	return slices.Clone(s.shards)
+}
+
+// Get returns the value of key. Missing keys return an error matching
+// ErrKeyNotFound.
+func (s *ShardedClient) Get(key string, cfName *string) (string, error) {
+	response, err := s.Shard(key).Get(&key, cfName, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
+
+// Put stores a key-value pair on the key's shard.
+func (s *ShardedClient) Put(key, value string, cfName *string) error {
+	_, err := s.Shard(key).Put(&key, &value, cfName, nil)
+	return err
+}
+
+// Delete removes key from its shard.
+func (s *ShardedClient) Delete(key string, cfName *string) error {
+	_, err := s.Shard(key).Delete(&key, cfName, nil)
+	return err
+}
+
+// Merge applies a merge operand to key on its shard.
+func (s *ShardedClient) Merge(key, value string, cfName *string) error {
+	_, err := s.Shard(key).Merge(&key, &value, cfName, nil)
+	return err
+}
+
+// MultiGet fetches keys with one multi_get per shard involved, in parallel.
+// Keys that do not exist map to nil. If any shard fails, the first error is
+// returned.
+func (s *ShardedClient) MultiGet(keys []string, cfName *string) (map[string]*string, error) {
+	byShard := map[int][]string{}
+	for _, key := range keys {
+		i := s.shardIndex(key)
+		byShard[i] = append(byShard[i], key)
+	}
+
+	values := make(map[string]*string, len(keys))
+	var mu sync.Mutex
+	err := s.fanOut(slices.Collect(maps.Keys(byShard)), func(c *RocksDBClient, i int) error {
+		shardValues, err := c.MultiGet(byShard[i], cfName)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for k, v := range shardValues {
+			values[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Keys returns the keys of all shards whose key or value contains query, as
+// ListAllKeys does for one server, merged in sorted order.
+func (s *ShardedClient) Keys(query string) ([]string, error) {
+	all := make([]int, len(s.shards))
+	for i := range all {
+		all[i] = i
+	}
+	var keys []string
+	var mu sync.Mutex
+	err := s.fanOut(all, func(c *RocksDBClient, _ int) error {
+		result, err := c.ListAllKeys(query)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, result.Keys...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// fanOut runs fn for the given shards in parallel and returns the first
+// error.
+func (s *ShardedClient) fanOut(shards []int, fn func(c *RocksDBClient, shard int) error) error {
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for n, i := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[n] = fn(s.shards[i], i)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connections to all shards.
+func (s *ShardedClient) Close() {
+	for _, c := range s.shards {
+		c.Close()
+	}
+}
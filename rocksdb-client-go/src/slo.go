@@ -0,0 +1,226 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults used by NewSLOTracker.
+var (
+	DefaultSLOWindows    = []time.Duration{5 * time.Minute, time.Hour}
+	DefaultSLOResolution = 10 * time.Second
+)
+
+// SLOTarget is the objective for one action.
+type SLOTarget struct {
+	// Action is the protocol action the target applies to. An empty Action
+	// applies to every action without a target of its own; each such action
+	// is still tracked separately.
+	Action string
+	// SuccessRate is the fraction of requests that must be good, e.g. 0.999.
+	SuccessRate float64
+	// Latency, when set, counts successful requests slower than it as bad.
+	Latency time.Duration
+}
+
+// SLOOptions configures NewSLOTracker.
+type SLOOptions struct {
+	Targets []SLOTarget
+	// Windows are the periods burn rates are computed over. Defaults to
+	// DefaultSLOWindows.
+	Windows []time.Duration
+	// Resolution is the size of the time buckets requests are counted in,
+	// which bounds how precisely windows end. Defaults to 10s.
+	Resolution time.Duration
+	// IsFailure decides which errors count against the objective. Defaults
+	// to IsSLOFailure.
+	IsFailure func(error) bool
+}
+
+// SLOWindow holds the counts of one burn-rate window.
+type SLOWindow struct {
+	Window time.Duration
+	Total  int64
+	Bad    int64
+	// BurnRate is how fast the window consumes the error budget: 1 uses up
+	// exactly the budget over the SLO period, 14.4 over a 1h window is the
+	// classic page-worthy rate for a 30 day objective.
+	BurnRate float64
+}
+
+// SLOStatus reports one tracked action.
+type SLOStatus struct {
+	Action string
+	Target SLOTarget
+	// Total and Bad count requests since the tracker was created.
+	Total int64
+	Bad   int64
+	// SuccessRate is the fraction of good requests, 1 without requests.
+	SuccessRate float64
+	// BudgetRemaining is the fraction of the error budget of all tracked
+	// requests that is left; it goes negative once the budget is exceeded.
+	BudgetRemaining float64
+	Windows         []SLOWindow
+}
+
+// Met reports whether the success rate meets the target.
+func (s SLOStatus) Met() bool {
+	return s.SuccessRate >= s.Target.SuccessRate
+}
+
+type sloBucket struct {
+	epoch      int64
+	total, bad int64
+}
+
+type sloSeries struct {
+	target     SLOTarget
+	total, bad int64
+	buckets    []sloBucket
+}
+
+// SLOTracker records the success rate and latency of requests against
+// per-action targets and reports error budget burn rates, so applications can
+// alert on their storage dependency degrading before users notice.
+type SLOTracker struct {
+	opts    SLOOptions
+	targets map[string]SLOTarget
+	// fallback is the target with an empty Action, if any.
+	fallback *SLOTarget
+
+	mu     sync.Mutex
+	series map[string]*sloSeries
+}
+
+// NewSLOTracker creates an SLO tracker for c and installs it as an
+// interceptor. Only actions with a target are tracked.
+func NewSLOTracker(c *RocksDBClient, opts SLOOptions) *SLOTracker {
+	if len(opts.Windows) == 0 {
+		opts.Windows = DefaultSLOWindows
+	}
+	opts.Windows = slices.Clone(opts.Windows)
+	slices.Sort(opts.Windows)
+	if opts.Resolution <= 0 {
+		opts.Resolution = DefaultSLOResolution
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = IsSLOFailure
+	}
+	t := &SLOTracker{opts: opts, targets: map[string]SLOTarget{}, series: map[string]*sloSeries{}}
+	for _, target := range opts.Targets {
+		if target.Action == "" {
+			t.fallback = &target
+		} else {
+			t.targets[target.Action] = target
+		}
+	}
+	c.Use(t.intercept)
+	return t
+}
+
+// sloExpectedErrors are outcomes the server reports correctly; they say
+// nothing about its health.
+var sloExpectedErrors = []error{
+	ErrKeyNotFound, ErrUnauthorized, ErrTxnConflict, ErrIteratorInvalid, ErrLockHeld, ErrFenced,
+	ErrLeaseExpired, ErrReplicaBehind, ErrSnapshotNotFound, ErrValidation, ErrUnsupportedAction,
+}
+
+// IsSLOFailure reports whether err means the server failed to serve a
+// request: connection failures, timeouts, maintenance mode, corruption and
+// unclassified server errors. Expected outcomes such as ErrKeyNotFound or
+// ErrTxnConflict count as good requests.
+func IsSLOFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, expected := range sloExpectedErrors {
+		if errors.Is(err, expected) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *SLOTracker) intercept(request Request, next Handler) (*Response, error) {
+	target, tracked := t.targets[request.Action]
+	if !tracked && t.fallback != nil {
+		target, tracked = *t.fallback, true
+	}
+	if !tracked {
+		return next(request)
+	}
+	start := time.Now()
+	response, err := next(request)
+	latency := time.Since(start)
+	bad := t.opts.IsFailure(err) || (err == nil && target.Latency > 0 && latency > target.Latency)
+	t.record(request.Action, target, start, bad)
+	return response, err
+}
+
+func (t *SLOTracker) record(action string, target SLOTarget, at time.Time, bad bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.series[action]
+	if s == nil {
+		longest := t.opts.Windows[len(t.opts.Windows)-1]
+		s = &sloSeries{target: target, buckets: make([]sloBucket, longest/t.opts.Resolution+1)}
+		t.series[action] = s
+	}
+	epoch := at.UnixNano() / int64(t.opts.Resolution)
+	b := &s.buckets[epoch%int64(len(s.buckets))]
+	if b.epoch != epoch {
+		*b = sloBucket{epoch: epoch}
+	}
+	b.total++
+	s.total++
+	if bad {
+		b.bad++
+		s.bad++
+	}
+}
+
+// Stats returns the status of every tracked action, sorted by action.
+func (t *SLOTracker) Stats() []SLOStatus {
+	now := time.Now().UnixNano() / int64(t.opts.Resolution)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]SLOStatus, 0, len(t.series))
+	for action, s := range t.series {
+		status := SLOStatus{Action: action, Target: s.target, Total: s.total, Bad: s.bad, SuccessRate: 1, BudgetRemaining: 1}
+		budget := 1 - s.target.SuccessRate
+		if s.total > 0 {
+			status.SuccessRate = 1 - float64(s.bad)/float64(s.total)
+			if budget > 0 {
+				status.BudgetRemaining = 1 - float64(s.bad)/float64(s.total)/budget
+			}
+		}
+		for _, window := range t.opts.Windows {
+			w := SLOWindow{Window: window}
+			oldest := now - int64(window/t.opts.Resolution)
+			for _, b := range s.buckets {
+				if b.epoch > oldest && b.epoch <= now {
+					w.Total += b.total
+					w.Bad += b.bad
+				}
+			}
+			if w.Total > 0 && budget > 0 {
+				w.BurnRate = float64(w.Bad) / float64(w.Total) / budget
+			}
+			status.Windows = append(status.Windows, w)
+		}
+		stats = append(stats, status)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Action < stats[j].Action })
+	return stats
+}
+
+// Reset clears all counts.
+func (t *SLOTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.series = map[string]*sloSeries{}
+}
@@ -0,0 +1,171 @@
+package rocksdbclient
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// This file implements the snappy block format
+// (https://github.com/google/snappy/blob/main/format_description.txt), so
+// values compressed by CompressionTransformer can be read by any snappy
+// implementation and the client needs no dependency.
+
+var errSnappyCorrupt = errors.New("snappy: corrupt input")
+
+const (
+	snappyTagLiteral = 0
+	snappyTagCopy1   = 1
+	snappyTagCopy2   = 2
+	snappyTagCopy4   = 3
+
+	snappyMinMatch  = 4
+	snappyMaxOffset = 1<<16 - 1
+	snappyTableBits = 14
+	// snappyMaxExpansion bounds the decoded size per encoded byte: a 3 byte
+	// copy produces at most 64 bytes.
+	snappyMaxExpansion = 22
+)
+
+// snappyEncode compresses src into a snappy block. It finds matches through
+// a hash table of 4 byte sequences and only emits offsets up to 64 KiB.
+func snappyEncode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)/2+16), uint64(len(src)))
+	var table [1 << snappyTableBits]int32
+	literal := 0
+	for i := 0; i+snappyMinMatch <= len(src); {
+		seq := binary.LittleEndian.Uint32(src[i:])
+		h := (seq * 0x1e35a7bd) >> (32 - snappyTableBits)
+		// Entries hold the position plus one, so 0 means empty.
+		candidate := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if candidate < 0 || i-candidate > snappyMaxOffset || binary.LittleEndian.Uint32(src[candidate:]) != seq {
+			i++
+			continue
+		}
+		length := snappyMinMatch
+		for i+length < len(src) && src[candidate+length] == src[i+length] {
+			length++
+		}
+		dst = appendSnappyLiteral(dst, src[literal:i])
+		dst = appendSnappyCopy(dst, i-candidate, length)
+		i += length
+		literal = i
+	}
+	return appendSnappyLiteral(dst, src[literal:])
+}
+
+func appendSnappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	switch n := uint32(len(lit) - 1); {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyTagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyTagLiteral, byte(n))
+	case n < 1<<16:
+		dst = binary.LittleEndian.AppendUint16(append(dst, 61<<2|snappyTagLiteral), uint16(n))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = binary.LittleEndian.AppendUint32(append(dst, 63<<2|snappyTagLiteral), n)
+	}
+	return append(dst, lit...)
+}
+
+// appendSnappyCopy emits a match as copies of at most 64 bytes, keeping the
+// last one at least 4 bytes long so it can use the short form.
+func appendSnappyCopy(dst []byte, offset, length int) []byte {
+	for length >= 68 {
+		dst = appendSnappyCopy2(dst, offset, 64)
+		length -= 64
+	}
+	if length > 64 {
+		dst = appendSnappyCopy2(dst, offset, 60)
+		length -= 60
+	}
+	if length >= 12 || offset >= 2048 {
+		return appendSnappyCopy2(dst, offset, length)
+	}
+	return append(dst, byte(offset>>8)<<5|byte(length-4)<<2|snappyTagCopy1, byte(offset))
+}
+
+func appendSnappyCopy2(dst []byte, offset, length int) []byte {
+	return binary.LittleEndian.AppendUint16(append(dst, byte(length-1)<<2|snappyTagCopy2), uint16(offset))
+}
+
+// snappyDecode decompresses a snappy block, including the copy forms
+// snappyEncode does not emit.
+func snappyDecode(src []byte) ([]byte, error) {
+	size, n := binary.Uvarint(src)
+	if n <= 0 || size > uint64(len(src))*snappyMaxExpansion {
+		return nil, errSnappyCorrupt
+	}
+	dst := make([]byte, 0, size)
+	for s := n; s < len(src); {
+		tag := src[s]
+		var length, offset int
+		switch tag & 3 {
+		case snappyTagLiteral:
+			length = int(tag>>2) + 1
+			s++
+			if length > 60 {
+				extra := length - 60
+				if extra > len(src)-s {
+					return nil, errSnappyCorrupt
+				}
+				var v uint64
+				for i := extra - 1; i >= 0; i-- {
+					v = v<<8 | uint64(src[s+i])
+				}
+				s += extra
+				if v >= uint64(len(src)-s) {
+					return nil, errSnappyCorrupt
+				}
+				length = int(v) + 1
+			}
+			if length > len(src)-s || uint64(len(dst)+length) > size {
+				return nil, errSnappyCorrupt
+			}
+			dst = append(dst, src[s:s+length]...)
+			s += length
+			continue
+		case snappyTagCopy1:
+			if len(src)-s < 2 {
+				return nil, errSnappyCorrupt
+			}
+			length = int(tag>>2&7) + 4
+			offset = int(tag>>5)<<8 | int(src[s+1])
+			s += 2
+		case snappyTagCopy2:
+			if len(src)-s < 3 {
+				return nil, errSnappyCorrupt
+			}
+			length = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint16(src[s+1:]))
+			s += 3
+		case snappyTagCopy4:
+			if len(src)-s < 5 {
+				return nil, errSnappyCorrupt
+			}
+			length = int(tag>>2) + 1
+			o := binary.LittleEndian.Uint32(src[s+1:])
+			if uint64(o) > uint64(len(dst)) {
+				return nil, errSnappyCorrupt
+			}
+			offset = int(o)
+			s += 5
+		}
+		if offset <= 0 || offset > len(dst) || uint64(len(dst)+length) > size {
+			return nil, errSnappyCorrupt
+		}
+		// Copies may overlap the bytes they produce, so go byte by byte.
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+	if uint64(len(dst)) != size {
+		return nil, errSnappyCorrupt
+	}
+	return dst, nil
+}
@@ -0,0 +1,117 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RetainedSnapshot describes a named RocksDB snapshot kept by the server.
+type RetainedSnapshot struct {
+	Label string `json:"label"`
+	// Seq is the sequence number the snapshot reads at.
+	Seq       uint64    `json:"seq"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is zero for snapshots without a TTL.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RetainSnapshot takes a snapshot of the database and keeps it under label
+// with the `retain_snapshot` admin action, so it can be read later with
+// GetAt, e.g. "end-of-month" for reporting. A snapshot pins every version it
+// can see, so old data is not compacted away while it is retained: release
+// snapshots with ReleaseSnapshot, give them a TTL (zero keeps them until
+// released) or let the policy set with SetSnapshotGCPolicy collect them.
+// Retaining an existing label fails.
+func (c *RocksDBClient) RetainSnapshot(label string, ttl time.Duration) (*RetainedSnapshot, error) {
+	if label == "" {
+		return nil, errors.New("snapshot label must not be empty")
+	}
+	request := Request{Action: ActionRetainSnapshot, Options: map[string]string{OptionLabel: label}}
+	if ttl != 0 {
+		seconds, err := ttlOption(ttl)
+		if err != nil {
+			return nil, err
+		}
+		request.Options[OptionTTL] = seconds
+	}
+	response, err := c.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &RetainedSnapshot{}
+	if err := json.Unmarshal([]byte(response.Result), snapshot); err != nil {
+		return nil, fmt.Errorf("error decoding retain_snapshot result: %w", err)
+	}
+	return snapshot, nil
+}
+
+// ReleaseSnapshot releases the snapshot kept under label with the
+// `release_snapshot` admin action.
+func (c *RocksDBClient) ReleaseSnapshot(label string) error {
+	_, err := c.SendRequest(Request{
+		Action:  ActionReleaseSnapshot,
+		Options: map[string]string{OptionLabel: label},
+	})
+	return err
+}
+
+// ListSnapshots returns the retained snapshots, oldest first, with the
+// `list_snapshots` action.
+func (c *RocksDBClient) ListSnapshots() ([]RetainedSnapshot, error) {
+	response, err := c.SendRequest(Request{Action: ActionListSnapshots, Options: map[string]string{}})
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []RetainedSnapshot
+	if err := json.Unmarshal([]byte(response.Result), &snapshots); err != nil {
+		return nil, fmt.Errorf("error decoding list_snapshots result: %w", err)
+	}
+	return snapshots, nil
+}
+
+// SnapshotGCPolicy bounds the snapshots the server retains. Zero fields are
+// not limited.
+type SnapshotGCPolicy struct {
+	// MaxSnapshots releases the oldest snapshots beyond this count.
+	MaxSnapshots int
+	// MaxAge releases snapshots older than this.
+	MaxAge time.Duration
+}
+
+// SetSnapshotGCPolicy sets the server's snapshot GC policy with the
+// `set_snapshot_policy` admin action. It applies to all retained snapshots
+// in addition to their own TTLs.
+func (c *RocksDBClient) SetSnapshotGCPolicy(policy SnapshotGCPolicy) error {
+	if policy.MaxSnapshots < 0 || policy.MaxAge < 0 {
+		return errors.New("snapshot policy limits must not be negative")
+	}
+	request := Request{Action: ActionSetSnapshotPolicy, Options: map[string]string{
+		OptionMaxSnapshots: strconv.Itoa(policy.MaxSnapshots),
+		OptionMaxAge:       "0",
+	}}
+	if policy.MaxAge > 0 {
+		seconds, _ := ttlOption(policy.MaxAge)
+		request.Options[OptionMaxAge] = seconds
+	}
+	_, err := c.SendRequest(request)
+	return err
+}
+
+// GetAt reads key as of the snapshot retained under label. A key that did
+// not exist at the time fails with ErrKeyNotFound. A label that was never
+// retained, was released or was collected fails with ErrSnapshotNotFound.
+func (c *RocksDBClient) GetAt(label, key string, cfName *string) (string, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionGet,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{OptionSnapshot: label},
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
@@ -0,0 +1,204 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+const defaultSpillPageSize = 1000
+
+// SpillOptions controls how large result sets are streamed to disk.
+type SpillOptions struct {
+	// Dir is the directory for the temporary file. os.TempDir() is used when empty.
+	Dir string
+	// PageSize is the number of keys fetched per round trip. Defaults to 1000.
+	PageSize int
+}
+
+// SpillStore is a temporary file holding key/value records streamed from the
+// server. Only one page of results is kept in memory while it is filled, so
+// memory usage does not grow with the size of the dataset.
+type SpillStore struct {
+	path  string
+	file  *os.File
+	w     *bufio.Writer
+	count int
+}
+
+func newSpillStore(dir string) (*SpillStore, error) {
+	file, err := os.CreateTemp(dir, "rocksdb-spill-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating spill file: %w", err)
+	}
+	return &SpillStore{path: file.Name(), file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// append writes one record as two length-prefixed strings, so keys and
+// values may contain any bytes including newlines.
+func (s *SpillStore) append(key, value string) error {
+	var buf [binary.MaxVarintLen64]byte
+	for _, field := range []string{key, value} {
+		n := binary.PutUvarint(buf[:], uint64(len(field)))
+		if _, err := s.w.Write(buf[:n]); err != nil {
+			return fmt.Errorf("error writing spill file: %w", err)
+		}
+		if _, err := s.w.WriteString(field); err != nil {
+			return fmt.Errorf("error writing spill file: %w", err)
+		}
+	}
+	s.count++
+	return nil
+}
+
+func (s *SpillStore) finish() error {
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("error writing spill file: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of records in the store.
+func (s *SpillStore) Len() int {
+	return s.count
+}
+
+// Iterator opens an independent cursor over the stored records.
+func (s *SpillStore) Iterator() (*SpillIterator, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening spill file: %w", err)
+	}
+	return &SpillIterator{file: file, r: bufio.NewReader(file)}, nil
+}
+
+// Close removes the backing file. Open iterators become invalid.
+func (s *SpillStore) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	s.file = nil
+	return os.Remove(s.path)
+}
+
+// SpillIterator walks the records of a SpillStore in insertion order.
+type SpillIterator struct {
+	file  *os.File
+	r     *bufio.Reader
+	key   string
+	value string
+	err   error
+}
+
+// Next advances to the next record and reports whether one was read.
+func (it *SpillIterator) Next() bool {
+	if it.err != nil || it.r == nil {
+		return false
+	}
+	key, err := it.readField()
+	if err == io.EOF {
+		return false
+	}
+	if err == nil {
+		it.value, err = it.readField()
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		it.err = fmt.Errorf("error reading spill file: %w", err)
+		return false
+	}
+	it.key = key
+	return true
+}
+
+func (it *SpillIterator) readField() (string, error) {
+	n, err := binary.ReadUvarint(it.r)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(it.r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// Key returns the key of the current record.
+func (it *SpillIterator) Key() string {
+	return it.key
+}
+
+// Value returns the value of the current record.
+func (it *SpillIterator) Value() string {
+	return it.value
+}
+
+// Err returns the first read error encountered, if any.
+func (it *SpillIterator) Err() error {
+	return it.err
+}
+
+// Close releases the file handle held by the iterator.
+func (it *SpillIterator) Close() error {
+	if it.file == nil {
+		return nil
+	}
+	err := it.file.Close()
+	it.file = nil
+	it.r = nil
+	return err
+}
+
+// AllSpilled is a memory-bounded alternative to All. Keys matching query are
+// fetched page by page with the `keys` action, their values with one
+// `multi_get` per page, and both are written to a temporary file; the caller
+// iterates the returned store and must Close it. Keys deleted between the two
+// requests are left out.
+func (c *RocksDBClient) AllSpilled(query string, opts SpillOptions) (*SpillStore, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultSpillPageSize
+	}
+
+	store, err := newSpillStore(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; ; start += pageSize {
+		keys, err := c.keysPage(query, start, pageSize)
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		values, err := c.MultiGet(keys, nil)
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		for _, key := range keys {
+			value := values[key]
+			if value == nil {
+				continue
+			}
+			if err := store.append(key, *value); err != nil {
+				store.Close()
+				return nil, err
+			}
+		}
+		if len(keys) < pageSize {
+			break
+		}
+	}
+
+	if err := store.finish(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
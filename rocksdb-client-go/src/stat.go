@@ -0,0 +1,63 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// KeyStat is the metadata of a key returned by Stat. Fields the server does
+// not track for the key are left nil.
+type KeyStat struct {
+	Key string `json:"key"`
+	// Size is the value size in bytes.
+	Size int64 `json:"size"`
+	// Sequence is the RocksDB sequence number of the latest write.
+	Sequence uint64 `json:"sequence"`
+	// CreatedAt and ModifiedAt are only known for keys written by servers
+	// that store write timestamps.
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ModifiedAt *time.Time `json:"modified_at,omitempty"`
+	// ExpiresAt is set for keys written with a TTL.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Stat returns the metadata of key without transferring its value, using the
+// `stat` action. Missing keys return an error matching ErrKeyNotFound.
+func (c *RocksDBClient) Stat(key string, cfName *string) (*KeyStat, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionStat,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	stat := &KeyStat{}
+	if err := json.Unmarshal([]byte(response.Result), stat); err != nil {
+		return nil, fmt.Errorf("error decoding stat result: %w", err)
+	}
+	return stat, nil
+}
+
+// Exists reports whether key exists without transferring its value. The
+// server answers the `exists` action with RocksDB's KeyMayExist and only
+// reads the key when the bloom filters cannot rule it out.
+func (c *RocksDBClient) Exists(key string, cfName *string) (bool, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionExists,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{},
+	})
+	if err != nil {
+		return false, err
+	}
+	exists, err := strconv.ParseBool(response.Result)
+	if err != nil {
+		return false, fmt.Errorf("error decoding exists result: %w", err)
+	}
+	return exists, nil
+}
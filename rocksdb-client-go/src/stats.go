@@ -0,0 +1,87 @@
+package rocksdbclient
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// numLevels is the number of LSM levels reported in DBStats.LevelFiles,
+// matching RocksDB's default num_levels.
+const numLevels = 7
+
+// DBStats is a typed snapshot of the RocksDB properties operators look at
+// most. All sizes are in bytes.
+type DBStats struct {
+	// MemtableSize is the size of the active and unflushed immutable
+	// memtables (rocksdb.cur-size-all-mem-tables).
+	MemtableSize uint64
+	// BlockCacheUsage is the memory used by the block cache
+	// (rocksdb.block-cache-usage).
+	BlockCacheUsage uint64
+	// BlockCachePinnedUsage is the part of it pinned by open iterators and
+	// table readers (rocksdb.block-cache-pinned-usage).
+	BlockCachePinnedUsage uint64
+	// PendingCompactionBytes estimates the bytes compaction must rewrite to
+	// bring every level under its target size
+	// (rocksdb.estimate-pending-compaction-bytes).
+	PendingCompactionBytes uint64
+	// RunningCompactions and RunningFlushes count the background jobs in
+	// progress.
+	RunningCompactions uint64
+	RunningFlushes     uint64
+	// EstimatedKeys is rocksdb.estimate-num-keys.
+	EstimatedKeys uint64
+	// LiveSSTSize is the total size of the SST files of the current version
+	// (rocksdb.live-sst-files-size).
+	LiveSSTSize uint64
+	// LevelFiles is the number of SST files at each level, L0 first
+	// (rocksdb.num-files-at-level<N>).
+	LevelFiles [numLevels]uint64
+}
+
+// Stats reads DBStats for a column family (the default one when cfName is
+// nil) with one get_property request per property. The properties are read
+// one after another, so the snapshot is not atomic.
+func (c *RocksDBClient) Stats(cfName *string) (*DBStats, error) {
+	type property struct {
+		name  string
+		value *uint64
+	}
+	stats := &DBStats{}
+	properties := []property{
+		{"rocksdb.cur-size-all-mem-tables", &stats.MemtableSize},
+		{"rocksdb.block-cache-usage", &stats.BlockCacheUsage},
+		{"rocksdb.block-cache-pinned-usage", &stats.BlockCachePinnedUsage},
+		{"rocksdb.estimate-pending-compaction-bytes", &stats.PendingCompactionBytes},
+		{"rocksdb.num-running-compactions", &stats.RunningCompactions},
+		{"rocksdb.num-running-flushes", &stats.RunningFlushes},
+		{"rocksdb.estimate-num-keys", &stats.EstimatedKeys},
+		{"rocksdb.live-sst-files-size", &stats.LiveSSTSize},
+	}
+	for level := range stats.LevelFiles {
+		name := "rocksdb.num-files-at-level" + strconv.Itoa(level)
+		properties = append(properties, property{name, &stats.LevelFiles[level]})
+	}
+
+	for _, p := range properties {
+		value, err := c.uintProperty(p.name, cfName)
+		if err != nil {
+			return nil, err
+		}
+		*p.value = value
+	}
+	return stats, nil
+}
+
+// uintProperty reads an integer RocksDB property.
+func (c *RocksDBClient) uintProperty(name string, cfName *string) (uint64, error) {
+	response, err := c.GetProperty(&name, cfName)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(response.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding %s: %w", name, err)
+	}
+	return value, nil
+}
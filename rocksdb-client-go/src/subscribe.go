@@ -0,0 +1,226 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Change streams run on a dedicated connection, since the server pushes
+// events instead of answering one request at a time. The client sends a
+// single request, the server acknowledges it with a regular response and
+// then writes one JSON ChangeEvent per line until the connection closes.
+
+// ChangeEvent is one write delivered by a change stream.
+type ChangeEvent struct {
+	Type OperationType `json:"type"`
+	Key  string        `json:"key"`
+	// Value is nil for deletes.
+	Value *string `json:"value,omitempty"`
+	CF    string  `json:"cf_name,omitempty"`
+	// Seq is the RocksDB sequence number of the write.
+	Seq uint64 `json:"seq"`
+}
+
+// Subscription delivers the change events of Subscribe or GetUpdatesSince.
+type Subscription struct {
+	events chan ChangeEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	conn    net.Conn
+	err     error
+	lastSeq uint64
+}
+
+// Subscribe streams the puts, merges and deletes of keys starting with
+// prefix, in the client's default column family, with the `subscribe`
+// action. Events arrive in sequence order on Events. If the stream
+// connection drops, the subscription reconnects and resumes after the last
+// delivered sequence number; it ends when ctx is done, on Close, or when it
+// cannot reconnect, which Err reports.
+//
+//	sub, err := client.Subscribe(ctx, "user:")
+//	if err != nil {
+//		return err
+//	}
+//	defer sub.Close()
+//	for event := range sub.Events() {
+//		cache.Invalidate(event.Key)
+//	}
+//	return sub.Err()
+func (c *RocksDBClient) Subscribe(ctx context.Context, prefix string) (*Subscription, error) {
+	request := Request{
+		Action:  ActionSubscribe,
+		CfName:  c.defaultCF,
+		Options: map[string]string{OptionPrefix: prefix},
+	}
+	return c.openSubscription(ctx, request)
+}
+
+func (c *RocksDBClient) openSubscription(ctx context.Context, request Request) (*Subscription, error) {
+	conn, reader, _, err := c.openStream(request)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription{
+		events: make(chan ChangeEvent, 64),
+		cancel: cancel,
+		done:   make(chan struct{}),
+		conn:   conn,
+	}
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		s.conn.Close()
+		s.mu.Unlock()
+	}()
+	go s.run(ctx, c, request, reader)
+	return s, nil
+}
+
+func (s *Subscription) run(ctx context.Context, c *RocksDBClient, request Request, reader *bufio.Reader) {
+	defer close(s.done)
+	defer close(s.events)
+	defer s.cancel()
+	for {
+		s.read(ctx, reader)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case <-time.After(c.retryInterval):
+		case <-ctx.Done():
+			return
+		}
+
+		if seq := s.lastSequence(); seq > 0 {
+			request.Options[OptionSinceSeq] = strconv.FormatUint(seq+1, 10)
+		}
+		conn, r, _, err := c.openStream(request)
+		s.mu.Lock()
+		if err != nil {
+			s.err = err
+			s.mu.Unlock()
+			return
+		}
+		s.conn, reader = conn, r
+		s.mu.Unlock()
+		if ctx.Err() != nil {
+			conn.Close()
+			return
+		}
+	}
+}
+
+// read delivers events until the stream connection fails or ctx is done.
+func (s *Subscription) read(ctx context.Context, reader *bufio.Reader) error {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return err
+		}
+		var event ChangeEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("error decoding change event: %w", err)
+		}
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.mu.Lock()
+		s.lastSeq = event.Seq
+		s.mu.Unlock()
+	}
+}
+
+func (s *Subscription) lastSequence() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeq
+}
+
+// Events returns the channel of change events. It is closed when the
+// subscription ends.
+func (s *Subscription) Events() <-chan ChangeEvent {
+	return s.events
+}
+
+// LastSeq returns the sequence number of the last event delivered.
+func (s *Subscription) LastSeq() uint64 {
+	return s.lastSequence()
+}
+
+// Err returns the error that ended the subscription, or nil if it ended
+// through its context or Close.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription and waits for it to shut down.
+func (s *Subscription) Close() {
+	s.cancel()
+	for range s.events {
+	}
+	<-s.done
+}
+
+// openStream dials a dedicated connection to the current endpoint, sends
+// request and returns the server's acknowledgement. The connection is then
+// left without a deadline for the server to push events on.
+func (c *RocksDBClient) openStream(request Request) (net.Conn, *bufio.Reader, *Response, error) {
+	c.mu.Lock()
+	addr, codec := c.endpoints[c.endpoint], c.codec
+	request.Token = c.token
+	c.mu.Unlock()
+
+	conn, err := c.dialAddr(addr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	response, err := streamHandshake(conn, reader, codec, request, c.timeout)
+	if err == nil && !response.Success {
+		err = newServerError(request.Action, response.Result)
+	}
+	if err == nil {
+		err = conn.SetDeadline(time.Time{})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	return conn, reader, response, nil
+}
+
+func streamHandshake(conn net.Conn, reader *bufio.Reader, codec JSONCodec, request Request, timeout time.Duration) (*Response, error) {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, fmt.Errorf("error setting deadline: %w", err)
+	}
+	data, err := codec.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, wrapTimeout(request.Action, fmt.Errorf("error sending request: %w", err))
+	}
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, wrapTimeout(request.Action, fmt.Errorf("error reading response: %w", err))
+	}
+	response := &Response{}
+	if err := codec.Unmarshal(line, response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	return response, nil
+}
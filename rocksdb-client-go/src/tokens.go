@@ -0,0 +1,96 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Access levels granted by a TokenScope.
+const (
+	AccessRead  = "read"
+	AccessWrite = "write"
+	AccessAdmin = "admin"
+)
+
+// TokenScope grants an access level, optionally limited to one column
+// family. An empty ColumnFamily applies to all column families.
+type TokenScope struct {
+	Access       string `json:"access"`
+	ColumnFamily string `json:"cf_name,omitempty"`
+}
+
+// TokenInfo describes an auth token managed by the server. Secret is only
+// populated in the response to CreateToken.
+type TokenInfo struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Secret    string       `json:"secret,omitempty"`
+	Scopes    []TokenScope `json:"scopes"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+// tokenRequest is the JSON payload of token management requests, sent in
+// the request value.
+type tokenRequest struct {
+	ID        string       `json:"id,omitempty"`
+	Name      string       `json:"name,omitempty"`
+	Scopes    []TokenScope `json:"scopes,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+func (c *RocksDBClient) sendTokenRequest(action string, payload tokenRequest, result any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	value := string(data)
+	response, err := c.SendRequest(Request{
+		Action:  action,
+		Value:   &value,
+		Options: map[string]string{},
+	})
+	if err != nil || result == nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(response.Result), result); err != nil {
+		return fmt.Errorf("error decoding %s result: %w", action, err)
+	}
+	return nil
+}
+
+// CreateToken provisions a new auth token with the given scopes. A zero ttl
+// creates a token that does not expire. The returned TokenInfo holds the
+// secret, which the server does not reveal again.
+func (c *RocksDBClient) CreateToken(name string, scopes []TokenScope, ttl time.Duration) (*TokenInfo, error) {
+	payload := tokenRequest{Name: name, Scopes: scopes}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl).UTC()
+		payload.ExpiresAt = &expiresAt
+	}
+	var token TokenInfo
+	if err := c.sendTokenRequest(ActionCreateToken, payload, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeToken invalidates the token with the given ID.
+func (c *RocksDBClient) RevokeToken(id string) error {
+	return c.sendTokenRequest(ActionRevokeToken, tokenRequest{ID: id}, nil)
+}
+
+// SetTokenScopes replaces the scopes granted to a token.
+func (c *RocksDBClient) SetTokenScopes(id string, scopes []TokenScope) error {
+	return c.sendTokenRequest(ActionSetTokenScopes, tokenRequest{ID: id, Scopes: scopes}, nil)
+}
+
+// ListTokens returns all tokens known to the server, without secrets.
+func (c *RocksDBClient) ListTokens() ([]TokenInfo, error) {
+	var tokens []TokenInfo
+	if err := c.sendTokenRequest(ActionListTokens, tokenRequest{}, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
@@ -0,0 +1,181 @@
+package rocksdbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTxnDone is returned by operations on a transaction that was already
+// committed or rolled back.
+var ErrTxnDone = errors.New("transaction has already been committed or rolled back")
+
+// Transaction is a handle to one server-side transaction. Every request it
+// sends carries the transaction ID assigned by the server, so several
+// transactions can be open on the same client at once.
+type Transaction struct {
+	c    *RocksDBClient
+	id   string
+	done bool
+	// heartbeat is the background keep-alive started by StartHeartbeat.
+	heartbeat *heartbeat
+}
+
+// BeginTransaction starts a transaction with the `begin_transaction` action
+// and returns a handle addressing it.
+func (c *RocksDBClient) BeginTransaction() (*Transaction, error) {
+	response, err := c.SendRequest(Request{
+		Action:  ActionBeginTransaction,
+		Options: map[string]string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Result == "" {
+		return nil, fmt.Errorf("server did not return a transaction id")
+	}
+	return &Transaction{c: c, id: response.Result}, nil
+}
+
+// ID returns the server-assigned transaction ID.
+func (t *Transaction) ID() string {
+	return t.id
+}
+
+func (t *Transaction) send(request Request) (*Response, error) {
+	if t.done {
+		return nil, ErrTxnDone
+	}
+	txn := true
+	request.Txn = &txn
+	request.TxnID = &t.id
+	if request.Options == nil {
+		request.Options = map[string]string{}
+	}
+	response, err := t.c.SendRequest(request)
+	if err != nil {
+		return nil, lockConflict(err, request, t.id)
+	}
+	return response, nil
+}
+
+// Get reads key within the transaction.
+func (t *Transaction) Get(key string, cfName *string) (string, error) {
+	response, err := t.send(Request{Action: ActionGet, Key: &key, CfName: cfName})
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
+
+// GetForUpdate reads key within the transaction and locks it, so concurrent
+// transactions writing or locking the same key conflict with this one until
+// it finishes. Unlike Get it also locks keys that do not exist yet.
+func (t *Transaction) GetForUpdate(key string, cfName *string) (string, error) {
+	response, err := t.send(Request{
+		Action:  ActionGet,
+		Key:     &key,
+		CfName:  cfName,
+		Options: map[string]string{OptionForUpdate: "true"},
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.Result, nil
+}
+
+// Put writes key within the transaction.
+func (t *Transaction) Put(key, value string, cfName *string) error {
+	_, err := t.send(Request{Action: ActionPut, Key: &key, Value: &value, CfName: cfName})
+	return err
+}
+
+// Delete removes key within the transaction.
+func (t *Transaction) Delete(key string, cfName *string) error {
+	_, err := t.send(Request{Action: ActionDelete, Key: &key, CfName: cfName})
+	return err
+}
+
+// Merge applies a merge operand to key within the transaction.
+func (t *Transaction) Merge(key, value string, cfName *string) error {
+	_, err := t.send(Request{Action: ActionMerge, Key: &key, Value: &value, CfName: cfName})
+	return err
+}
+
+// Commit commits the transaction. After a successful commit the handle can
+// no longer be used; after a failed one it should be rolled back.
+func (t *Transaction) Commit() error {
+	t.StopHeartbeat()
+	_, err := t.send(Request{Action: ActionCommitTransaction})
+	if err == nil {
+		t.done = true
+	}
+	return err
+}
+
+// Rollback discards the transaction. Rolling back a finished transaction
+// returns ErrTxnDone.
+func (t *Transaction) Rollback() error {
+	t.StopHeartbeat()
+	_, err := t.send(Request{Action: ActionRollbackTransaction})
+	if err != ErrTxnDone {
+		t.done = true
+	}
+	return err
+}
+
+// SetTransactionRetryPolicy configures how WithTransaction retries
+// conflicting transactions.
+func (c *RocksDBClient) SetTransactionRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txnRetry = policy
+}
+
+// WithTransaction runs fn inside a transaction and commits it. If fn or the
+// commit fails with ErrTxnConflict (lock timeouts, deadlocks, busy keys) the
+// transaction is rolled back and fn is retried according to the client's
+// transaction retry policy. Any other error, or a panic in fn, rolls the
+// transaction back; panics are re-raised after the rollback.
+func (c *RocksDBClient) WithTransaction(ctx context.Context, fn func(txn *Transaction) error) error {
+	c.mu.Lock()
+	policy, heartbeat := c.txnRetry, c.txnHeartbeat
+	c.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := c.runTransaction(ctx, heartbeat, fn)
+		if err == nil || !errors.Is(err, ErrTxnConflict) || attempt >= policy.MaxRetries {
+			return err
+		}
+		if err := policy.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *RocksDBClient) runTransaction(ctx context.Context, heartbeat *HeartbeatOptions, fn func(txn *Transaction) error) (err error) {
+	txn, err := c.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	if heartbeat != nil {
+		txn.StartHeartbeat(ctx, *heartbeat)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			txn.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			txn.Rollback()
+		}
+	}()
+
+	if err := fn(txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
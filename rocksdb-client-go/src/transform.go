@@ -0,0 +1,246 @@
+package rocksdbclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValueTransformer converts values on their way to and from the server, e.g.
+// to compress or encrypt them. Decode must invert Encode.
+type ValueTransformer interface {
+	// Name identifies the transformer in the header of stored values. It
+	// must be non-empty, stable across releases and must not contain ',' or
+	// ':'.
+	Name() string
+	// Encode may return ErrSkipTransform to leave a value alone, e.g. when
+	// it is too small to be worth compressing.
+	Encode(value []byte) ([]byte, error)
+	Decode(value []byte) ([]byte, error)
+}
+
+// ErrSkipTransform is returned by ValueTransformer.Encode to store a value
+// without applying the transformer. A value skipped by every transformer is
+// stored as is, without a header.
+var ErrSkipTransform = errors.New("skip value transform")
+
+// transformedPrefix starts every transformed value. It is followed by the
+// comma-separated transformer names in the order they were applied, a ':' and
+// the base64 encoding of the transformed bytes.
+const transformedPrefix = "\x1evt1:"
+
+// UseValueTransformers installs an interceptor that passes values through
+// transformers on write and reverses them on read. Transformers are applied in
+// order on write and in reverse order on read. Stored values carry a header
+// naming the transformers, so values written without transformers, or before
+// they were enabled, are returned unchanged.
+//
+// Values of put, put_if_absent, compare_and_swap (including the expected
+// value), write_batch_put and the put operations of batch_write are encoded;
+// results of get, including conditional reads, multi_get, multi_get_cf,
+// compare_and_swap, iterator positioning and the old values of writes sent
+// WithReturnOld are decoded. Merge operands are left alone because the
+// server's merge operator has to understand them. Interceptors registered
+// earlier, such as UseCache, see plain values.
+//
+// compare_and_swap compares the encoded expected value with the stored bytes,
+// so CAS, Upsert and AddToSet only work with transformers whose Encode is
+// deterministic, like CompressionTransformer.
+func (c *RocksDBClient) UseValueTransformers(transformers ...ValueTransformer) {
+	byName := make(map[string]ValueTransformer, len(transformers))
+	for _, t := range transformers {
+		byName[t.Name()] = t
+	}
+
+	encode := func(value string) (string, error) {
+		data := []byte(value)
+		var applied []string
+		for _, t := range transformers {
+			encoded, err := t.Encode(data)
+			if errors.Is(err, ErrSkipTransform) {
+				continue
+			}
+			if err != nil {
+				return "", fmt.Errorf("error encoding value with %s: %w", t.Name(), err)
+			}
+			data = encoded
+			applied = append(applied, t.Name())
+		}
+		if len(applied) == 0 {
+			return value, nil
+		}
+		return transformedPrefix + strings.Join(applied, ",") + ":" + base64.StdEncoding.EncodeToString(data), nil
+	}
+	decode := func(value string) (string, error) {
+		rest, found := strings.CutPrefix(value, transformedPrefix)
+		if !found {
+			return value, nil
+		}
+		list, encoded, found := strings.Cut(rest, ":")
+		if !found {
+			return "", errors.New("malformed transformed value header")
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("error decoding transformed value: %w", err)
+		}
+		applied := strings.Split(list, ",")
+		for i := len(applied) - 1; i >= 0; i-- {
+			t, known := byName[applied[i]]
+			if !known {
+				return "", fmt.Errorf("value transformer %q is not installed", applied[i])
+			}
+			if data, err = t.Decode(data); err != nil {
+				return "", fmt.Errorf("error decoding value with %s: %w", t.Name(), err)
+			}
+		}
+		return string(data), nil
+	}
+
+	c.Use(func(request Request, next Handler) (*Response, error) {
+		var err error
+		switch request.Action {
+		case ActionPut, ActionPutIfAbsent, ActionWriteBatchPut, ActionCompareAndSwap:
+			if request.Value != nil {
+				var value string
+				if value, err = encode(*request.Value); err != nil {
+					return nil, err
+				}
+				request.Value = &value
+			}
+			if expected, found := request.Options[OptionExpected]; found && request.Action == ActionCompareAndSwap {
+				options := make(map[string]string, len(request.Options))
+				for k, v := range request.Options {
+					options[k] = v
+				}
+				if options[OptionExpected], err = encode(expected); err != nil {
+					return nil, err
+				}
+				request.Options = options
+			}
+		case ActionBatchWrite:
+			ops := make([]Operation, len(request.Operations))
+			for i, op := range request.Operations {
+				if op.Type == OpPut && op.Value != nil {
+					var value string
+					if value, err = encode(*op.Value); err != nil {
+						return nil, err
+					}
+					op.Value = &value
+				}
+				ops[i] = op
+			}
+			request.Operations = ops
+		}
+
+		response, err := next(request)
+		if err != nil {
+			return response, err
+		}
+
+		var result string
+		switch request.Action {
+		case ActionGet:
+			if _, conditional := request.Options[OptionIfModifiedSince]; conditional {
+				result, err = decodeField(response.Result, "value", decode)
+			} else {
+				result, err = decode(response.Result)
+			}
+		case ActionCompareAndSwap:
+			result, err = decodeField(response.Result, "current", decode)
+		case ActionPut, ActionDelete, ActionMerge:
+			if request.Options[OptionReturnOld] != "true" {
+				return response, nil
+			}
+			result, err = decodeField(response.Result, "old", decode)
+		case ActionMultiGet:
+			result, err = decodeMultiGet(response.Result, decode)
+		case ActionMultiGetCF:
+			result, err = decodeMultiGetCF(response.Result, decode)
+		case ActionIteratorSeek, ActionIteratorSeekForPrev, ActionIteratorSeekToFirst, ActionIteratorSeekToLast,
+			ActionIteratorNext, ActionIteratorPrev:
+			result, err = decodeIteratorEntry(response.Result, decode)
+		default:
+			return response, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Success: response.Success, Result: result}, nil
+	})
+}
+
+// decodeField decodes the string field name of a JSON object result.
+func decodeField(result, name string, decode func(string) (string, error)) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(result), &fields); err != nil {
+		// Leave it to the caller to report the malformed result.
+		return result, nil
+	}
+	var value *string
+	if err := json.Unmarshal(fields[name], &value); err != nil || value == nil {
+		return result, nil
+	}
+	decoded, err := decode(*value)
+	if err != nil {
+		return "", err
+	}
+	if fields[name], err = json.Marshal(decoded); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(fields)
+	return string(data), err
+}
+
+func decodeMultiGet(result string, decode func(string) (string, error)) (string, error) {
+	var values map[string]*string
+	if err := json.Unmarshal([]byte(result), &values); err != nil {
+		// Leave it to MultiGet to report the malformed result.
+		return result, nil
+	}
+	for key, value := range values {
+		if value == nil {
+			continue
+		}
+		decoded, err := decode(*value)
+		if err != nil {
+			return "", err
+		}
+		values[key] = &decoded
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+func decodeMultiGetCF(result string, decode func(string) (string, error)) (string, error) {
+	var values []*string
+	if err := json.Unmarshal([]byte(result), &values); err != nil {
+		return result, nil
+	}
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		decoded, err := decode(*value)
+		if err != nil {
+			return "", err
+		}
+		values[i] = &decoded
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+func decodeIteratorEntry(result string, decode func(string) (string, error)) (string, error) {
+	key, value, valid, err := parseIteratorEntry(result)
+	if err != nil || !valid {
+		return result, nil
+	}
+	if value, err = decode(value); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(iteratorEntry{Key: &key, Value: value})
+	return string(data), err
+}
@@ -0,0 +1,63 @@
+package rocksdbclient
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ttlOption renders ttl as the "ttl" option value: whole seconds, rounded up
+// so that sub-second TTLs do not turn into "never expires".
+func ttlOption(ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.New("ttl must be positive")
+	}
+	seconds := (ttl + time.Second - 1) / time.Second
+	return strconv.FormatInt(int64(seconds), 10), nil
+}
+
+// PutWithTTL stores a key-value pair that the server expires after ttl. The
+// TTL travels in the "ttl" option (seconds); expired keys are dropped by the
+// server's TTL compaction filter and are no longer returned by reads.
+func (c *RocksDBClient) PutWithTTL(key, value string, ttl time.Duration, cfName *string) error {
+	seconds, err := ttlOption(ttl)
+	if err != nil {
+		return err
+	}
+	_, err = c.SendRequest(Request{
+		Action:  ActionPut,
+		Key:     &key,
+		Value:   &value,
+		CfName:  cfName,
+		Options: map[string]string{OptionTTL: seconds},
+	})
+	return err
+}
+
+// Touch resets the expiry of keys to ttl from now in a single `touch`
+// request, without rewriting their values. It returns how many of the keys
+// existed and were refreshed.
+func (c *RocksDBClient) Touch(keys []string, ttl time.Duration, cfName *string) (int, error) {
+	seconds, err := ttlOption(ttl)
+	if err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	response, err := c.SendRequest(Request{
+		Action:  ActionTouch,
+		Keys:    keys,
+		CfName:  cfName,
+		Options: map[string]string{OptionTTL: seconds},
+	})
+	if err != nil {
+		return 0, err
+	}
+	touched, err := strconv.Atoi(response.Result)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding touch result: %w", err)
+	}
+	return touched, nil
+}
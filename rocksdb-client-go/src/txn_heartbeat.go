@@ -0,0 +1,121 @@
+package rocksdbclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTxnMaxLifetime is reported by Transaction.HeartbeatErr once a heartbeat
+// stopped because the transaction reached HeartbeatOptions.MaxLifetime.
+var ErrTxnMaxLifetime = errors.New("transaction reached its maximum lifetime")
+
+// HeartbeatOptions configures the background keep-alive of a transaction.
+type HeartbeatOptions struct {
+	// Interval is the time between keep-alives; it must be well below the
+	// server's transaction TTL. Defaults to 10s.
+	Interval time.Duration
+	// MaxLifetime caps how long the heartbeat keeps the transaction alive,
+	// so a leaked handle still expires on the server. Defaults to 10m.
+	MaxLifetime time.Duration
+}
+
+type heartbeat struct {
+	stop chan struct{}
+	done chan struct{}
+	mu   sync.Mutex
+	err  error
+}
+
+// KeepAlive resets the server-side TTL of the transaction with the
+// `transaction_keepalive` action.
+func (t *Transaction) KeepAlive() error {
+	_, err := t.send(Request{Action: ActionTransactionKeepAlive})
+	return err
+}
+
+// StartHeartbeat keeps the transaction alive in the background until it is
+// committed or rolled back, ctx ends, opts.MaxLifetime elapses or a
+// keep-alive fails; HeartbeatErr reports why it stopped. A transaction has at
+// most one heartbeat, so later calls have no effect. WithTransaction starts
+// one for every transaction when the client was created with
+// WithTransactionHeartbeat.
+func (t *Transaction) StartHeartbeat(ctx context.Context, opts HeartbeatOptions) {
+	if t.heartbeat != nil || t.done {
+		return
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.MaxLifetime <= 0 {
+		opts.MaxLifetime = 10 * time.Minute
+	}
+	hb := &heartbeat{stop: make(chan struct{}), done: make(chan struct{})}
+	t.heartbeat = hb
+
+	// The goroutine does not use t.send, which reads fields owned by the
+	// caller's goroutine.
+	c, id := t.c, t.id
+	go func() {
+		defer close(hb.done)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		lifetime := time.NewTimer(opts.MaxLifetime)
+		defer lifetime.Stop()
+		for {
+			var err error
+			select {
+			case <-ticker.C:
+				txn := true
+				_, err = c.SendRequest(Request{
+					Action:  ActionTransactionKeepAlive,
+					Txn:     &txn,
+					TxnID:   &id,
+					Options: map[string]string{},
+				})
+				if err == nil {
+					continue
+				}
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-lifetime.C:
+				err = ErrTxnMaxLifetime
+			case <-hb.stop:
+				return
+			}
+			hb.mu.Lock()
+			hb.err = err
+			hb.mu.Unlock()
+			return
+		}
+	}()
+}
+
+// StopHeartbeat stops a heartbeat started with StartHeartbeat and waits for
+// it to exit. Commit and Rollback call it before finishing the transaction.
+func (t *Transaction) StopHeartbeat() {
+	hb := t.heartbeat
+	if hb == nil {
+		return
+	}
+	select {
+	case <-hb.stop:
+	default:
+		close(hb.stop)
+	}
+	<-hb.done
+}
+
+// HeartbeatErr returns the reason the heartbeat stopped on its own: a failed
+// keep-alive, the context error or ErrTxnMaxLifetime. It returns nil while
+// the heartbeat runs, after StopHeartbeat and if none was started.
+func (t *Transaction) HeartbeatErr() error {
+	hb := t.heartbeat
+	if hb == nil {
+		return nil
+	}
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	return hb.err
+}
@@ -0,0 +1,30 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GetAs reads key and unmarshals its JSON value into a T.
+func GetAs[T any](c *RocksDBClient, key string, cfName *string) (T, error) {
+	var value T
+	response, err := c.Get(&key, cfName, nil, nil)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal([]byte(response.Result), &value); err != nil {
+		return value, fmt.Errorf("error decoding value of %q: %w", key, err)
+	}
+	return value, nil
+}
+
+// PutAs marshals value to JSON and stores it under key.
+func PutAs[T any](c *RocksDBClient, key string, value T, cfName *string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error encoding value of %q: %w", key, err)
+	}
+	encoded := string(data)
+	_, err = c.Put(&key, &encoded, cfName, nil)
+	return err
+}
@@ -0,0 +1,53 @@
+package rocksdbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotUnique is returned by EnsureUnique when the value is already claimed
+// by another primary key.
+var ErrNotUnique = errors.New("value is not unique")
+
+// UniqueIndexKey is the key under which EnsureUnique records that value of
+// field is taken: "<field>:<value>".
+func UniqueIndexKey(field, value string) string {
+	return field + ":" + value
+}
+
+// EnsureUnique claims value of field for primaryKey in the index column
+// family indexCF. The index entry is read with GetForUpdate, so two
+// transactions claiming the same value conflict instead of both succeeding.
+// Claiming a value already owned by primaryKey is a no-op; a value owned by
+// another key fails with ErrNotUnique. Write the primary record in the same
+// transaction so the claim and the record commit together:
+//
+//	err := client.WithTransaction(ctx, func(txn *rocksdbclient.Transaction) error {
+//		if err := txn.EnsureUnique("users_by_email", "email", email, userID); err != nil {
+//			return err
+//		}
+//		return txn.Put(userID, record, nil)
+//	})
+func (t *Transaction) EnsureUnique(indexCF, field, value, primaryKey string) error {
+	key := UniqueIndexKey(field, value)
+	owner, err := t.GetForUpdate(key, &indexCF)
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		return t.Put(key, primaryKey, &indexCF)
+	case err != nil:
+		return err
+	case owner != primaryKey:
+		return fmt.Errorf("%w: %s %q is taken by %q", ErrNotUnique, field, value, owner)
+	}
+	return nil
+}
+
+// EnsureUnique claims value of field for primaryKey in its own transaction,
+// retried on conflicts like WithTransaction. See Transaction.EnsureUnique to
+// claim a value together with other writes.
+func (c *RocksDBClient) EnsureUnique(indexCF, field, value, primaryKey string) error {
+	return c.WithTransaction(context.Background(), func(txn *Transaction) error {
+		return txn.EnsureUnique(indexCF, field, value, primaryKey)
+	})
+}
@@ -0,0 +1,131 @@
+package rocksdbclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrValidation is matched by every error returned when a Validator rejects
+// a write.
+var ErrValidation = errors.New("value rejected by validator")
+
+// Validator checks a value before it is written. A non-nil error rejects the
+// write; it should describe what is wrong with the value.
+type Validator interface {
+	Validate(key, value string) error
+}
+
+// ValidatorFunc adapts a function to a Validator.
+type ValidatorFunc func(key, value string) error
+
+func (f ValidatorFunc) Validate(key, value string) error {
+	return f(key, value)
+}
+
+// ValidationRule applies a Validator to the keys of one column family that
+// start with a prefix. Empty CF and Prefix match every column family and
+// every key; "default" matches writes that do not name a column family.
+type ValidationRule struct {
+	CF        string
+	Prefix    string
+	Validator Validator
+}
+
+func (r ValidationRule) matches(cf, key string) bool {
+	return (r.CF == "" || r.CF == cf) && strings.HasPrefix(key, r.Prefix)
+}
+
+// ValidationError is returned when a write is rejected on the client. It
+// matches ErrValidation and the validator's own error.
+type ValidationError struct {
+	Action string
+	Key    string
+	CF     string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %q in column family %s rejected: %v", e.Action, e.Key, e.CF, e.Err)
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return []error{ErrValidation, e.Err}
+}
+
+// UseValidators installs an interceptor that runs the validators of every
+// matching rule before a value is sent, so malformed values are rejected
+// before they reach a shared dataset. All rules matching a key are applied in
+// order and the first rejection fails the request.
+//
+// Values of put, put_if_absent, merge, compare_and_swap, write_batch_put,
+// write_batch_merge and the put and merge operations of batch_write are
+// validated; a rejected operation fails the whole batch. Merge operands are
+// validated as sent, not the merged result. Install validators before
+// UseValueTransformers so they see plain values.
+func (c *RocksDBClient) UseValidators(rules ...ValidationRule) {
+	rules = append([]ValidationRule(nil), rules...)
+	validate := func(action string, cfName *string, key string, value *string) error {
+		if value == nil {
+			return nil
+		}
+		cf := "default"
+		if cfName != nil {
+			cf = *cfName
+		}
+		for _, rule := range rules {
+			if !rule.matches(cf, key) {
+				continue
+			}
+			if err := rule.Validator.Validate(key, *value); err != nil {
+				return &ValidationError{Action: action, Key: key, CF: cf, Err: err}
+			}
+		}
+		return nil
+	}
+
+	c.Use(func(request Request, next Handler) (*Response, error) {
+		switch request.Action {
+		case ActionPut, ActionPutIfAbsent, ActionMerge, ActionCompareAndSwap,
+			ActionWriteBatchPut, ActionWriteBatchMerge:
+			if request.Key != nil {
+				if err := validate(request.Action, request.CfName, *request.Key, request.Value); err != nil {
+					return nil, err
+				}
+			}
+		case ActionBatchWrite:
+			for _, op := range request.Operations {
+				if op.Type != OpPut && op.Type != OpMerge {
+					continue
+				}
+				cfName := op.CfName
+				if cfName == nil {
+					cfName = request.CfName
+				}
+				if err := validate(request.Action, cfName, op.Key, op.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return next(request)
+	})
+}
+
+// ValidJSON is a Validator that accepts only well-formed JSON values.
+var ValidJSON Validator = ValidatorFunc(func(key, value string) error {
+	if !json.Valid([]byte(value)) {
+		return errors.New("value is not valid JSON")
+	}
+	return nil
+})
+
+// MaxValueSize returns a Validator that rejects values longer than n bytes.
+func MaxValueSize(n int) Validator {
+	return ValidatorFunc(func(key, value string) error {
+		if len(value) > n {
+			return fmt.Errorf("value is %d bytes, limit is %d", len(value), n)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,149 @@
+package rocksdbclient
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WireEncoding is the encoding of requests and responses on a connection.
+type WireEncoding string
+
+const (
+	// WireJSON is the newline-delimited JSON every server speaks.
+	WireJSON WireEncoding = "json"
+	// WireMsgpack sends MessagePack messages, see MsgpackCodec, each
+	// preceded by its length as a 4-byte big-endian integer.
+	WireMsgpack WireEncoding = "msgpack"
+)
+
+// maxFrameSize bounds the length of a single length-prefixed message, so a
+// corrupt prefix cannot make the client allocate gigabytes.
+const maxFrameSize = 1 << 30
+
+// framingLength is the framing option value of length-prefixed JSON.
+const framingLength = "length"
+
+// WithWireEncoding asks the server for encoding in the hello handshake of
+// every new connection. Binary encodings save the JSON encoding cost and
+// send binary values without escaping. Servers that do not support the
+// encoding, or the handshake, keep talking JSON, so the option is safe to
+// set unconditionally. SetJSONCodec does not apply to other encodings.
+func WithWireEncoding(encoding WireEncoding) Option {
+	return func(c *RocksDBClient) {
+		c.encoding = encoding
+	}
+}
+
+// WithFraming asks the server in the hello handshake to switch JSON
+// connections to length-prefixed framing: every message is preceded by its
+// length as a 4-byte big-endian integer instead of being terminated by a
+// newline. A framed stream cannot be desynchronized by a value that contains
+// a newline, e.g. a raw value from a misbehaving proxy, and responses are
+// read into buffers allocated at their final size. Servers that do not
+// support framing keep newline delimiting. Binary wire encodings are always
+// framed.
+func WithFraming() Option {
+	return func(c *RocksDBClient) {
+		c.framing = true
+	}
+}
+
+// Framed reports whether the current connection uses length-prefixed
+// framing.
+func (c *RocksDBClient) Framed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wire.framed()
+}
+
+// WireEncoding returns the encoding negotiated for the current connection,
+// WireJSON before the client connected.
+func (c *RocksDBClient) WireEncoding() WireEncoding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wire.name()
+}
+
+// wireFormat is the format negotiated for one connection. The zero value is
+// newline-delimited JSON, which every connection starts with.
+type wireFormat struct {
+	encoding WireEncoding
+	// lengthPrefixed frames JSON messages like binary ones.
+	lengthPrefixed bool
+}
+
+func (w wireFormat) name() WireEncoding {
+	if w.encoding == "" {
+		return WireJSON
+	}
+	return w.encoding
+}
+
+func (w wireFormat) framed() bool {
+	return w.lengthPrefixed || w.encoding == WireMsgpack
+}
+
+// encode returns request as it is written to the connection, including the
+// delimiter or length prefix.
+func (w wireFormat) encode(codec JSONCodec, request Request) ([]byte, error) {
+	if w.encoding == WireMsgpack {
+		return frame(appendMsgpackRequest(make([]byte, 4, 128), &request))
+	}
+	data, err := codec.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+	if w.lengthPrefixed {
+		return frame(append(make([]byte, 4, 4+len(data)), data...))
+	}
+	return append(data, '\n'), nil
+}
+
+// frame fills in the length prefix reserved in the first 4 bytes of buf.
+func frame(buf []byte) ([]byte, error) {
+	n := len(buf) - 4
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("error encoding request: message of %d bytes exceeds the frame limit", n)
+	}
+	binary.BigEndian.PutUint32(buf, uint32(n))
+	return buf, nil
+}
+
+// read reads one message and returns its payload and its size on the wire.
+func (w wireFormat) read(reader *bufio.Reader) ([]byte, int, error) {
+	if !w.framed() {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading response: %w", err)
+		}
+		return line, len(line), nil
+	}
+	var prefix [4]byte
+	if _, err := io.ReadFull(reader, prefix[:]); err != nil {
+		return nil, 0, fmt.Errorf("error reading response: %w", err)
+	}
+	n := binary.BigEndian.Uint32(prefix[:])
+	if n > maxFrameSize {
+		return nil, 0, fmt.Errorf("error reading response: frame of %d bytes exceeds the limit", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, 0, fmt.Errorf("error reading response: %w", err)
+	}
+	return payload, len(payload) + 4, nil
+}
+
+func (w wireFormat) decode(codec JSONCodec, payload []byte, response *Response) error {
+	var err error
+	if w.encoding == WireMsgpack {
+		err = MsgpackCodec{}.Unmarshal(payload, response)
+	} else {
+		err = codec.Unmarshal(payload, response)
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
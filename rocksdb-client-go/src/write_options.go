@@ -0,0 +1,62 @@
+package rocksdbclient
+
+// WriteOptions mirror the RocksDB WriteOptions of a single write. The zero
+// value uses the server defaults.
+type WriteOptions struct {
+	// Sync fsyncs the write ahead log before the write is acknowledged.
+	Sync bool
+	// DisableWAL skips the write ahead log. Writes are lost on a crash until
+	// the memtable is flushed, which is acceptable for bulk loads that can be
+	// rerun. RocksDB rejects it together with Sync.
+	DisableWAL bool
+	// LowPri marks the write as low priority, so it is throttled first when
+	// compaction falls behind.
+	LowPri bool
+}
+
+func (wo WriteOptions) apply(options map[string]string) {
+	if wo.Sync {
+		options[OptionSync] = "true"
+	}
+	if wo.DisableWAL {
+		options[OptionDisableWAL] = "true"
+	}
+	if wo.LowPri {
+		options[OptionLowPri] = "true"
+	}
+}
+
+// WithWriteOptions sends wo with a write, e.g.
+//
+//	client.PutWith(key, value, nil, rocksdbclient.WithWriteOptions(rocksdbclient.WriteOptions{DisableWAL: true}))
+func WithWriteOptions(wo WriteOptions) CallOption {
+	return func(request *Request) {
+		wo.apply(request.Options)
+	}
+}
+
+// MergeWith merges value into key like Merge, applying call options such as
+// WithWriteOptions.
+func (c *RocksDBClient) MergeWith(key, value string, cfName *string, opts ...CallOption) (*WriteResult, error) {
+	return c.sendWrite(Request{Action: ActionMerge, Key: &key, Value: &value, CfName: cfName}, opts)
+}
+
+// BatchWriteWith is BatchWrite with call options, which apply to the batch
+// as a whole.
+func (c *RocksDBClient) BatchWriteWith(ops []Operation, opts ...CallOption) (*Response, error) {
+	if len(ops) == 0 {
+		return &Response{Success: true}, nil
+	}
+	for _, op := range ops {
+		if err := op.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	request := Request{
+		Action:     ActionBatchWrite,
+		Operations: ops,
+	}
+	applyCallOptions(&request, opts)
+	return c.SendRequest(request)
+}
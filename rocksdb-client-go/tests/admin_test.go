@@ -0,0 +1,57 @@
+package rocksdbclient_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestGetAdminLog(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`[{"timestamp":"2024-05-01T10:00:00Z","action":"backup","actor":"ops","target":"3","success":true},` +
+			`{"timestamp":"2024-05-01T09:00:00Z","action":"drop_column_family","actor":"10.0.0.5:51234","target":"tmp","success":false,"details":"Column family not found"}]`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	since := time.Date(2024, 5, 1, 11, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	entries, err := client.GetAdminLog(rocksdbclient.AdminLogQuery{Since: since, Action: "backup", Limit: 2})
+	if err != nil {
+		t.Fatalf("failed to get admin log: %v", err)
+	}
+	req := server.received()[0]
+	want := map[string]string{"since": "2024-05-01T09:00:00Z", "action": "backup", "limit": "2"}
+	if req.Action != "get_admin_log" || !reflect.DeepEqual(req.Options, want) {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Action != "backup" || e.Actor != "ops" || e.Target != "3" || !e.Success || !e.Timestamp.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	if e := entries[1]; e.Success || e.Details != "Column family not found" {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	if _, err := client.GetAdminLog(rocksdbclient.AdminLogQuery{}); err != nil {
+		t.Fatalf("failed to get admin log: %v", err)
+	}
+	if req := server.received()[1]; len(req.Options) != 0 {
+		t.Fatalf("expected zero query fields to be omitted, got %v", req.Options)
+	}
+}
+
+func TestGetAdminLogInvalidResult(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("not json")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if _, err := client.GetAdminLog(rocksdbclient.AdminLogQuery{}); err == nil {
+		t.Fatal("expected a decoding error")
+	}
+}
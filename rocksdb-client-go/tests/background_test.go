@@ -0,0 +1,34 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestPauseBackgroundWork(t *testing.T) {
+	paused := 0
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "pause_background_work":
+			paused++
+		case "continue_background_work":
+			paused--
+		default:
+			return fail("Unknown action")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.PauseBackgroundWork(); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if err := client.ContinueBackgroundWork(); err != nil {
+		t.Fatalf("continue failed: %v", err)
+	}
+	if paused != 0 || len(server.received()) != 2 {
+		t.Fatalf("expected a balanced pause and continue, got %d", paused)
+	}
+}
@@ -0,0 +1,150 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestListBackups(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action != "get_backup_info" {
+			return fail("Unknown action")
+		}
+		return ok(`[{"timestamp":1700000000,"backup_id":1,"size":2048,"num_files":4},{"timestamp":1700003600,"backup_id":2,"size":4096,"num_files":6}]`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	backups, err := client.ListBackups()
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d", len(backups))
+	}
+	want := rocksdbclient.BackupInfo{ID: 2, Timestamp: time.Unix(1700003600, 0), Size: 4096, NumFiles: 6}
+	if backups[1] != want {
+		t.Fatalf("expected %+v, got %+v", want, backups[1])
+	}
+}
+
+func TestCreateCheckpoint(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.CreateCheckpoint("/var/lib/rocksdb/seed-1"); err != nil {
+		t.Fatalf("failed to create checkpoint: %v", err)
+	}
+	if err := client.CreateCheckpoint(""); err == nil {
+		t.Fatal("expected an error for an empty path")
+	}
+	requests := server.received()
+	if len(requests) != 1 || requests[0].Action != "create_checkpoint" || requests[0].Options["path"] != "/var/lib/rocksdb/seed-1" {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+}
+
+func TestBackupWith(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`{"backup_id":5,"size":8192,"num_files":12}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	result, err := client.BackupWith(rocksdbclient.BackupOptions{Dir: "/mnt/backups", FlushBeforeBackup: true, Incremental: true})
+	if err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if *result != (rocksdbclient.BackupResult{ID: 5, Size: 8192, NumFiles: 12}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	req := server.received()[0]
+	if req.Action != "backup" || req.Options["backup_dir"] != "/mnt/backups" ||
+		req.Options["flush_before_backup"] != "true" || req.Options["incremental"] != "true" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
+
+func TestBackupRetention(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.PurgeOldBackups(3); err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+	if err := client.PurgeOldBackups(0); err == nil {
+		t.Fatal("expected keepN 0 to be rejected")
+	}
+	if err := client.DeleteBackup(7); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if requests[0].Action != "purge_old_backups" || requests[0].Options["num_backups_to_keep"] != "3" {
+		t.Fatalf("unexpected purge request %+v", requests[0])
+	}
+	if requests[1].Action != "delete_backup" || requests[1].Options["backup_id"] != "7" {
+		t.Fatalf("unexpected delete request %+v", requests[1])
+	}
+}
+
+func TestRestoreTo(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.RestoreTo(4, rocksdbclient.RestoreOptions{DBDir: "/staging/db", WALDir: "/staging/wal"}); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if err := client.RestoreLatestTo(rocksdbclient.RestoreOptions{DBDir: "/staging/db"}); err != nil {
+		t.Fatalf("restore latest failed: %v", err)
+	}
+
+	requests := server.received()
+	if requests[0].Action != "restore" || requests[0].Options["backup_id"] != "4" ||
+		requests[0].Options["db_dir"] != "/staging/db" || requests[0].Options["wal_dir"] != "/staging/wal" {
+		t.Fatalf("unexpected restore request %+v", requests[0])
+	}
+	if requests[1].Action != "restore_latest" || requests[1].Options["db_dir"] != "/staging/db" {
+		t.Fatalf("unexpected restore latest request %+v", requests[1])
+	}
+	if _, found := requests[1].Options["wal_dir"]; found {
+		t.Fatal("empty wal_dir must not be sent")
+	}
+}
+
+func TestVerifyBackup(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Options["backup_id"] == "2" {
+			return fail("Corruption: checksum mismatch in 000012.sst")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.VerifyBackup(1); err != nil {
+		t.Fatalf("expected backup 1 to verify, got %v", err)
+	}
+	if err := client.VerifyBackup(2); !errors.Is(err, rocksdbclient.ErrCorruption) {
+		t.Fatalf("expected ErrCorruption, got %v", err)
+	}
+	if req := server.received()[0]; req.Action != rocksdbclient.ActionVerifyBackup {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
@@ -0,0 +1,69 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestBatchWrite(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	ops := []rocksdbclient.Operation{
+		{Type: rocksdbclient.OpPut, Key: "a", Value: stringPtr("1")},
+		{Type: rocksdbclient.OpMerge, Key: "b", Value: stringPtr(`[]`), CfName: stringPtr("cf")},
+		{Type: rocksdbclient.OpDelete, Key: "c"},
+	}
+	if _, err := client.BatchWrite(ops); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 1 || received[0].Action != "batch_write" {
+		t.Fatalf("expected one batch_write request, got %+v", received)
+	}
+	if len(received[0].Operations) != 3 || received[0].Operations[1].Type != rocksdbclient.OpMerge {
+		t.Fatalf("unexpected operations %+v", received[0].Operations)
+	}
+
+	_, err := client.BatchWrite([]rocksdbclient.Operation{{Type: rocksdbclient.OpPut, Key: "a"}})
+	if err == nil {
+		t.Fatalf("expected validation error for put without value")
+	}
+	if len(server.received()) != 1 {
+		t.Fatalf("invalid batch must not be sent")
+	}
+}
+
+func TestWriteBatchAutoFlush(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	batch := client.NewWriteBatch(rocksdbclient.WriteBatchOptions{MaxOps: 3})
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if err := batch.Put(k, "v", nil); err != nil {
+			t.Fatalf("failed to buffer put: %v", err)
+		}
+	}
+	if err := batch.Delete("e", nil); err != nil {
+		t.Fatalf("failed to buffer delete: %v", err)
+	}
+	if len(server.received()) != 1 || batch.Len() != 2 {
+		t.Fatalf("expected one automatic flush and 2 buffered ops, got %d requests and %d ops", len(server.received()), batch.Len())
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	received := server.received()
+	if len(received) != 2 || len(received[1].Operations) != 2 || batch.Len() != 0 {
+		t.Fatalf("unexpected flushes %+v", received)
+	}
+}
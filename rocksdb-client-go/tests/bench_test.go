@@ -0,0 +1,44 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// codecs lists the codecs compared by the SendRequest benchmarks. Add
+// jsoniter.ConfigCompatibleWithStandardLibrary or sonic.ConfigStd here
+// locally to measure them against encoding/json.
+var codecs = map[string]rocksdbclient.JSONCodec{
+	"encoding/json": stdCodec{},
+}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (stdCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+func BenchmarkSendRequest(b *testing.B) {
+	value := strings.Repeat(`{"field":"value"},`, 64)
+	for name, codec := range codecs {
+		b.Run(name, func(b *testing.B) {
+			server := newFakeServer(b, func(req rocksdbclient.Request) rocksdbclient.Response {
+				return ok(value)
+			})
+			client := server.client()
+			defer client.Close()
+			client.SetJSONCodec(codec)
+
+			key := "bench_key"
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.Put(&key, &value, nil, nil); err != nil {
+					b.Fatalf("request failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestNegativeCacheShortCircuitsMisses(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["present"] = "v"
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	nc := rocksdbclient.NewNegativeCache(client, rocksdbclient.NegativeCacheOptions{ExpectedKeys: 100})
+	if err := nc.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	before := len(server.received())
+
+	_, err := client.Get(stringPtr("absent"), nil, nil, nil)
+	if !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	response, err := client.Get(stringPtr("absent"), nil, stringPtr("fallback"), nil)
+	if err != nil || response.Result != "fallback" {
+		t.Fatalf("expected default value, got %v, %v", response, err)
+	}
+	if len(server.received()) != before {
+		t.Fatalf("misses must not reach the server")
+	}
+
+	if _, err := client.Put(stringPtr("new"), stringPtr("v"), nil, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	response, err = client.Get(stringPtr("new"), nil, nil, nil)
+	if err != nil || response.Result != "v" {
+		t.Fatalf("expected written key to be readable, got %v, %v", response, err)
+	}
+	response, err = client.Get(stringPtr("present"), nil, nil, nil)
+	if err != nil || response.Result != "v" {
+		t.Fatalf("expected scanned key to be readable, got %v, %v", response, err)
+	}
+}
+
+func TestNegativeCacheConditionalWrites(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	nc := rocksdbclient.NewNegativeCache(client, rocksdbclient.NegativeCacheOptions{ExpectedKeys: 100})
+	if err := nc.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if written, err := client.PutIfAbsent("created", "v", nil); err != nil || !written {
+		t.Fatalf("failed to put if absent: %v, %v", written, err)
+	}
+	if swapped, _, err := client.CAS("swapped", nil, "v", nil); err != nil || !swapped {
+		t.Fatalf("failed to compare and swap: %v, %v", swapped, err)
+	}
+	for _, key := range []string{"created", "swapped"} {
+		response, err := client.Get(stringPtr(key), nil, nil, nil)
+		if err != nil || response.Result != "v" {
+			t.Fatalf("expected %s to be readable, got %v, %v", key, response, err)
+		}
+	}
+}
+
+func TestNegativeCacheKeylessWrites(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "restore" {
+			kv.mu.Lock()
+			kv.data["restored"] = "v"
+			kv.mu.Unlock()
+			return ok("")
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+
+	nc := rocksdbclient.NewNegativeCache(client, rocksdbclient.NegativeCacheOptions{ExpectedKeys: 100})
+	if err := nc.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if _, err := client.Restore("1"); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+	response, err := client.Get(stringPtr("restored"), nil, nil, nil)
+	if err != nil || response.Result != "v" {
+		t.Fatalf("expected restored key to be readable, got %v, %v", response, err)
+	}
+
+	if err := nc.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	before := len(server.received())
+	if _, err := client.Get(stringPtr("absent"), nil, nil, nil); !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if len(server.received()) != before {
+		t.Fatalf("expected the refresh to enable the cache again")
+	}
+}
+
+func TestNegativeCacheDefaultCF(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := rocksdbclient.NewClient(server.listener.Addr().String(), rocksdbclient.WithDefaultCF("users"))
+	defer client.Close()
+
+	nc := rocksdbclient.NewNegativeCache(client, rocksdbclient.NegativeCacheOptions{ExpectedKeys: 100})
+	if err := nc.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	before := len(server.received())
+	if _, err := client.Get(stringPtr("absent"), nil, nil, nil); !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if len(server.received()) != before {
+		t.Fatalf("expected misses in the default column family not to reach the server")
+	}
+
+	if _, err := client.Get(stringPtr("absent"), stringPtr("other"), nil, nil); !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if len(server.received()) != before+1 {
+		t.Fatalf("expected gets in other column families to reach the server")
+	}
+}
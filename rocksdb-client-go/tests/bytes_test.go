@@ -0,0 +1,32 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestBytesRoundTrip(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	key := []byte{0x00, 0xff, '\n', ':'}
+	value := []byte{0xde, 0xad, 0xbe, 0xef, 0x00, '\n'}
+	if err := client.PutBytes(key, value, nil); err != nil {
+		t.Fatalf("failed to put bytes: %v", err)
+	}
+	got, err := client.GetBytes(key, nil)
+	if err != nil || !bytes.Equal(got, value) {
+		t.Fatalf("unexpected value %x, %v", got, err)
+	}
+	if err := client.DeleteBytes(key, nil); err != nil {
+		t.Fatalf("failed to delete bytes: %v", err)
+	}
+	if _, err := client.GetBytes(key, nil); !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
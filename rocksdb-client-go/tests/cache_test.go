@@ -0,0 +1,147 @@
+package rocksdbclient_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// mapRistretto mimics the method set of *ristretto.Cache[string, string].
+type mapRistretto struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (m *mapRistretto) Get(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, found := m.data[key]
+	return v, found
+}
+
+func (m *mapRistretto) Set(key string, value string, cost int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	return true
+}
+
+func (m *mapRistretto) Del(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+}
+
+func (m *mapRistretto) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = map[string]string{}
+}
+
+func TestReadThroughWriteThroughCache(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["a"] = "1"
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	store := &mapRistretto{data: map[string]string{}}
+	client.UseCache(rocksdbclient.RistrettoCache(store))
+
+	for i := 0; i < 3; i++ {
+		response, err := client.Get(stringPtr("a"), nil, nil, nil)
+		if err != nil || response.Result != "1" {
+			t.Fatalf("unexpected get result %v, %v", response, err)
+		}
+	}
+	if got := len(server.received()); got != 1 {
+		t.Fatalf("expected a single server read, got %d", got)
+	}
+
+	if _, err := client.Put(stringPtr("b"), stringPtr("2"), nil, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if v, found := store.Get("b"); !found || v != "2" {
+		t.Fatalf("expected write-through of b, got %q %v", v, found)
+	}
+
+	if _, err := client.Delete(stringPtr("a"), nil, nil); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, found := store.Get("a"); found {
+		t.Fatalf("expected a to be invalidated")
+	}
+}
+
+func TestCacheInvalidatesOtherWrites(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["a"] = "1"
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("t1")
+		case "commit_transaction":
+			return ok("")
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+	store := &mapRistretto{data: map[string]string{}}
+	client.UseCache(rocksdbclient.RistrettoCache(store))
+
+	get := func(want string) {
+		t.Helper()
+		response, err := client.Get(stringPtr("a"), nil, nil, nil)
+		if err != nil || response.Result != want {
+			t.Fatalf("expected %q, got %v, %v", want, response, err)
+		}
+	}
+	get("1")
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if err := txn.Put("a", "2", nil); err != nil {
+		t.Fatalf("failed to put in transaction: %v", err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	get("2")
+
+	if swapped, _, err := client.CAS("a", stringPtr("2"), "3", nil); err != nil || !swapped {
+		t.Fatalf("failed to compare and swap: %v, %v", swapped, err)
+	}
+	get("3")
+}
+
+func TestCacheExpiresTTLValues(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	client.UseCache(rocksdbclient.RistrettoCache(&mapRistretto{data: map[string]string{}}))
+
+	if err := client.PutWithTTL("a", "1", time.Second, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if _, err := client.Get(stringPtr("a"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got := len(server.received()); got != 1 {
+		t.Fatalf("expected the get to be served from the cache, got %d requests", got)
+	}
+
+	// The fake server does not expire keys, so the read shows up there.
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := client.Get(stringPtr("a"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got := len(server.received()); got != 2 {
+		t.Fatalf("expected the expired value to be read from the server, got %d requests", got)
+	}
+}
@@ -0,0 +1,72 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestWithReturnOld(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		old, found := kv.data[*req.Key]
+		response := kv.handle(req)
+		if !response.Success || req.Options["return_old"] != "true" {
+			return response
+		}
+		result := map[string]*string{"old": nil}
+		if found {
+			result["old"] = &old
+		}
+		data, _ := json.Marshal(result)
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	result, err := client.PutWith("k", "v1", nil, rocksdbclient.WithReturnOld())
+	if err != nil || result.Old != nil {
+		t.Fatalf("expected no old value, got %+v, %v", result, err)
+	}
+	result, err = client.PutWith("k", "v2", nil, rocksdbclient.WithReturnOld())
+	if err != nil || result.Old == nil || *result.Old != "v1" {
+		t.Fatalf("expected old value v1, got %+v, %v", result, err)
+	}
+	result, err = client.DeleteWith("k", nil, rocksdbclient.WithReturnOld())
+	if err != nil || result.Old == nil || *result.Old != "v2" {
+		t.Fatalf("expected old value v2, got %+v, %v", result, err)
+	}
+	if result, err = client.PutWith("k", "v3", nil); err != nil || result.Old != nil {
+		t.Fatalf("expected plain put, got %+v, %v", result, err)
+	}
+}
+
+func TestWithIfModifiedSince(t *testing.T) {
+	sequence := uint64(7)
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		since, found := req.Options["if_modified_since"]
+		if !found {
+			return ok("doc")
+		}
+		if since == "7" {
+			return ok(`{"not_modified":true,"sequence":7}`)
+		}
+		data, _ := json.Marshal(map[string]any{"value": "doc", "sequence": sequence})
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	result, err := client.GetWith("k", nil, rocksdbclient.WithIfModifiedSince(0))
+	if err != nil || result.NotModified || result.Value != "doc" || result.Sequence != 7 {
+		t.Fatalf("expected full read, got %+v, %v", result, err)
+	}
+	result, err = client.GetWith("k", nil, rocksdbclient.WithIfModifiedSince(result.Sequence))
+	if err != nil || !result.NotModified || result.Value != "" {
+		t.Fatalf("expected not modified, got %+v, %v", result, err)
+	}
+	if result, err = client.GetWith("k", nil); err != nil || result.Value != "doc" {
+		t.Fatalf("expected plain read, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,49 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCallerLabel(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("value")
+	})
+	client := server.client()
+	defer client.Close()
+
+	client.SetCaller("billing")
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	options := map[string]string{"caller": "reports"}
+	if _, err := client.SendRequest(rocksdbclient.Request{Action: "get", Key: stringPtr("k"), Options: options}); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	client.SetCaller("")
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+
+	received := server.received()
+	if received[0].Options["caller"] != "billing" || received[1].Options["caller"] != "reports" {
+		t.Fatalf("unexpected caller options %v, %v", received[0].Options, received[1].Options)
+	}
+	if _, found := received[2].Options["caller"]; found {
+		t.Fatalf("expected untagged request, got %v", received[2].Options)
+	}
+
+	usage := client.Usage()
+	if len(usage) != 3 {
+		t.Fatalf("expected usage for 3 labels, got %v", usage)
+	}
+	billing := usage["billing"]
+	if billing.Requests != 1 || billing.RequestBytes == 0 || billing.ResponseBytes == 0 {
+		t.Fatalf("unexpected billing usage %+v", billing)
+	}
+	client.ResetUsage()
+	if len(client.Usage()) != 0 {
+		t.Fatal("expected usage to be cleared")
+	}
+}
@@ -0,0 +1,53 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestMintCapability(t *testing.T) {
+	token, err := rocksdbclient.MintCapability("parent-secret", rocksdbclient.CapabilityClaims{
+		Prefix:         "orders:",
+		ColumnFamilies: []string{"orders"},
+		ReadOnly:       true,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+	if !strings.HasPrefix(token, "cap1.") || strings.Contains(token, "parent-secret") {
+		t.Fatalf("unexpected token %q", token)
+	}
+
+	claims, err := rocksdbclient.VerifyCapability("parent-secret", token)
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if claims.Prefix != "orders:" || !claims.ReadOnly || claims.Parent != rocksdbclient.TokenFingerprint("parent-secret") {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	if _, err := rocksdbclient.VerifyCapability("other", token); !errors.Is(err, rocksdbclient.ErrInvalidCapability) {
+		t.Fatalf("expected ErrInvalidCapability for wrong parent, got %v", err)
+	}
+	tampered := token[:len(token)-2] + "xx"
+	if _, err := rocksdbclient.VerifyCapability("parent-secret", tampered); !errors.Is(err, rocksdbclient.ErrInvalidCapability) {
+		t.Fatalf("expected ErrInvalidCapability for tampered token, got %v", err)
+	}
+}
+
+func TestMintCapabilityExpired(t *testing.T) {
+	token, err := rocksdbclient.MintCapability("parent-secret", rocksdbclient.CapabilityClaims{
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+	if _, err := rocksdbclient.VerifyCapability("parent-secret", token); !errors.Is(err, rocksdbclient.ErrInvalidCapability) {
+		t.Fatalf("expected expired capability to be rejected, got %v", err)
+	}
+}
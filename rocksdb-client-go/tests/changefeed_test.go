@@ -0,0 +1,111 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestLatestSequenceNumber(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get_latest_sequence_number" {
+			return ok("42")
+		}
+		return fail("Unknown action")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if seq, err := client.LatestSequenceNumber(); err != nil || seq != 42 {
+		t.Fatalf("unexpected sequence number %d, %v", seq, err)
+	}
+}
+
+func TestExportSince(t *testing.T) {
+	listener := streamServer(t, func(conn net.Conn, req rocksdbclient.Request) {
+		if req.Action != "get_updates_since" || req.Options["since_seq"] != "10" {
+			writeLine(conn, fail("Unexpected request"))
+			return
+		}
+		writeLine(conn, ok(`{"latest_seq":12}`))
+		a, b := "1", "2"
+		writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpPut, Key: "user:a", Value: &a, Seq: 10})
+		writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpPut, Key: "other", Value: &b, Seq: 11})
+		writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpDelete, Key: "user:b", Seq: 12})
+	})
+	client := rocksdbclient.NewClient(listener.Addr().String())
+	defer client.Close()
+
+	var buf bytes.Buffer
+	result, err := client.ExportSince(context.Background(), &buf, 10, rocksdbclient.ExportOptions{Prefix: "user:", TempDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if result.Count != 2 || result.Seq != 12 {
+		t.Fatalf("unexpected export result %+v", result)
+	}
+	manifest, err := rocksdbclient.VerifyArchive(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if !manifest.Incremental || manifest.FromSeq != 10 || manifest.ToSeq != 12 || manifest.Entries != 2 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+
+	target := newFakeKV()
+	target.data["user:b"] = "old"
+	targetClient := newFakeServer(t, target.handle).client()
+	defer targetClient.Close()
+	if n, err := targetClient.Import(&buf); err != nil || n != 2 {
+		t.Fatalf("import failed: %d, %v", n, err)
+	}
+	if len(target.data) != 1 || target.data["user:a"] != "1" {
+		t.Fatalf("unexpected data after import %v", target.data)
+	}
+}
+
+func TestGetUpdatesSince(t *testing.T) {
+	requests := make(chan rocksdbclient.Request, 4)
+	listener := streamServer(t, func(conn net.Conn, req rocksdbclient.Request) {
+		requests <- req
+		writeLine(conn, ok(`{"latest_seq":6}`))
+		since, _ := strconv.ParseUint(req.Options["since_seq"], 10, 64)
+		value := "v"
+		for seq := since; seq < since+2; seq++ {
+			writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpPut, Key: "k" + strconv.FormatUint(seq, 10), Value: &value, Seq: seq})
+		}
+		// The stream drops after two events; the client resumes after them.
+	})
+	client := rocksdbclient.NewClient(listener.Addr().String(), rocksdbclient.WithRetryInterval(10*time.Millisecond))
+	defer client.Close()
+
+	sub, err := client.GetUpdatesSince(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("failed to tail updates: %v", err)
+	}
+	var seqs []uint64
+	for event := range sub.Events() {
+		seqs = append(seqs, event.Seq)
+		if len(seqs) == 4 {
+			break
+		}
+	}
+	sub.Close()
+	if !slices.Equal(seqs, []uint64{5, 6, 7, 8}) {
+		t.Fatalf("unexpected sequence numbers %v", seqs)
+	}
+
+	first, second := <-requests, <-requests
+	if first.Action != "get_updates_since" || first.Options["follow"] != "true" || first.Options["since_seq"] != "5" {
+		t.Fatalf("unexpected request %+v", first)
+	}
+	if second.Options["since_seq"] != "7" {
+		t.Fatalf("expected resume at seq 7, got %+v", second.Options)
+	}
+}
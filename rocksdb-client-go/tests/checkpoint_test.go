@@ -0,0 +1,100 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestScanFromResumesAfterCheckpoint(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		kv.data[k] = "v" + k
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	path := filepath.Join(t.TempDir(), "scan.json")
+	cp, err := rocksdbclient.LoadScanCheckpoint(path)
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+
+	boom := errors.New("boom")
+	var seen []string
+	err = client.ScanFrom(cp, 10, func(key, value string) error {
+		if key == "c" {
+			return boom
+		}
+		seen = append(seen, key)
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+
+	cp, err = rocksdbclient.LoadScanCheckpoint(path)
+	if err != nil {
+		t.Fatalf("failed to reload checkpoint: %v", err)
+	}
+	if cp.LastKey != "b" || cp.Processed != 2 {
+		t.Fatalf("unexpected checkpoint %+v", cp)
+	}
+
+	err = client.ScanFrom(cp, 1, func(key, value string) error {
+		seen = append(seen, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to resume scan: %v", err)
+	}
+
+	want := []string{"a", "b", "c", "d", "e"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, seen)
+		}
+	}
+	if cp.Processed != 5 {
+		t.Fatalf("expected 5 processed keys, got %d", cp.Processed)
+	}
+}
+
+func TestScanFromRecoversPanic(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a", "b", "c"} {
+		kv.data[k] = "v" + k
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	cp, err := rocksdbclient.LoadScanCheckpoint(filepath.Join(t.TempDir(), "scan.json"))
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	err = client.ScanFrom(cp, 10, func(key, value string) error {
+		if key == "b" {
+			var m map[string]int
+			m[key] = 1
+		}
+		return nil
+	})
+
+	var panicErr *rocksdbclient.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if panicErr.Key != "b" || panicErr.Offset != 1 || len(panicErr.Stack) == 0 {
+		t.Fatalf("unexpected panic error %+v", panicErr)
+	}
+	if cp.LastKey != "a" {
+		t.Fatalf("expected checkpoint at a, got %q", cp.LastKey)
+	}
+}
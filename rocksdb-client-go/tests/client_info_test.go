@@ -0,0 +1,95 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestClientInfoHandshake(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "hello" {
+			return ok(`{"client_id":"c-17"}`)
+		}
+		return ok("v")
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(),
+		rocksdbclient.WithClientInfo("billing", "1.4.2"),
+		rocksdbclient.WithToken("secret"),
+	)
+	defer client.Close()
+
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if id := client.ClientID(); id != "c-17" {
+		t.Fatalf("expected client id c-17, got %q", id)
+	}
+
+	requests := server.received()
+	if len(requests) != 2 || requests[0].Action != "hello" || requests[1].Action != "get" {
+		t.Fatalf("expected hello before get, got %+v", requests)
+	}
+	var info rocksdbclient.ClientInfo
+	if err := json.Unmarshal([]byte(*requests[0].Value), &info); err != nil {
+		t.Fatalf("failed to decode client info: %v", err)
+	}
+	if info.Name != "billing" || info.Version != "1.4.2" || info.Hostname == "" {
+		t.Fatalf("unexpected client info %+v", info)
+	}
+	if requests[0].Token == nil || *requests[0].Token != "secret" {
+		t.Fatal("expected the handshake to be authenticated")
+	}
+}
+
+func TestClientInfoOldServer(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "hello" {
+			return fail("Unknown action")
+		}
+		return ok("v")
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(), rocksdbclient.WithClientInfo("billing", ""))
+	defer client.Close()
+
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("expected servers without hello to be tolerated, got %v", err)
+	}
+	if id := client.ClientID(); id != "" {
+		t.Fatalf("expected no client id, got %q", id)
+	}
+}
+
+func TestListClients(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "list_clients" {
+			return ok(`[{"id":"c-1","name":"billing","version":"1.4.2","hostname":"web-1","addr":"10.0.0.5:51234","connected_at":"2024-05-01T10:00:00Z"}]`)
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	clients, err := client.ListClients()
+	if err != nil {
+		t.Fatalf("list_clients failed: %v", err)
+	}
+	want := rocksdbclient.ConnectedClient{
+		ID:          "c-1",
+		ClientInfo:  rocksdbclient.ClientInfo{Name: "billing", Version: "1.4.2", Hostname: "web-1"},
+		Addr:        "10.0.0.5:51234",
+		ConnectedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if len(clients) != 1 || clients[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, clients)
+	}
+
+	if err := client.DisconnectClient("c-1"); err != nil {
+		t.Fatalf("disconnect_client failed: %v", err)
+	}
+	if req := server.received()[1]; req.Action != "disconnect_client" || req.Options["client_id"] != "c-1" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
@@ -0,0 +1,44 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestColumnFamilyView(t *testing.T) {
+	data := map[string]string{}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		cf := "default"
+		if req.CfName != nil {
+			cf = *req.CfName
+		}
+		switch req.Action {
+		case "put":
+			data[cf+"/"+*req.Key] = *req.Value
+			return ok("")
+		case "get":
+			if v, found := data[cf+"/"+*req.Key]; found {
+				return ok(v)
+			}
+			return fail("Key not found")
+		}
+		return fail("Unknown action")
+	})
+	client := server.client()
+	defer client.Close()
+
+	users := client.CF("users")
+	if err := users.Put("u1", "alice"); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if data["users/u1"] != "alice" {
+		t.Fatalf("expected write to users, got %v", data)
+	}
+	if value, err := users.Get("u1"); err != nil || value != "alice" {
+		t.Fatalf("expected alice, got %q, %v", value, err)
+	}
+	if _, err := client.CF("orders").Get("u1"); err == nil {
+		t.Fatal("expected key to be missing in orders")
+	}
+}
@@ -0,0 +1,188 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestPutIfAbsent(t *testing.T) {
+	var mu sync.Mutex
+	data := map[string]string{}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, found := data[*req.Key]; found {
+			return ok("false")
+		}
+		data[*req.Key] = *req.Value
+		return ok("true")
+	})
+	client := server.client()
+	defer client.Close()
+
+	for i, want := range []bool{true, false} {
+		written, err := client.PutIfAbsent("lock", strconv.Itoa(i), nil)
+		if err != nil {
+			t.Fatalf("failed to put: %v", err)
+		}
+		if written != want {
+			t.Fatalf("attempt %d: expected written=%v", i, want)
+		}
+	}
+	if data["lock"] != "0" {
+		t.Fatalf("expected first value to be kept, got %q", data["lock"])
+	}
+	if action := server.received()[0].Action; action != "put_if_absent" {
+		t.Fatalf("unexpected action %q", action)
+	}
+}
+
+func TestCAS(t *testing.T) {
+	var mu sync.Mutex
+	data := map[string]string{"counter": "1"}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		current, found := data[*req.Key]
+		expected, expectFound := req.Options["expected"]
+		if found != expectFound || current != expected {
+			if !found {
+				return ok(`{"swapped":false,"current":null}`)
+			}
+			return ok(`{"swapped":false,"current":"` + current + `"}`)
+		}
+		data[*req.Key] = *req.Value
+		return ok(`{"swapped":true}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	swapped, current, err := client.CAS("counter", stringPtr("0"), "2", nil)
+	if err != nil || swapped || current == nil || *current != "1" {
+		t.Fatalf("expected mismatch with current 1, got %v, %v, %v", swapped, current, err)
+	}
+	swapped, current, err = client.CAS("counter", current, "2", nil)
+	if err != nil || !swapped || *current != "2" {
+		t.Fatalf("expected swap, got %v, %v, %v", swapped, current, err)
+	}
+	swapped, current, err = client.CAS("lock", nil, "owner", nil)
+	if err != nil || !swapped || data["lock"] != "owner" {
+		t.Fatalf("expected swap on missing key, got %v, %v, %v", swapped, current, err)
+	}
+	swapped, current, err = client.CAS("missing", stringPtr("x"), "y", nil)
+	if err != nil || swapped || current != nil {
+		t.Fatalf("expected mismatch on missing key, got %v, %v, %v", swapped, current, err)
+	}
+}
+
+func TestGetOrCreateMany(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["a"] = "existing"
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "multi_get":
+			result := map[string]*string{}
+			for _, k := range req.Keys {
+				if v, found := kv.data[k]; found {
+					result[k] = &v
+				}
+			}
+			// Simulate another client creating "c" after the lookup.
+			kv.data["c"] = "raced"
+			data, _ := json.Marshal(result)
+			return ok(string(data))
+		case "put_if_absent":
+			if _, found := kv.data[*req.Key]; found {
+				return ok("false")
+			}
+			kv.data[*req.Key] = *req.Value
+			return ok("true")
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+
+	var calls []string
+	existing, created, err := client.GetOrCreateMany([]string{"a", "b", "c"}, func(key string) (string, error) {
+		calls = append(calls, key)
+		return "new-" + key, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to get or create: %v", err)
+	}
+	if len(existing) != 2 || existing["a"] != "existing" || existing["c"] != "raced" {
+		t.Fatalf("unexpected existing %v", existing)
+	}
+	if len(created) != 1 || created["b"] != "new-b" {
+		t.Fatalf("unexpected created %v", created)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected factory calls for b and c, got %v", calls)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	increment := func(existing *string) (string, error) {
+		n := 0
+		if existing != nil {
+			n, _ = strconv.Atoi(*existing)
+		}
+		return strconv.Itoa(n + 1), nil
+	}
+	if value, err := client.Upsert("n", increment, nil); err != nil || value != "1" {
+		t.Fatalf("expected 1, got %q, %v", value, err)
+	}
+
+	// A concurrent writer changes the key after the first read.
+	calls := 0
+	racy := func(existing *string) (string, error) {
+		if calls++; calls == 1 {
+			kv.mu.Lock()
+			kv.data["n"] = "10"
+			kv.mu.Unlock()
+		}
+		return increment(existing)
+	}
+	if value, err := client.Upsert("n", racy, nil); err != nil || value != "11" || calls != 2 {
+		t.Fatalf("expected 11 after a retry, got %q, %v (calls %d)", value, err, calls)
+	}
+
+	failing := func(*string) (string, error) { return "", errors.New("rejected") }
+	if _, err := client.Upsert("n", failing, nil); err == nil || kv.data["n"] != "11" {
+		t.Fatalf("expected the error to abort the upsert, got %v", err)
+	}
+}
+
+func TestUpsertGivesUpUnderContention(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	client.SetTransactionRetryPolicy(rocksdbclient.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+
+	// Another writer changes the key before every swap.
+	calls := 0
+	contended := func(existing *string) (string, error) {
+		calls++
+		kv.mu.Lock()
+		kv.data["n"] = strconv.Itoa(calls)
+		kv.mu.Unlock()
+		return "x", nil
+	}
+	_, err := client.Upsert("n", contended, nil)
+	if !errors.Is(err, rocksdbclient.ErrTxnConflict) || calls != 3 {
+		t.Fatalf("expected ErrTxnConflict after 3 attempts, got %v (calls %d)", err, calls)
+	}
+}
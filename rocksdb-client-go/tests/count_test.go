@@ -0,0 +1,68 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCountKeys(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a", "user:1", "user:2", "v"} {
+		kv.data[k] = "v"
+	}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action != "count_keys" {
+			return kv.handle(req)
+		}
+		r := rocksdbclient.KeyRange{Start: req.Options["start"], End: req.Options["end"]}
+		count := 0
+		for k := range kv.data {
+			if r.Contains(k) {
+				count++
+			}
+		}
+		data, _ := json.Marshal(count)
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	if count, err := client.CountPrefix("user:", nil); err != nil || count != 2 {
+		t.Fatalf("expected 2 keys, got %d, %v", count, err)
+	}
+	if count, err := client.CountKeys(rocksdbclient.KeyRange{Start: "b"}, nil); err != nil || count != 3 {
+		t.Fatalf("expected 3 keys, got %d, %v", count, err)
+	}
+	if estimate, err := client.EstimateNumKeys(nil); err != nil || estimate != 4 {
+		t.Fatalf("expected estimate 4, got %d, %v", estimate, err)
+	}
+}
+
+func TestApproximateSizes(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		var ranges []map[string]string
+		json.Unmarshal([]byte(*req.Value), &ranges)
+		sizes := make([]int, len(ranges))
+		for i := range ranges {
+			sizes[i] = (i + 1) * 1024
+		}
+		data, _ := json.Marshal(sizes)
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	sizes, err := client.ApproximateSizes([]rocksdbclient.KeyRange{{Start: "a", End: "m"}, {Start: "m"}}, nil)
+	if err != nil {
+		t.Fatalf("failed to get sizes: %v", err)
+	}
+	if !reflect.DeepEqual(sizes, []uint64{1024, 2048}) {
+		t.Fatalf("unexpected sizes %v", sizes)
+	}
+	if req := server.received()[0]; req.Action != "get_approximate_sizes" || *req.Value != `[{"start":"a","end":"m"},{"start":"m"}]` {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
@@ -0,0 +1,36 @@
+package rocksdbclient_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestIncrDecr(t *testing.T) {
+	var mu sync.Mutex
+	counters := map[string]int64{}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		delta, err := strconv.ParseInt(req.Options["delta"], 10, 64)
+		if err != nil {
+			return fail("Invalid delta")
+		}
+		counters[*req.Key] += delta
+		return ok(strconv.FormatInt(counters[*req.Key], 10))
+	})
+	client := server.client()
+	defer client.Close()
+
+	if value, err := client.Incr("hits", 5, nil); err != nil || value != 5 {
+		t.Fatalf("expected 5, got %d, %v", value, err)
+	}
+	if value, err := client.Decr("hits", 2, nil); err != nil || value != 3 {
+		t.Fatalf("expected 3, got %d, %v", value, err)
+	}
+	if req := server.received()[1]; req.Action != "incr" || req.Options["delta"] != "-2" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
@@ -0,0 +1,41 @@
+package rocksdbclient_test
+
+import (
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestDebugHookReportsCallStats(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("value")
+	})
+	client := server.client()
+	defer client.Close()
+
+	var stats []rocksdbclient.CallStats
+	client.SetDebugHook(func(s rocksdbclient.CallStats) {
+		stats = append(stats, s)
+	})
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+
+	if len(stats) != 1 {
+		t.Fatalf("expected one stats record, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.Action != "get" || s.RequestBytes != len(`{"action":"get","key":"k"}`)+1 {
+		t.Fatalf("unexpected request stats %+v", s)
+	}
+	if s.ResponseBytes != len(`{"success":true,"result":"value"}`)+1 {
+		t.Fatalf("unexpected response size %d", s.ResponseBytes)
+	}
+	if !strings.Contains(s.Caller, "debug_test.go") {
+		t.Fatalf("expected caller in debug_test.go, got %q", s.Caller)
+	}
+	if s.Allocs == 0 {
+		t.Fatalf("expected allocations to be recorded")
+	}
+}
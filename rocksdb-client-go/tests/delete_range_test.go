@@ -0,0 +1,48 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestDeleteRange(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if _, err := client.DeleteRange("a", "m", stringPtr("users")); err != nil {
+		t.Fatalf("failed to delete range: %v", err)
+	}
+	req := server.received()[0]
+	if req.Action != "delete_range" || req.Options["start"] != "a" || req.Options["end"] != "m" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if req.CfName == nil || *req.CfName != "users" {
+		t.Fatalf("expected the column family to be sent, got %v", req.CfName)
+	}
+
+	if _, err := client.DeleteRange("m", "a", nil); err == nil {
+		t.Fatal("expected an inverted range to be rejected")
+	}
+	if n := len(server.received()); n != 1 {
+		t.Fatalf("expected invalid ranges not to be sent, got %d requests", n)
+	}
+}
+
+func TestDeleteRangeServerError(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return fail("Maintenance mode")
+	})
+	client := server.client()
+	defer client.Close()
+
+	_, err := client.DeleteRange("a", "m", nil)
+	var serverErr *rocksdbclient.ServerError
+	if !errors.As(err, &serverErr) || serverErr.Action != "delete_range" || !errors.Is(err, rocksdbclient.ErrMaintenance) {
+		t.Fatalf("expected the server error, got %v", err)
+	}
+}
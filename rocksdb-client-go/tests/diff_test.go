@@ -0,0 +1,50 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestGetDiff(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Options["diff_base"] {
+		case "":
+			return ok(`{"value":"{\"name\":\"a\",\"tags\":[\"x\",\"y\"],\"meta\":{\"v\":1}}","hash":"h1"}`)
+		case "h1":
+			return ok(`{"patch":[` +
+				`{"op":"replace","path":"/name","value":"b"},` +
+				`{"op":"add","path":"/tags/1","value":"new"},` +
+				`{"op":"remove","path":"/tags/0"},` +
+				`{"op":"move","from":"/meta/v","path":"/version"},` +
+				`{"op":"copy","from":"/tags","path":"/meta/tags"},` +
+				`{"op":"test","path":"/version","value":1}` +
+				`],"hash":"h2"}`)
+		}
+		return ok(`{"value":"{}","hash":"h3"}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	doc, err := client.GetDiff("doc", nil, nil)
+	if err != nil || doc.Hash != "h1" || doc.Patched {
+		t.Fatalf("unexpected full read %+v, %v", doc, err)
+	}
+	doc, err = client.GetDiff("doc", doc, nil)
+	if err != nil || doc.Hash != "h2" || !doc.Patched {
+		t.Fatalf("unexpected patched read %+v, %v", doc, err)
+	}
+
+	var got, want any
+	json.Unmarshal(doc.Value, &got)
+	json.Unmarshal([]byte(`{"name":"b","tags":["new","y"],"meta":{"tags":["new","y"]},"version":1}`), &want)
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("expected %s, got %s", wantJSON, gotJSON)
+	}
+	if req := server.received()[1]; req.Options["diff"] != "true" || req.Options["diff_base"] != "h1" {
+		t.Fatalf("unexpected request options %v", req.Options)
+	}
+}
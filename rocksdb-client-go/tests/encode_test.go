@@ -0,0 +1,63 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestRequestEncodingMatchesEncodingJSON(t *testing.T) {
+	txn := true
+	request := rocksdbclient.Request{
+		Action:  "put",
+		Key:     stringPtr("k\n\"<&> \x01"),
+		Value:   stringPtr("caf\xe9 ☕"),
+		CfName:  stringPtr("cf"),
+		Options: map[string]string{"z": "1", "a": "2", "m": "3"},
+		Token:   stringPtr("secret"),
+		Txn:     &txn,
+		TxnID:   stringPtr("7"),
+		Keys:    []string{"a", "b"},
+		Operations: []rocksdbclient.Operation{
+			{Type: rocksdbclient.OpPut, Key: "x", Value: stringPtr("y")},
+			{Type: rocksdbclient.OpDelete, Key: "z"},
+		},
+		RequestID: "9",
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	// Converting to a local type drops the MarshalJSON method, so this is
+	// what encoding/json produces by reflection.
+	type plainRequest rocksdbclient.Request
+	want, err := json.Marshal(plainRequest(request))
+	if err != nil {
+		t.Fatalf("failed to marshal by reflection: %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("unexpected encoding:\n%s\nwant:\n%s", data, want)
+	}
+
+	type plainOperation rocksdbclient.Operation
+	for _, op := range request.Operations {
+		got, _ := json.Marshal(op)
+		want, _ := json.Marshal(plainOperation(op))
+		if !bytes.Equal(got, want) {
+			t.Fatalf("unexpected operation encoding:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	other := request
+	other.Token = stringPtr("another")
+	if request.Hash() != other.Hash() {
+		t.Fatalf("hash must not depend on the token")
+	}
+	other.Options = map[string]string{"a": "2", "m": "3", "z": "1"}
+	if request.Hash() != other.Hash() {
+		t.Fatalf("hash must not depend on map iteration order")
+	}
+}
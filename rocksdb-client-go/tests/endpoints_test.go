@@ -0,0 +1,54 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestSetEndpointsDrainsInFlightRequests(t *testing.T) {
+	release := make(chan struct{})
+	oldServer := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get" {
+			<-release
+		}
+		return ok("old")
+	})
+	newServer := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("new")
+	})
+	client := oldServer.client()
+	defer client.Close()
+
+	done := make(chan error)
+	go func() {
+		response, err := client.Get(stringPtr("k"), nil, nil, nil)
+		if err == nil && response.Result != "old" {
+			t.Errorf("in-flight request answered by %q", response.Result)
+		}
+		done <- err
+	}()
+	for len(oldServer.received()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	switched := make(chan error)
+	go func() {
+		switched <- client.SetEndpoints([]string{newServer.listener.Addr().String()})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+	if err := <-switched; err != nil {
+		t.Fatalf("failed to switch endpoints: %v", err)
+	}
+
+	response, err := client.Get(stringPtr("k"), nil, nil, nil)
+	if err != nil || response.Result != "new" {
+		t.Fatalf("expected request on the new endpoint, got %v, %v", response, err)
+	}
+}
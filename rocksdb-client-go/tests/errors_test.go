@@ -0,0 +1,70 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestServerErrorClassification(t *testing.T) {
+	cases := []struct {
+		message string
+		want    error
+	}{
+		{"Key not found", rocksdbclient.ErrKeyNotFound},
+		{"Unauthorized", rocksdbclient.ErrUnauthorized},
+		{"Iterator ID not found", rocksdbclient.ErrIteratorInvalid},
+		{"Resource busy: ", rocksdbclient.ErrTxnConflict},
+		{"Failed to open column family", nil},
+	}
+
+	for _, tc := range cases {
+		server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+			return fail(tc.message)
+		})
+		client := server.client()
+
+		_, err := client.Get(stringPtr("k"), nil, nil, nil)
+		client.Close()
+
+		var serverErr *rocksdbclient.ServerError
+		if !errors.As(err, &serverErr) {
+			t.Fatalf("%q: expected *ServerError, got %v", tc.message, err)
+		}
+		if serverErr.Action != "get" || serverErr.Message != tc.message {
+			t.Fatalf("%q: unexpected error fields %+v", tc.message, serverErr)
+		}
+		if tc.want != nil && !errors.Is(err, tc.want) {
+			t.Fatalf("%q: expected errors.Is(%v)", tc.message, tc.want)
+		}
+		if tc.want == nil && serverErr.Err != nil {
+			t.Fatalf("%q: expected unclassified error, got %v", tc.message, serverErr.Err)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get" {
+			<-release
+		}
+		return ok("v")
+	})
+	client := server.client()
+	defer client.Close()
+	client.SetRequestTimeout(50 * time.Millisecond)
+
+	_, err := client.Get(stringPtr("slow"), nil, nil, nil)
+	if !errors.Is(err, rocksdbclient.ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+
+	response, err := client.SendRequest(rocksdbclient.Request{Action: "put", Timeout: time.Second})
+	if err != nil || response.Result != "v" {
+		t.Fatalf("expected request on a fresh connection to succeed, got %v, %v", response, err)
+	}
+}
@@ -0,0 +1,90 @@
+package rocksdbclient_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestExportArchive(t *testing.T) {
+	source := seededKV(10)
+	sourceClient := newFakeServer(t, source.handle).client()
+	defer sourceClient.Close()
+
+	var buf bytes.Buffer
+	result, err := sourceClient.Export(&buf, rocksdbclient.ExportOptions{
+		Prefix:    "user:",
+		Format:    rocksdbclient.ExportArchive,
+		PageSize:  3,
+		ChunkSize: 4,
+		TempDir:   t.TempDir(),
+	})
+	if err != nil || result.Count != 10 {
+		t.Fatalf("unexpected export result %+v, %v", result, err)
+	}
+
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid tar archive: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	want := []string{"manifest.json", "data/000000.ndjson", "data/000001.ndjson", "data/000002.ndjson"}
+	if len(names) != len(want) || names[0] != want[0] || names[3] != want[3] {
+		t.Fatalf("unexpected archive files %v", names)
+	}
+
+	manifest, err := rocksdbclient.VerifyArchive(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if manifest.Version != rocksdbclient.ArchiveVersion || manifest.Prefix != "user:" || manifest.Entries != 10 || len(manifest.Chunks) != 3 || manifest.Chunks[2].Entries != 2 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+
+	target := newFakeKV()
+	targetClient := newFakeServer(t, target.handle).client()
+	defer targetClient.Close()
+	n, err := targetClient.Import(bytes.NewReader(buf.Bytes()))
+	delete(source.data, "other")
+	if err != nil || n != 10 || !maps.Equal(target.data, source.data) {
+		t.Fatalf("imported %d entries (%v): %v", n, err, target.data)
+	}
+}
+
+func TestExportArchiveCorruption(t *testing.T) {
+	client := newFakeServer(t, seededKV(10).handle).client()
+	defer client.Close()
+
+	var buf bytes.Buffer
+	if _, err := client.Export(&buf, rocksdbclient.ExportOptions{Format: rocksdbclient.ExportArchive, ChunkSize: 4}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	data := buf.Bytes()
+	i := bytes.LastIndex(data, []byte(`"v9"`))
+	data[i+2] = '8'
+
+	if _, err := rocksdbclient.VerifyArchive(bytes.NewReader(data)); !errors.Is(err, rocksdbclient.ErrCorruption) {
+		t.Fatalf("expected ErrCorruption, got %v", err)
+	}
+	target := newFakeKV()
+	targetClient := newFakeServer(t, target.handle).client()
+	defer targetClient.Close()
+	if _, err := targetClient.Import(bytes.NewReader(data)); !errors.Is(err, rocksdbclient.ErrCorruption) {
+		t.Fatalf("expected ErrCorruption, got %v", err)
+	}
+	if _, found := target.data["user:09"]; found {
+		t.Fatal("entries of a corrupt chunk must not be imported")
+	}
+}
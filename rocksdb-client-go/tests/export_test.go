@@ -0,0 +1,94 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func seededKV(n int) *fakeKV {
+	kv := newFakeKV()
+	for i := 0; i < n; i++ {
+		kv.data[fmt.Sprintf("user:%02d", i)] = fmt.Sprintf("v%d", i)
+	}
+	kv.data["other"] = "x"
+	return kv
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []rocksdbclient.ExportFormat{rocksdbclient.ExportNDJSON, rocksdbclient.ExportBinary} {
+		t.Run(string(format), func(t *testing.T) {
+			source := seededKV(10)
+			sourceClient := newFakeServer(t, source.handle).client()
+			defer sourceClient.Close()
+
+			var buf bytes.Buffer
+			result, err := sourceClient.Export(&buf, rocksdbclient.ExportOptions{Prefix: "user:", Format: format, PageSize: 3})
+			if err != nil {
+				t.Fatalf("export failed: %v", err)
+			}
+			if result.Count != 10 || result.Cursor != "" {
+				t.Fatalf("unexpected export result %+v", result)
+			}
+
+			target := newFakeKV()
+			targetClient := newFakeServer(t, target.handle).client()
+			defer targetClient.Close()
+
+			n, err := targetClient.ImportWith(&buf, rocksdbclient.ImportOptions{BatchSize: 4})
+			if err != nil {
+				t.Fatalf("import failed: %v", err)
+			}
+			delete(source.data, "other")
+			if n != 10 || !maps.Equal(target.data, source.data) {
+				t.Fatalf("imported %d entries: %v", n, target.data)
+			}
+		})
+	}
+}
+
+// failingWriter accepts n writes and fails afterwards.
+type failingWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return w.Buffer.Write(p)
+}
+
+func TestExportResume(t *testing.T) {
+	source := seededKV(10)
+	client := newFakeServer(t, source.handle).client()
+	defer client.Close()
+
+	w := &failingWriter{n: 2}
+	opts := rocksdbclient.ExportOptions{Prefix: "user:", PageSize: 4}
+	result, err := client.Export(w, opts)
+	if err == nil || result.Count != 8 || result.Cursor == "" {
+		t.Fatalf("expected a partial export, got %+v, %v", result, err)
+	}
+
+	w.n = 1
+	opts.Cursor = result.Cursor
+	result, err = client.Export(w, opts)
+	if err != nil || result.Count != 2 {
+		t.Fatalf("unexpected resumed export %+v, %v", result, err)
+	}
+	if lines := strings.Count(w.String(), "\n"); lines != 10 {
+		t.Fatalf("expected 10 exported entries, got %d", lines)
+	}
+
+	if _, err := client.Import(strings.NewReader("{\"key\":\"a\",\"value\":\"1\"}\nnot json\n")); err == nil {
+		t.Fatal("expected a malformed stream to fail")
+	}
+}
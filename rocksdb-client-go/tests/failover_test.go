@@ -0,0 +1,68 @@
+package rocksdbclient_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestFailover(t *testing.T) {
+	answer := func(result string) func(rocksdbclient.Request) rocksdbclient.Response {
+		return func(rocksdbclient.Request) rocksdbclient.Response { return ok(result) }
+	}
+	// Reserve an address for the primary, which starts out down.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	primaryAddr := listener.Addr().String()
+	listener.Close()
+	standby := newFakeServer(t, answer("standby"))
+	standbyAddr := standby.listener.Addr().String()
+
+	client := rocksdbclient.NewClient(primaryAddr,
+		rocksdbclient.WithTimeout(time.Second),
+		rocksdbclient.WithRetryInterval(10*time.Millisecond),
+		rocksdbclient.WithFailover(rocksdbclient.FailoverOptions{
+			Standbys:      []string{standbyAddr},
+			ProbeInterval: 20 * time.Millisecond,
+		}),
+	)
+	defer client.Close()
+
+	get := func() (string, error) {
+		response, err := client.Get(stringPtr("k"), nil, nil, nil)
+		if err != nil {
+			return "", err
+		}
+		return response.Result, nil
+	}
+	if result, err := get(); err != nil || result != "standby" || client.CurrentEndpoint() != standbyAddr {
+		t.Fatalf("expected failover to the standby, got %q, %v", result, err)
+	}
+
+	primary := newFakeServerAt(t, primaryAddr, answer("primary"))
+	waitFor(t, func() bool { return client.CurrentEndpoint() == primaryAddr })
+	if result, err := get(); err != nil || result != "primary" {
+		t.Fatalf("expected fail back to the primary, got %q, %v", result, err)
+	}
+
+	primary.kill()
+	if _, err := get(); err == nil {
+		t.Fatal("expected the request on the crashed primary to fail")
+	}
+	if result, err := get(); err != nil || result != "standby" {
+		t.Fatalf("expected the next request on the standby, got %q, %v", result, err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); !cond(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+	}
+}
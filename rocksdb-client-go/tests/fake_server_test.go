@@ -0,0 +1,332 @@
+package rocksdbclient_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// fakeServer speaks the newline-delimited JSON protocol of the real server and
+// answers every request with handler, so client logic can be tested offline.
+type fakeServer struct {
+	listener net.Listener
+	handler  func(rocksdbclient.Request) rocksdbclient.Response
+
+	mu       sync.Mutex
+	requests []rocksdbclient.Request
+	conns    []net.Conn
+}
+
+func newFakeServer(t testing.TB, handler func(rocksdbclient.Request) rocksdbclient.Response) *fakeServer {
+	t.Helper()
+	return newFakeServerAt(t, "127.0.0.1:0", handler)
+}
+
+// newFakeServerAt starts a fake server listening on addr.
+func newFakeServerAt(t testing.TB, addr string, handler func(rocksdbclient.Request) rocksdbclient.Response) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeServer{listener: listener, handler: handler}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var request rocksdbclient.Request
+		if err := json.Unmarshal(line, &request); err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		s.mu.Unlock()
+
+		data, _ := json.Marshal(s.handler(request))
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) client() *rocksdbclient.RocksDBClient {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return rocksdbclient.NewRocksDBClient(addr.IP.String(), addr.Port, nil, time.Second, 10*time.Millisecond)
+}
+
+// kill stops the server and drops every open connection, like a crashed
+// process.
+func (s *fakeServer) kill() {
+	s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *fakeServer) received() []rocksdbclient.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]rocksdbclient.Request(nil), s.requests...)
+}
+
+func ok(result string) rocksdbclient.Response {
+	return rocksdbclient.Response{Success: true, Result: result}
+}
+
+func fail(message string) rocksdbclient.Response {
+	return rocksdbclient.Response{Success: false, Result: message}
+}
+
+// fakeKV is an in-memory handler implementing the basic key/value and
+// iterator actions over a sorted keyspace.
+type fakeKV struct {
+	mu        sync.Mutex
+	data      map[string]string
+	iterators map[string]string
+	nextID    int
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{data: map[string]string{}, iterators: map[string]string{}}
+}
+
+func (kv *fakeKV) sortedKeys() []string {
+	keys := make([]string, 0, len(kv.data))
+	for k := range kv.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// entryAt positions iterator id on the first key >= key (or the key after it
+// when skip is set) and renders it as a JSON iterator entry.
+func (kv *fakeKV) entryAt(id, key string, skip bool) string {
+	for _, k := range kv.sortedKeys() {
+		if k > key || (k == key && !skip) {
+			kv.iterators[id] = k
+			data, _ := json.Marshal(map[string]string{"key": k, "value": kv.data[k]})
+			return string(data)
+		}
+	}
+	return "invalid:invalid"
+}
+
+// entryBefore positions iterator id on the last key <= key (or the key before
+// it when skip is set), mirroring entryAt for reverse iteration.
+func (kv *fakeKV) entryBefore(id, key string, skip bool) string {
+	keys := kv.sortedKeys()
+	for i := len(keys) - 1; i >= 0; i-- {
+		if k := keys[i]; k < key || (k == key && !skip) {
+			kv.iterators[id] = k
+			data, _ := json.Marshal(map[string]string{"key": k, "value": kv.data[k]})
+			return string(data)
+		}
+	}
+	return "invalid:invalid"
+}
+
+func (kv *fakeKV) handle(req rocksdbclient.Request) rocksdbclient.Response {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	switch req.Action {
+	case "put":
+		kv.data[*req.Key] = *req.Value
+		return ok("")
+	case "get":
+		if v, found := kv.data[*req.Key]; found {
+			return ok(v)
+		}
+		if req.DefaultValue != nil {
+			return ok(*req.DefaultValue)
+		}
+		return fail("Key not found")
+	case "delete":
+		delete(kv.data, *req.Key)
+		return ok("")
+	case "merge":
+		// Only the JSON Patch "add /-" operation used by list helpers.
+		var list []json.RawMessage
+		json.Unmarshal([]byte(kv.data[*req.Key]), &list)
+		var patch []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal([]byte(*req.Value), &patch); err != nil {
+			return fail("Invalid patch")
+		}
+		for _, op := range patch {
+			if op.Op == "add" && op.Path == "/-" {
+				list = append(list, op.Value)
+			}
+		}
+		data, _ := json.Marshal(list)
+		kv.data[*req.Key] = string(data)
+		return ok("")
+	case "multi_get":
+		values := map[string]*string{}
+		for _, k := range req.Keys {
+			if v, found := kv.data[k]; found {
+				values[k] = &v
+			}
+		}
+		data, _ := json.Marshal(values)
+		return ok(string(data))
+	case "batch_write":
+		for _, op := range req.Operations {
+			switch op.Type {
+			case rocksdbclient.OpPut:
+				kv.data[op.Key] = *op.Value
+			case rocksdbclient.OpDelete:
+				delete(kv.data, op.Key)
+			}
+		}
+		return ok("")
+	case "put_if_absent":
+		if _, found := kv.data[*req.Key]; found {
+			return ok("false")
+		}
+		kv.data[*req.Key] = *req.Value
+		return ok("true")
+	case "compare_and_swap":
+		current, found := kv.data[*req.Key]
+		expected, expectFound := req.Options["expected"]
+		if found != expectFound || current != expected {
+			result := map[string]any{"swapped": false, "current": nil}
+			if found {
+				result["current"] = current
+			}
+			data, _ := json.Marshal(result)
+			return ok(string(data))
+		}
+		kv.data[*req.Key] = *req.Value
+		return ok(`{"swapped":true}`)
+	case "keys":
+		var matched []string
+		for _, k := range kv.sortedKeys() {
+			if strings.Contains(k, req.Options["query"]) {
+				matched = append(matched, k)
+			}
+		}
+		start, _ := strconv.Atoi(req.Options["start"])
+		limit, _ := strconv.Atoi(req.Options["limit"])
+		if start > len(matched) {
+			start = len(matched)
+		}
+		if limit > 0 && start+limit < len(matched) {
+			matched = matched[:start+limit]
+		}
+		data, _ := json.Marshal(matched[start:])
+		return ok(string(data))
+	case "all":
+		matched := []string{}
+		for _, k := range kv.sortedKeys() {
+			if strings.Contains(k, req.Options["query"]) {
+				matched = append(matched, k)
+			}
+		}
+		data, _ := json.Marshal(matched)
+		return ok(string(data))
+	case "keys_cursor":
+		// The cursor is the last key of the previous page.
+		var page []string
+		limit, _ := strconv.Atoi(req.Options["limit"])
+		for _, k := range kv.sortedKeys() {
+			if strings.HasPrefix(k, req.Options["prefix"]) && k > req.Options["cursor"] {
+				page = append(page, k)
+			}
+		}
+		cursor := ""
+		if len(page) > limit {
+			page = page[:limit]
+			cursor = page[limit-1]
+		}
+		data, _ := json.Marshal(map[string]any{"keys": page, "cursor": cursor})
+		return ok(string(data))
+	case "list_column_families":
+		return ok(`["default"]`)
+	case "get_approximate_sizes":
+		var ranges []struct{ Start, End string }
+		json.Unmarshal([]byte(*req.Value), &ranges)
+		sizes := make([]int, len(ranges))
+		for i, r := range ranges {
+			for k, v := range kv.data {
+				if k >= r.Start && (r.End == "" || k < r.End) {
+					sizes[i] += len(k) + len(v)
+				}
+			}
+		}
+		data, _ := json.Marshal(sizes)
+		return ok(string(data))
+	case "get_property":
+		if *req.Value == "rocksdb.estimate-num-keys" {
+			return ok(strconv.Itoa(len(kv.data)))
+		}
+		return ok("")
+	case "create_iterator":
+		kv.nextID++
+		id := strconv.Itoa(kv.nextID)
+		kv.iterators[id] = ""
+		return ok(id)
+	case "iterator_seek":
+		return ok(kv.entryAt(req.Options["iterator_id"], *req.Key, false))
+	case "iterator_seek_to_first":
+		return ok(kv.entryAt(req.Options["iterator_id"], "", false))
+	case "iterator_seek_to_last":
+		keys := kv.sortedKeys()
+		if len(keys) == 0 {
+			return ok("invalid:invalid")
+		}
+		return ok(kv.entryBefore(req.Options["iterator_id"], keys[len(keys)-1], false))
+	case "iterator_seek_for_prev":
+		return ok(kv.entryBefore(req.Options["iterator_id"], *req.Key, false))
+	case "iterator_next", "iterator_prev":
+		id := req.Options["iterator_id"]
+		pos, found := kv.iterators[id]
+		if !found {
+			return fail("Iterator ID not found")
+		}
+		if req.Action == "iterator_prev" {
+			return ok(kv.entryBefore(id, pos, true))
+		}
+		return ok(kv.entryAt(id, pos, true))
+	case "destroy_iterator":
+		delete(kv.iterators, req.Options["iterator_id"])
+		return ok("")
+	}
+	return fail("Unknown action")
+}
@@ -0,0 +1,30 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestFlush(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.Flush(stringPtr("users")); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if err := client.FlushWAL(true); err != nil {
+		t.Fatalf("flush_wal failed: %v", err)
+	}
+
+	requests := server.received()
+	if requests[0].Action != "flush" || requests[0].CfName == nil || *requests[0].CfName != "users" {
+		t.Fatalf("unexpected flush request %+v", requests[0])
+	}
+	if requests[1].Action != "flush_wal" || requests[1].Options["sync"] != "true" {
+		t.Fatalf("unexpected flush_wal request %+v", requests[1])
+	}
+}
@@ -0,0 +1,120 @@
+package rocksdbclient_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestGroupCommitBatchesConcurrentSyncWrites(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+	client.EnableGroupCommit(rocksdbclient.GroupCommitOptions{MaxDelay: 50 * time.Millisecond, MaxBatch: 8})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := client.PutSync(fmt.Sprintf("k%d", i), "v", nil); err != nil {
+				t.Errorf("sync put failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	received := server.received()
+	if len(received) != 1 {
+		t.Fatalf("expected one grouped request, got %d", len(received))
+	}
+	if received[0].Action != "batch_write" || len(received[0].Operations) != 8 || received[0].Options["sync"] != "true" {
+		t.Fatalf("unexpected grouped request %+v", received[0])
+	}
+}
+
+func TestGroupCommitKeepsResponseAndOptions(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Options["return_old"] == "true" {
+			return ok(`{"old":"prev"}`)
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+	client.EnableGroupCommit(rocksdbclient.GroupCommitOptions{MaxDelay: 50 * time.Millisecond, MaxBatch: 8})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		result, err := client.PutWith("k", "v", nil, rocksdbclient.WithWriteOptions(rocksdbclient.WriteOptions{Sync: true}), rocksdbclient.WithReturnOld())
+		if err != nil || result.Old == nil || *result.Old != "prev" {
+			t.Errorf("expected the old value, got %v, %v", result, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if _, err := client.PutSync("other", "v", nil); err != nil {
+			t.Errorf("sync put failed: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	for _, req := range server.received() {
+		if req.Action == "batch_write" {
+			t.Fatalf("expected writes with other options not to be grouped, got %+v", req)
+		}
+	}
+}
+
+func TestGroupCommitSingleWriteResponse(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("done")
+	})
+	client := server.client()
+	defer client.Close()
+	client.EnableGroupCommit(rocksdbclient.GroupCommitOptions{MaxDelay: time.Millisecond})
+
+	response, err := client.PutSync("k", "v", nil)
+	if err != nil || response.Result != "done" {
+		t.Fatalf("expected the server response, got %v, %v", response, err)
+	}
+}
+
+func TestGroupCommitSkipsTransactionsAndTimeouts(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+	client.EnableGroupCommit(rocksdbclient.GroupCommitOptions{MaxDelay: 50 * time.Millisecond, MaxBatch: 8})
+
+	syncOption := map[string]string{"sync": "true"}
+	requests := []rocksdbclient.Request{
+		{Action: "put", Key: stringPtr("a"), Value: stringPtr("v"), Options: syncOption, TxnID: stringPtr("t1")},
+		{Action: "put", Key: stringPtr("b"), Value: stringPtr("v"), Options: syncOption, Timeout: time.Second},
+	}
+	var wg sync.WaitGroup
+	for _, request := range requests {
+		wg.Add(1)
+		go func(request rocksdbclient.Request) {
+			defer wg.Done()
+			if _, err := client.SendRequest(request); err != nil {
+				t.Errorf("sync put failed: %v", err)
+			}
+		}(request)
+	}
+	wg.Wait()
+
+	for _, req := range server.received() {
+		if req.Action != "put" {
+			t.Fatalf("expected the writes to be sent on their own, got %+v", req)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package rocksdbclient_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestHAClientPromotion(t *testing.T) {
+	primary := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get_latest_sequence_number" {
+			return ok("10")
+		}
+		return ok("primary")
+	})
+	var standbySeq atomic.Value
+	standbySeq.Store("8")
+	standby := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get_latest_sequence_number" {
+			return ok(standbySeq.Load().(string))
+		}
+		return ok("standby")
+	})
+
+	var mu sync.Mutex
+	var events []rocksdbclient.HAEventType
+	client := rocksdbclient.NewClient(primary.listener.Addr().String(),
+		rocksdbclient.WithTimeout(50*time.Millisecond),
+		rocksdbclient.WithRetryInterval(5*time.Millisecond),
+	)
+	defer client.Close()
+	ha := rocksdbclient.NewHAClient(client, standby.listener.Addr().String(), rocksdbclient.HAOptions{
+		Interval:         10 * time.Millisecond,
+		FailureThreshold: 2,
+		OnEvent: func(event rocksdbclient.HAEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event.Type)
+		},
+	})
+	defer ha.Close()
+
+	waitFor(t, func() bool { return ha.Lag() == 2 })
+	if response, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil); err != nil || response.Result != "primary" {
+		t.Fatalf("expected the write on the primary, got %v, %v", response, err)
+	}
+
+	primary.kill()
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) >= 2
+	})
+	if ha.Promoted() {
+		t.Fatal("a lagging standby must not be promoted")
+	}
+	standbySeq.Store("10")
+	waitFor(t, ha.Promoted)
+
+	mu.Lock()
+	if events[0] != rocksdbclient.HAPrimaryDown || events[1] != rocksdbclient.HAPromotionBlocked ||
+		events[len(events)-1] != rocksdbclient.HAPromoted {
+		t.Fatalf("unexpected events %v", events)
+	}
+	mu.Unlock()
+	if response, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil); err != nil || response.Result != "standby" {
+		t.Fatalf("expected the write on the promoted standby, got %v, %v", response, err)
+	}
+}
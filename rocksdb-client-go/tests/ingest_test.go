@@ -0,0 +1,104 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// fakeUploads assembles upload_chunk requests into files and records
+// ingested paths.
+type fakeUploads struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	chunks   int
+	ingested []string
+	move     bool
+}
+
+func (u *fakeUploads) handle(req rocksdbclient.Request) rocksdbclient.Response {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	switch req.Action {
+	case "upload_chunk":
+		name := req.Options["name"]
+		offset, _ := strconv.Atoi(req.Options["offset"])
+		if offset != len(u.files[name]) {
+			return fail("Unexpected offset")
+		}
+		data, err := base64.StdEncoding.DecodeString(*req.Value)
+		if err != nil {
+			return fail("Invalid chunk")
+		}
+		u.files[name] = append(u.files[name], data...)
+		u.chunks++
+		if req.Options["last"] == "true" {
+			return ok(`{"path":"/uploads/` + name + `"}`)
+		}
+		return ok("")
+	case "ingest_external_file":
+		json.Unmarshal([]byte(*req.Value), &u.ingested)
+		u.move = req.Options["move_files"] == "true"
+		return ok("")
+	}
+	return fail("Unknown action")
+}
+
+func TestUploadFile(t *testing.T) {
+	uploads := &fakeUploads{files: map[string][]byte{}}
+	server := newFakeServer(t, uploads.handle)
+	client := server.client()
+	defer client.Close()
+
+	data := bytes.Repeat([]byte("sst"), 900000)
+	path, err := client.UploadFile(bytes.NewReader(data), "data.sst")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if path != "/uploads/data.sst" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if uploads.chunks != 3 || !bytes.Equal(uploads.files["data.sst"], data) {
+		t.Fatalf("unexpected upload: %d chunks, %d bytes", uploads.chunks, len(uploads.files["data.sst"]))
+	}
+	if _, err := client.UploadFile(bytes.NewReader(data), "../data.sst"); err == nil {
+		t.Fatal("expected a name with a directory to be rejected")
+	}
+}
+
+func TestIngestLocalFiles(t *testing.T) {
+	uploads := &fakeUploads{files: map[string][]byte{}}
+	server := newFakeServer(t, uploads.handle)
+	client := server.client()
+	defer client.Close()
+
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.sst", "b.sst"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	if err := client.IngestLocalFiles(paths, nil); err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+	if len(uploads.ingested) != 2 || uploads.ingested[1] != "/uploads/b.sst" || !uploads.move {
+		t.Fatalf("unexpected ingestion %v (move %v)", uploads.ingested, uploads.move)
+	}
+	if string(uploads.files["a.sst"]) != "a.sst" {
+		t.Fatalf("unexpected upload %q", uploads.files["a.sst"])
+	}
+
+	if err := client.IngestExternalFile(nil, nil, false); err == nil {
+		t.Fatal("expected an empty path list to be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package rocksdbclient_test
+
+import (
+	"reflect"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestIteratorRangeOverFunc(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["user:1"] = `{"name":"a"}`
+	kv.data["user:2"] = `{"name":"b"}`
+	kv.data["user:3"] = `{"name":"c"}`
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	it, err := client.NewIterator()
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	defer it.Close()
+
+	var keys []string
+	for k, v := range it.All() {
+		if v != kv.data[k] {
+			t.Fatalf("unexpected value %q for %q", v, k)
+		}
+		keys = append(keys, k)
+		if k == "user:2" {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "user:1" || keys[1] != "user:2" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	if !it.Valid() || it.Key() != "user:2" {
+		t.Fatalf("iterator should stay on the last yielded key")
+	}
+	if it.Next(); it.Key() != "user:3" {
+		t.Fatalf("expected user:3, got %q", it.Key())
+	}
+	if it.Next() {
+		t.Fatalf("expected iterator to be exhausted")
+	}
+}
+
+func TestIteratorOptions(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	it, err := client.NewIteratorWithOptions(rocksdbclient.IteratorOptions{
+		LowerBound:        "a",
+		UpperBound:        "m",
+		PrefixSameAsStart: true,
+		CF:                "users",
+	})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	it.Close()
+
+	req := server.received()[0]
+	want := map[string]string{"lower_bound": "a", "upper_bound": "m", "prefix_same_as_start": "true"}
+	if req.Action != "create_iterator" || *req.CfName != "users" || !reflect.DeepEqual(req.Options, want) {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
+
+func TestIteratorSeekToFirstAndLast(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"b", "d", "f"} {
+		kv.data[k] = "v-" + k
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	it, err := client.NewIterator()
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	defer it.Close()
+
+	if !it.SeekToLast() || it.Key() != "f" {
+		t.Fatalf("expected last key f, got %q", it.Key())
+	}
+	if !it.SeekForPrev("e") || it.Key() != "d" {
+		t.Fatalf("expected d before e, got %q", it.Key())
+	}
+	if !it.Prev() || it.Key() != "b" || it.Prev() {
+		t.Fatalf("expected b to be the first key, got %q", it.Key())
+	}
+	if !it.SeekToFirst() || it.Key() != "b" {
+		t.Fatalf("expected first key b, got %q", it.Key())
+	}
+}
@@ -0,0 +1,154 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// fakeJobs runs jobs that finish after a number of status polls.
+type fakeJobs struct {
+	mu     sync.Mutex
+	polls  map[string]int
+	states map[string]rocksdbclient.JobState
+}
+
+func (f *fakeJobs) handle(req rocksdbclient.Request) rocksdbclient.Response {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch req.Action {
+	case "backup", "restore":
+		if req.Options["async"] != "true" {
+			return fail("expected async")
+		}
+		return ok("job-" + req.Action)
+	case "cancel_operation":
+		f.states[req.Options["job_id"]] = rocksdbclient.JobCanceled
+		return ok("")
+	case "job_status":
+		id := req.Options["job_id"]
+		state, found := f.states[id]
+		if !found {
+			if f.polls[id]++; f.polls[id] < 2 {
+				state = rocksdbclient.JobRunning
+			} else {
+				state = rocksdbclient.JobSucceeded
+			}
+		}
+		return ok(`{"id":"` + id + `","kind":"backup","state":"` + string(state) + `","progress":0.5}`)
+	}
+	return fail("Unknown action")
+}
+
+func TestJobWait(t *testing.T) {
+	jobs := &fakeJobs{polls: map[string]int{}, states: map[string]rocksdbclient.JobState{}}
+	server := newFakeServer(t, jobs.handle)
+	client := server.client()
+	defer client.Close()
+
+	job, err := client.BackupJob()
+	if err != nil {
+		t.Fatalf("failed to start backup: %v", err)
+	}
+	if job.ID != "job-backup" || job.Kind != "backup" {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	status, err := job.Wait(context.Background())
+	if err != nil || status.State != rocksdbclient.JobSucceeded {
+		t.Fatalf("expected success, got %+v, %v", status, err)
+	}
+
+	job, err = client.RestoreJob(3)
+	if err != nil {
+		t.Fatalf("failed to start restore: %v", err)
+	}
+	if err := job.Cancel(); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if _, err := job.Wait(context.Background()); !errors.Is(err, rocksdbclient.ErrJobCanceled) {
+		t.Fatalf("expected ErrJobCanceled, got %v", err)
+	}
+	for _, req := range server.received() {
+		if req.Action == "restore" && req.Options["backup_id"] != "3" {
+			t.Fatalf("unexpected restore request %+v", req)
+		}
+	}
+}
+
+func TestJobWaitContext(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "job_status" {
+			return ok(`{"id":"j","state":"running"}`)
+		}
+		return ok("j")
+	})
+	client := server.client()
+	defer client.Close()
+
+	job, _ := client.BackupJob()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err := job.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || status.State != rocksdbclient.JobRunning {
+		t.Fatalf("expected deadline with a running status, got %+v, %v", status, err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	jobs := &fakeJobs{polls: map[string]int{}, states: map[string]rocksdbclient.JobState{}}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "compact_range" {
+			return ok("job-compact")
+		}
+		return jobs.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+
+	job, err := client.CompactRangeJob("a", "", stringPtr("logs"))
+	if err != nil {
+		t.Fatalf("failed to start compaction: %v", err)
+	}
+	if err := client.Cancel(job.ID); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if _, err := job.Wait(context.Background()); !errors.Is(err, rocksdbclient.ErrJobCanceled) {
+		t.Fatalf("expected ErrJobCanceled, got %v", err)
+	}
+
+	req := server.received()[0]
+	if req.Options["start"] != "a" || req.Options["async"] != "true" {
+		t.Fatalf("unexpected compact_range options %v", req.Options)
+	}
+	if _, found := req.Options["end"]; found {
+		t.Fatalf("expected an open end bound, got %v", req.Options)
+	}
+}
+
+func TestListOperations(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`[{"id":"7","kind":"compact_range","state":"running","progress":0.25,"started_at":"2024-05-01T10:00:00Z","client":"nightly"}]`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	jobs, err := client.ListOperations()
+	if err != nil {
+		t.Fatalf("list_operations failed: %v", err)
+	}
+	want := rocksdbclient.JobStatus{
+		ID:        "7",
+		Kind:      "compact_range",
+		State:     rocksdbclient.JobRunning,
+		Progress:  0.25,
+		StartedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Client:    "nightly",
+	}
+	if len(jobs) != 1 || jobs[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, jobs)
+	}
+}
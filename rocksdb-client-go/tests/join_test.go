@@ -0,0 +1,64 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestJoinScan(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["order:1"] = "u1"
+	kv.data["order:2"] = "u2"
+	kv.data["order:3"] = "u1"
+	users := map[string]string{"u1": "alice"}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action != "multi_get_cf" {
+			return kv.handle(req)
+		}
+		var cfs []string
+		json.Unmarshal([]byte(req.Options["cf_names"]), &cfs)
+		result := make([]*string, len(req.Keys))
+		for i, key := range req.Keys {
+			if v, found := users[key]; found && cfs[i] == "users" {
+				result[i] = &v
+			}
+		}
+		data, _ := json.Marshal(result)
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	joins := []rocksdbclient.Join{{
+		CF:  "users",
+		Key: func(key, value string) (string, bool) { return value, true },
+	}}
+	var rows []string
+	err := client.JoinScan(nil, "order:", joins, 2, func(row rocksdbclient.JoinRow) error {
+		name := "<none>"
+		if row.Related[0] != nil {
+			name = *row.Related[0]
+		}
+		rows = append(rows, row.Key+"="+name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to join: %v", err)
+	}
+	if got := strings.Join(rows, ","); got != "order:1=alice,order:2=<none>,order:3=alice" {
+		t.Fatalf("unexpected rows %s", got)
+	}
+
+	lookups := 0
+	for _, req := range server.received() {
+		if req.Action == "multi_get_cf" {
+			lookups++
+		}
+	}
+	if lookups != 2 {
+		t.Fatalf("expected 2 batched lookups, got %d", lookups)
+	}
+}
@@ -0,0 +1,143 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestJournal(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	path := filepath.Join(t.TempDir(), "journal.log")
+	journal, err := rocksdbclient.NewJournal(client, rocksdbclient.JournalOptions{Path: path})
+	if err != nil {
+		t.Fatalf("failed to open journal: %v", err)
+	}
+
+	client.Put(stringPtr("a"), stringPtr("1"), nil, nil)
+	client.Get(stringPtr("a"), nil, nil, nil)
+	client.Put(stringPtr("b"), stringPtr("2"), nil, nil)
+	client.Delete(stringPtr("a"), nil, nil)
+	client.BatchWrite([]rocksdbclient.Operation{{Type: rocksdbclient.OpPut, Key: "c", Value: stringPtr("3")}})
+	if err := journal.Close(); err != nil || journal.Err() != nil {
+		t.Fatalf("failed to close journal: %v, %v", err, journal.Err())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open journal file: %v", err)
+	}
+	defer file.Close()
+	var actions []string
+	rocksdbclient.ReadJournal(file, func(entry rocksdbclient.JournalEntry) error {
+		actions = append(actions, entry.Request.Action)
+		return nil
+	})
+	if strings.Join(actions, ",") != "put,put,delete,batch_write" {
+		t.Fatalf("unexpected journaled actions %v", actions)
+	}
+
+	replayKV := newFakeKV()
+	replayServer := newFakeServer(t, replayKV.handle)
+	replayClient := replayServer.client()
+	defer replayClient.Close()
+	file.Seek(0, 0)
+	applied, err := rocksdbclient.ReplayJournal(replayClient, file)
+	if err != nil || applied != 4 {
+		t.Fatalf("unexpected replay result %d, %v", applied, err)
+	}
+	if len(replayKV.data) != 2 || replayKV.data["b"] != "2" || replayKV.data["c"] != "3" {
+		t.Fatalf("unexpected replayed data %v", replayKV.data)
+	}
+}
+
+func TestJournalRotationAndRedaction(t *testing.T) {
+	server := newFakeServer(t, newFakeKV().handle)
+	client := server.client()
+	defer client.Close()
+	path := filepath.Join(t.TempDir(), "journal.log")
+	journal, err := rocksdbclient.NewJournal(client, rocksdbclient.JournalOptions{
+		Path:         path,
+		MaxSize:      300,
+		MaxFiles:     2,
+		RedactValues: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open journal: %v", err)
+	}
+	for range 10 {
+		client.Put(stringPtr("k"), stringPtr("secret"), nil, nil)
+	}
+	journal.Close()
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if len(data) > 300 || strings.Contains(string(data), "secret") ||
+			!strings.Contains(string(data), rocksdbclient.ValueHash("secret")) {
+			t.Fatalf("unexpected journal file %s:\n%s", name, data)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only two rotated files, got %v", err)
+	}
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	if _, err := rocksdbclient.ReplayJournal(client, file); err == nil {
+		t.Fatal("expected redacted journal replay to fail")
+	}
+}
+
+func TestJournalReplayTransaction(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("t1")
+		case "commit_transaction", "rollback_transaction":
+			return ok("")
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+	path := filepath.Join(t.TempDir(), "journal.log")
+	journal, err := rocksdbclient.NewJournal(client, rocksdbclient.JournalOptions{Path: path})
+	if err != nil {
+		t.Fatalf("failed to open journal: %v", err)
+	}
+	err = client.WithTransaction(context.Background(), func(txn *rocksdbclient.Transaction) error {
+		return txn.Put("a", "1", nil)
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	journal.Close()
+
+	replayServer := newFakeServer(t, newFakeKV().handle)
+	replayClient := replayServer.client()
+	defer replayClient.Close()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open journal file: %v", err)
+	}
+	defer file.Close()
+	applied, err := rocksdbclient.ReplayJournal(replayClient, file)
+	if err != nil || applied != 1 {
+		t.Fatalf("unexpected replay result %d, %v", applied, err)
+	}
+	req := replayServer.received()[0]
+	if req.Action != "put" || req.Txn != nil || req.TxnID != nil {
+		t.Fatalf("expected the write to be replayed without a transaction, got %+v", req)
+	}
+}
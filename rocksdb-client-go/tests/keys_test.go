@@ -0,0 +1,57 @@
+package rocksdbclient_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListKeys(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a1", "a2", "b1"} {
+		kv.data[k] = "v"
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	result, err := client.ListKeys(1, 10, "a")
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	if !reflect.DeepEqual(result.Keys, []string{"a2"}) || result.Raw.Result != `["a2"]` {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	families, err := client.ColumnFamilies()
+	if err != nil {
+		t.Fatalf("failed to list column families: %v", err)
+	}
+	if !reflect.DeepEqual(families.Names, []string{"default"}) {
+		t.Fatalf("unexpected column families %v", families.Names)
+	}
+}
+
+func TestKeysStream(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"user:1", "user:2", "user:3", "user:4", "order:1"} {
+		kv.data[k] = "v"
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	stream := client.KeysStream("user:", 2)
+	var keys []string
+	for key := range stream.All() {
+		keys = append(keys, key)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"user:1", "user:2", "user:3", "user:4"}) {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	if pages := len(server.received()); pages != 2 {
+		t.Fatalf("expected 2 pages, got %d", pages)
+	}
+}
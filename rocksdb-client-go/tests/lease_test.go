@@ -0,0 +1,78 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestLeaseEphemeralKeys(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		attached   = map[string][]string{}
+		data       = map[string]string{}
+		keepAlives int
+	)
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		id := req.Options["lease_id"]
+		switch req.Action {
+		case "lease_grant":
+			attached["l1"] = nil
+			return ok(`{"id":"l1","ttl":1}`)
+		case "put":
+			if _, found := attached[id]; !found {
+				return fail("Lease not found")
+			}
+			attached[id] = append(attached[id], *req.Key)
+			data[*req.Key] = *req.Value
+			return ok("")
+		case "lease_keepalive":
+			keepAlives++
+			return ok("")
+		case "lease_revoke":
+			for _, key := range attached[id] {
+				delete(data, key)
+			}
+			delete(attached, id)
+			return ok("")
+		}
+		return fail("Unknown action")
+	})
+	client := server.client()
+	defer client.Close()
+
+	lease, err := client.GrantLease(time.Second)
+	if err != nil {
+		t.Fatalf("failed to grant lease: %v", err)
+	}
+	if lease.ID != "l1" || lease.TTL != time.Second {
+		t.Fatalf("unexpected lease %+v", lease)
+	}
+	if err := client.PutEphemeral("service:a", "10.0.0.1", lease.ID, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+
+	lease.Start()
+	time.Sleep(400 * time.Millisecond)
+	if err := lease.Revoke(); err != nil {
+		t.Fatalf("failed to revoke: %v", err)
+	}
+	mu.Lock()
+	if keepAlives == 0 {
+		t.Fatal("expected background keep-alives")
+	}
+	if _, found := data["service:a"]; found {
+		t.Fatal("expected ephemeral key to be removed with the lease")
+	}
+	mu.Unlock()
+
+	err = client.PutEphemeral("service:a", "10.0.0.1", lease.ID, nil)
+	if !errors.Is(err, rocksdbclient.ErrLeaseExpired) {
+		t.Fatalf("expected ErrLeaseExpired, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestAppendToList(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	if _, err := rocksdbclient.AppendToList(client, "events", "created", nil); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	list, err := rocksdbclient.AppendToList(client, "events", "updated", nil)
+	if err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	if !reflect.DeepEqual(list, []string{"created", "updated"}) {
+		t.Fatalf("unexpected list %v", list)
+	}
+	if req := server.received()[0]; req.Action != "merge" || *req.Value != `[{"op":"add","path":"/-","value":"created"}]` {
+		t.Fatalf("unexpected merge request %+v", req)
+	}
+
+	empty, err := rocksdbclient.GetList[string](client, "missing", nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected empty list, got %v, %v", empty, err)
+	}
+}
+
+func TestAddToSet(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	for _, tc := range []struct {
+		element int
+		added   bool
+	}{{1, true}, {2, true}, {1, false}} {
+		added, err := rocksdbclient.AddToSet(client, "ids", tc.element, nil)
+		if err != nil {
+			t.Fatalf("failed to add %d: %v", tc.element, err)
+		}
+		if added != tc.added {
+			t.Fatalf("element %d: expected added=%v", tc.element, tc.added)
+		}
+	}
+	if kv.data["ids"] != "[1,2]" {
+		t.Fatalf("unexpected set %q", kv.data["ids"])
+	}
+}
+
+func TestAddToSetGivesUpUnderContention(t *testing.T) {
+	kv := newFakeKV()
+	swaps := 0
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "compare_and_swap" {
+			// Another writer changes the set before every swap.
+			swaps++
+			kv.mu.Lock()
+			kv.data["ids"] = "[" + strconv.Itoa(-swaps) + "]"
+			kv.mu.Unlock()
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+	client.SetTransactionRetryPolicy(rocksdbclient.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+
+	_, err := rocksdbclient.AddToSet(client, "ids", 1, nil)
+	if !errors.Is(err, rocksdbclient.ErrTxnConflict) || swaps != 3 {
+		t.Fatalf("expected ErrTxnConflict after 3 attempts, got %v (swaps %d)", err, swaps)
+	}
+}
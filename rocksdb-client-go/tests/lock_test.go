@@ -0,0 +1,62 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestFencedWrites(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		issued  uint64
+		highest uint64
+		data    = map[string]string{}
+	)
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.Action {
+		case "acquire_lock":
+			issued++
+			return ok(`{"token":` + strconv.FormatUint(issued, 10) + `,"expires_at":"2030-01-01T00:00:00Z"}`)
+		case "put":
+			token, _ := strconv.ParseUint(req.Options["fence_token"], 10, 64)
+			if req.Options["fence_resource"] != "jobs" || token < highest {
+				return fail("Stale fencing token")
+			}
+			highest = token
+			data[*req.Key] = *req.Value
+			return ok("")
+		}
+		return fail("Unknown action")
+	})
+	client := server.client()
+	defer client.Close()
+
+	zombie, err := client.AcquireLock("jobs", time.Second)
+	if err != nil {
+		t.Fatalf("failed to acquire: %v", err)
+	}
+	current, err := client.AcquireLock("jobs", time.Second)
+	if err != nil {
+		t.Fatalf("failed to acquire: %v", err)
+	}
+	if current.Fencing.Token <= zombie.Fencing.Token {
+		t.Fatalf("expected increasing tokens, got %d then %d", zombie.Fencing.Token, current.Fencing.Token)
+	}
+
+	if err := current.Fenced().Put("k", "new", nil); err != nil {
+		t.Fatalf("failed to write with current token: %v", err)
+	}
+	if err := zombie.Fenced().Put("k", "old", nil); !errors.Is(err, rocksdbclient.ErrFenced) {
+		t.Fatalf("expected ErrFenced, got %v", err)
+	}
+	if data["k"] != "new" {
+		t.Fatalf("expected fenced write to be rejected, got %q", data["k"])
+	}
+}
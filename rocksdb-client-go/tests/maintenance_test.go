@@ -0,0 +1,90 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func maintenanceServer(t *testing.T, kv *fakeKV, maintenance *atomic.Bool) *fakeServer {
+	return newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if maintenance.Load() && req.Action != "get" {
+			return fail("Maintenance mode: writes are disabled")
+		}
+		return kv.handle(req)
+	})
+}
+
+func TestMaintenanceError(t *testing.T) {
+	var maintenance atomic.Bool
+	maintenance.Store(true)
+	kv := newFakeKV()
+	kv.data["k"] = "v"
+	server := maintenanceServer(t, kv, &maintenance)
+	client := server.client()
+	defer client.Close()
+
+	if _, err := client.Put(stringPtr("k"), stringPtr("v2"), nil, nil); !errors.Is(err, rocksdbclient.ErrMaintenance) {
+		t.Fatalf("expected ErrMaintenance, got %v", err)
+	}
+	if response, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil || response.Result != "v" {
+		t.Fatalf("expected reads to keep working, got %v, %v", response, err)
+	}
+}
+
+func TestMaintenanceQueue(t *testing.T) {
+	var maintenance atomic.Bool
+	maintenance.Store(true)
+	kv := newFakeKV()
+	server := maintenanceServer(t, kv, &maintenance)
+	client := server.client()
+	defer client.Close()
+	queue := rocksdbclient.NewMaintenanceQueue(client, rocksdbclient.MaintenanceQueueOptions{RetryInterval: 10 * time.Millisecond})
+	defer queue.Close()
+
+	for _, v := range []string{"1", "2", "3"} {
+		if _, err := client.Put(stringPtr("k"), stringPtr(v), nil, nil); !errors.Is(err, rocksdbclient.ErrWriteQueued) {
+			t.Fatalf("expected ErrWriteQueued, got %v", err)
+		}
+	}
+	if _, _, err := client.CAS("k", nil, "x", nil); errors.Is(err, rocksdbclient.ErrWriteQueued) {
+		t.Fatal("expected CAS not to be queued")
+	}
+	if n := queue.Len(); n != 3 {
+		t.Fatalf("expected 3 held writes, got %d", n)
+	}
+
+	maintenance.Store(false)
+	deadline := time.Now().Add(time.Second)
+	for queue.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := queue.Len(); n != 0 {
+		t.Fatalf("expected the queue to drain, %d writes left", n)
+	}
+	if response, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil || response.Result != "3" {
+		t.Fatalf("expected the last queued write to win, got %v, %v", response, err)
+	}
+}
+
+func TestMaintenanceQueueClose(t *testing.T) {
+	var maintenance atomic.Bool
+	maintenance.Store(true)
+	server := maintenanceServer(t, newFakeKV(), &maintenance)
+	client := server.client()
+	defer client.Close()
+	queue := rocksdbclient.NewMaintenanceQueue(client, rocksdbclient.MaintenanceQueueOptions{RetryInterval: time.Hour})
+
+	client.Delete(stringPtr("k"), nil, nil)
+	held := queue.Close()
+	if len(held) != 1 || held[0].Action != "delete" {
+		t.Fatalf("expected the held delete to be returned, got %+v", held)
+	}
+	_, err := client.Delete(stringPtr("k"), nil, nil)
+	if !errors.Is(err, rocksdbclient.ErrMaintenance) || errors.Is(err, rocksdbclient.ErrWriteQueued) {
+		t.Fatalf("expected ErrMaintenance after Close, got %v", err)
+	}
+}
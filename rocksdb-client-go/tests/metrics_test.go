@@ -0,0 +1,51 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestMetricsPerColumnFamily(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	metrics := rocksdbclient.NewMetrics(client)
+
+	client.Put(stringPtr("a"), stringPtr("1"), nil, nil)
+	client.Put(stringPtr("b"), stringPtr("2"), stringPtr("users"), nil)
+	client.Put(stringPtr("c"), stringPtr("3"), stringPtr("users"), nil)
+	client.Get(stringPtr("missing"), stringPtr("users"), nil, nil)
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("expected 3 series, got %+v", snapshot)
+	}
+	want := []struct {
+		cf, action       string
+		requests, errors int64
+	}{
+		{"default", "put", 1, 0},
+		{"users", "get", 1, 1},
+		{"users", "put", 2, 0},
+	}
+	for i, w := range want {
+		s := snapshot[i]
+		if s.CF != w.cf || s.Action != w.action || s.Requests != w.requests || s.Errors != w.errors {
+			t.Fatalf("series %d: expected %+v, got %+v", i, w, s)
+		}
+		var bucketed int64
+		for _, n := range s.Buckets {
+			bucketed += n
+		}
+		if bucketed != s.Requests || s.MaxLatency <= 0 || s.MeanLatency() > s.MaxLatency {
+			t.Fatalf("series %d: inconsistent latency stats %+v", i, s)
+		}
+	}
+
+	metrics.Reset()
+	if n := len(metrics.Snapshot()); n != 0 {
+		t.Fatalf("expected no series after Reset, got %d", n)
+	}
+}
@@ -0,0 +1,142 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// lockingKV adds acquire_lock and release_lock to fakeKV.
+func lockingKV(kv *fakeKV, held *bool) func(rocksdbclient.Request) rocksdbclient.Response {
+	return func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "acquire_lock":
+			if *held {
+				return fail("Lock is held")
+			}
+			*held = true
+			return ok(`{"token":1,"expires_at":"2030-01-01T00:00:00Z"}`)
+		case "release_lock":
+			*held = false
+			return ok("")
+		}
+		return kv.handle(req)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["user:1"] = "alice"
+	kv.data["user:2"] = "bob"
+	held := false
+	server := newFakeServer(t, lockingKV(kv, &held))
+	client := server.client()
+	defer client.Close()
+
+	renameUsers := func(ctx context.Context, tx *rocksdbclient.MigrationTx) error {
+		keys, err := tx.Client.ListAllKeys("user:")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys.Keys {
+			response, err := tx.Client.Get(&key, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+			if err := tx.Put("users/"+strings.TrimPrefix(key, "user:"), response.Result, nil); err != nil {
+				return err
+			}
+			if err := tx.Delete(key, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	migrator, err := rocksdbclient.NewMigrator(client, rocksdbclient.MigratorOptions{},
+		rocksdbclient.Migration{Version: 1, Name: "rename users", Up: renameUsers},
+		rocksdbclient.Migration{Version: 2, Name: "add flag", Up: func(ctx context.Context, tx *rocksdbclient.MigrationTx) error {
+			return tx.Put("flag", "on", nil)
+		}},
+	)
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+
+	runs, err := migrator.DryRun(context.Background())
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(runs) != 2 || len(runs[0].Planned) != 4 || runs[0].Planned[0].Key != "users/1" || len(runs[1].Planned) != 1 {
+		t.Fatalf("unexpected dry run %+v", runs)
+	}
+	if _, found := kv.data["users/1"]; found || held {
+		t.Fatal("dry run must not write or lock")
+	}
+
+	held = true
+	if _, err := migrator.Up(context.Background()); !errors.Is(err, rocksdbclient.ErrLockHeld) {
+		t.Fatalf("expected ErrLockHeld, got %v", err)
+	}
+	held = false
+
+	runs, err = migrator.Up(context.Background())
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("unexpected migration result %+v, %v", runs, err)
+	}
+	if kv.data["users/2"] != "bob" || kv.data["flag"] != "on" || kv.data["__schema_version"] != "2" || held {
+		t.Fatalf("unexpected data after migration %v (lock held %v)", kv.data, held)
+	}
+	if _, found := kv.data["user:1"]; found {
+		t.Fatal("expected old keys to be removed")
+	}
+	for _, req := range server.received() {
+		if req.Action == "put" && req.Options["fence_token"] != "1" {
+			t.Fatalf("migration write without fencing token: %+v", req)
+		}
+	}
+	if runs, err := migrator.Up(context.Background()); err != nil || len(runs) != 0 {
+		t.Fatalf("expected nothing pending, got %+v, %v", runs, err)
+	}
+
+	if _, err := rocksdbclient.NewMigrator(client, rocksdbclient.MigratorOptions{},
+		rocksdbclient.Migration{Version: 2, Up: renameUsers},
+		rocksdbclient.Migration{Version: 1, Up: renameUsers},
+	); err == nil {
+		t.Fatal("expected out-of-order migrations to be rejected")
+	}
+}
+
+func TestMigratorPanic(t *testing.T) {
+	kv := newFakeKV()
+	held := false
+	server := newFakeServer(t, lockingKV(kv, &held))
+	client := server.client()
+	defer client.Close()
+
+	migrator, err := rocksdbclient.NewMigrator(client, rocksdbclient.MigratorOptions{},
+		rocksdbclient.Migration{Version: 1, Name: "add flag", Up: func(ctx context.Context, tx *rocksdbclient.MigrationTx) error {
+			return tx.Put("flag", "on", nil)
+		}},
+		rocksdbclient.Migration{Version: 2, Name: "broken", Up: func(ctx context.Context, tx *rocksdbclient.MigrationTx) error {
+			panic("boom")
+		}},
+	)
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+
+	runs, err := migrator.Up(context.Background())
+	var panicErr *rocksdbclient.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Fatalf("expected a PanicError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "migration 2 (broken)") {
+		t.Fatalf("expected the error to name the migration, got %v", err)
+	}
+	if len(runs) != 1 || kv.data["__schema_version"] != "1" || held {
+		t.Fatalf("expected version 1 to be stored and the lock released, got %+v, %v (lock held %v)", runs, kv.data, held)
+	}
+}
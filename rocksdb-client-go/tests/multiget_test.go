@@ -0,0 +1,83 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestMultiGet(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["a"] = "1"
+	kv.data["c"] = "3"
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action != "multi_get" {
+			return fail("Unknown action")
+		}
+		result := map[string]*string{}
+		for _, k := range req.Keys {
+			if v, found := kv.data[k]; found {
+				result[k] = &v
+			} else {
+				result[k] = nil
+			}
+		}
+		data, _ := json.Marshal(result)
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	values, err := client.MultiGet([]string{"a", "b", "c"}, nil)
+	if err != nil {
+		t.Fatalf("failed to multi get: %v", err)
+	}
+	if len(server.received()) != 1 {
+		t.Fatalf("expected a single round trip, got %d", len(server.received()))
+	}
+	if values["a"] == nil || *values["a"] != "1" || values["c"] == nil || *values["c"] != "3" {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if v, found := values["b"]; !found || v != nil {
+		t.Fatalf("expected nil entry for missing key, got %v", v)
+	}
+}
+
+func TestMultiGetCF(t *testing.T) {
+	data := map[rocksdbclient.CFKey]string{
+		{Key: "u1", CF: "users"}:  "alice",
+		{Key: "o1", CF: "orders"}: "book",
+	}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action != "multi_get_cf" {
+			return fail("Unknown action")
+		}
+		var cfs []string
+		if err := json.Unmarshal([]byte(req.Options["cf_names"]), &cfs); err != nil || len(cfs) != len(req.Keys) || req.Value != nil {
+			return fail("Invalid keys")
+		}
+		result := make([]*string, len(req.Keys))
+		for i, key := range req.Keys {
+			if v, found := data[rocksdbclient.CFKey{Key: key, CF: cfs[i]}]; found {
+				result[i] = &v
+			}
+		}
+		encoded, _ := json.Marshal(result)
+		return ok(string(encoded))
+	})
+	client := server.client()
+	defer client.Close()
+
+	keys := []rocksdbclient.CFKey{{Key: "u1", CF: "users"}, {Key: "o1", CF: "orders"}, {Key: "u1", CF: "orders"}}
+	values, err := client.MultiGetCF(keys)
+	if err != nil {
+		t.Fatalf("failed to multi-get: %v", err)
+	}
+	if *values[keys[0]] != "alice" || *values[keys[1]] != "book" || values[keys[2]] != nil {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if len(server.received()) != 1 {
+		t.Fatalf("expected a single round trip, got %d", len(server.received()))
+	}
+}
@@ -0,0 +1,43 @@
+package rocksdbclient_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["tenant:1:tenant:42:x"] = "other tenant"
+	kv.data["tenant:420:user:9"] = "similar prefix"
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	tenant := client.Namespace("tenant:42:")
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		if err := tenant.Put(key, "v-"+key, nil); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+	if kv.data["tenant:42:user:1"] != "v-user:1" {
+		t.Fatalf("expected prefixed key, got %v", kv.data)
+	}
+	if value, err := tenant.Get("order:1", nil); err != nil || value != "v-order:1" {
+		t.Fatalf("expected v-order:1, got %q, %v", value, err)
+	}
+
+	keys, err := tenant.Keys("user:")
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	if err := tenant.Delete("user:1", nil); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, found := kv.data["tenant:42:user:1"]; found {
+		t.Fatal("expected prefixed key to be deleted")
+	}
+}
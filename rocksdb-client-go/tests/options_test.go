@@ -0,0 +1,64 @@
+package rocksdbclient_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("v")
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(),
+		rocksdbclient.WithToken("secret"),
+		rocksdbclient.WithDefaultCF("users"),
+	)
+	defer client.Close()
+
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if _, err := client.Get(stringPtr("k"), stringPtr("orders"), nil, nil); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+
+	received := server.received()
+	if received[0].Token == nil || *received[0].Token != "secret" {
+		t.Fatalf("expected token, got %+v", received[0])
+	}
+	if *received[0].CfName != "users" || *received[1].CfName != "orders" {
+		t.Fatalf("unexpected column families %q, %q", *received[0].CfName, *received[1].CfName)
+	}
+}
+
+func TestNewClientLogger(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := rocksdbclient.NewClient(addr,
+		rocksdbclient.WithTimeout(50*time.Millisecond),
+		rocksdbclient.WithRetryInterval(10*time.Millisecond),
+		rocksdbclient.WithLogger(logger),
+	)
+	defer client.Close()
+
+	if err := client.Connect(); err == nil || errors.Is(err, rocksdbclient.ErrTimeout) {
+		t.Fatalf("expected connection error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "connect failed, retrying") {
+		t.Fatalf("expected retry to be logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,98 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCursorEncoding(t *testing.T) {
+	token := rocksdbclient.EncodeCursor("user:\x00/42", 7)
+	key, hash, err := rocksdbclient.DecodeCursor(token)
+	if err != nil || key != "user:\x00/42" || hash != 7 {
+		t.Fatalf("unexpected decode %q, %d, %v", key, hash, err)
+	}
+	for _, bad := range []string{"!!", "AAAA"} {
+		if _, _, err := rocksdbclient.DecodeCursor(bad); !errors.Is(err, rocksdbclient.ErrInvalidCursor) {
+			t.Fatalf("%q: expected ErrInvalidCursor, got %v", bad, err)
+		}
+	}
+}
+
+func TestScanPage(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a", "user:1", "user:2", "user:3", "user:4", "user:5", "v"} {
+		kv.data[k] = "v-" + k
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	for _, tc := range []struct {
+		opts rocksdbclient.ScanOptions
+		want [][]string
+	}{
+		{rocksdbclient.ScanOptions{Prefix: "user:", Limit: 2}, [][]string{{"user:1", "user:2"}, {"user:3", "user:4"}, {"user:5"}}},
+		{rocksdbclient.ScanOptions{Prefix: "user:", Limit: 3, Reverse: true}, [][]string{{"user:5", "user:4", "user:3"}, {"user:2", "user:1"}}},
+		{rocksdbclient.ScanOptions{Prefix: "user:", Limit: 5}, [][]string{{"user:1", "user:2", "user:3", "user:4", "user:5"}}},
+	} {
+		var pages [][]string
+		cursor := ""
+		for {
+			page, err := client.ScanPage(tc.opts, cursor)
+			if err != nil {
+				t.Fatalf("%+v: scan page failed: %v", tc.opts, err)
+			}
+			var keys []string
+			for _, e := range page.Items {
+				keys = append(keys, e.Key)
+			}
+			pages = append(pages, keys)
+			if !page.HasMore() {
+				break
+			}
+			cursor = page.Next
+		}
+		if !reflect.DeepEqual(pages, tc.want) {
+			t.Fatalf("%+v: expected %v, got %v", tc.opts, tc.want, pages)
+		}
+	}
+
+	page, _ := client.ScanPage(rocksdbclient.ScanOptions{Prefix: "user:", Limit: 1}, "")
+	if _, err := client.ScanPage(rocksdbclient.ScanOptions{Prefix: "order:", Limit: 1}, page.Next); !errors.Is(err, rocksdbclient.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for a foreign cursor, got %v", err)
+	}
+}
+
+func TestKeysPage(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"user:1", "user:2", "user:3", "order:1"} {
+		kv.data[k] = "v"
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	page, err := client.KeysPage("user:", 2, "")
+	if err != nil || !reflect.DeepEqual(page.Items, []string{"user:1", "user:2"}) || !page.HasMore() {
+		t.Fatalf("unexpected first page %+v, %v", page, err)
+	}
+
+	stream := client.ResumeKeysStream("user:", page.Next, 2)
+	var keys []string
+	for key := range stream.All() {
+		keys = append(keys, key)
+	}
+	if err := stream.Err(); err != nil || !reflect.DeepEqual(keys, []string{"user:3"}) {
+		t.Fatalf("unexpected resumed keys %v, %v", keys, err)
+	}
+
+	stream = client.ResumeKeysStream("order:", page.Next, 2)
+	for range stream.All() {
+	}
+	if !errors.Is(stream.Err(), rocksdbclient.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", stream.Err())
+	}
+}
@@ -0,0 +1,96 @@
+package rocksdbclient_test
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestSplitRangesAndParallelScan(t *testing.T) {
+	kv := newFakeKV()
+	for i := 0; i < 40; i++ {
+		kv.data[fmt.Sprintf("user:%02d", i)] = "v"
+	}
+	// Most of the database is outside the prefix.
+	for i := 0; i < 200; i++ {
+		kv.data[fmt.Sprintf("other:%03d", i)] = "v"
+	}
+	server := newFakeServer(t, kv.handle)
+
+	pool := rocksdbclient.NewPool(3, server.client)
+	defer pool.Close()
+
+	client := pool.Get()
+	ranges, err := client.SplitRanges("user:", 4)
+	pool.Put(client)
+	if err != nil {
+		t.Fatalf("failed to split ranges: %v", err)
+	}
+	if len(ranges) != 4 {
+		t.Fatalf("expected 4 ranges, got %+v", ranges)
+	}
+	if ranges[0].Start != "user:" || ranges[len(ranges)-1].End != "user;" {
+		t.Fatalf("ranges do not cover the prefix: %+v", ranges)
+	}
+
+	var mu sync.Mutex
+	var seen []string
+	err = pool.ParallelScan(ranges, func(key, value string) error {
+		mu.Lock()
+		seen = append(seen, key)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("parallel scan failed: %v", err)
+	}
+
+	for _, r := range ranges {
+		n := 0
+		for _, key := range seen {
+			if r.Contains(key) {
+				n++
+			}
+		}
+		if n != 10 {
+			t.Fatalf("expected balanced ranges, %+v holds %d keys", r, n)
+		}
+	}
+
+	sort.Strings(seen)
+	if len(seen) != 40 {
+		t.Fatalf("expected 40 keys, got %d: %v", len(seen), seen)
+	}
+	for i, key := range seen {
+		if key != fmt.Sprintf("user:%02d", i) {
+			t.Fatalf("unexpected key %q at %d", key, i)
+		}
+	}
+}
+
+func TestSplitRangesRefinesSkewedKeys(t *testing.T) {
+	kv := newFakeKV()
+	for i := 0; i < 40; i++ {
+		kv.data[fmt.Sprintf("user:1%02d", i)] = "v"
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	ranges, err := client.SplitRanges("user:", 4)
+	if err != nil {
+		t.Fatalf("failed to split ranges: %v", err)
+	}
+	want := []string{"user:", "user:11", "user:12", "user:13"}
+	if len(ranges) != len(want) {
+		t.Fatalf("expected %d ranges, got %+v", len(want), ranges)
+	}
+	for i, r := range ranges {
+		if r.Start != want[i] {
+			t.Fatalf("expected range %d to start at %q, got %+v", i, want[i], ranges)
+		}
+	}
+}
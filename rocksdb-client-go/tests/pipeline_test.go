@@ -0,0 +1,120 @@
+package rocksdbclient_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// batchServer waits for n requests on a connection before answering any of
+// them, which only a pipelining client can satisfy. With echo set the
+// responses carry the request IDs and are sent in reverse order.
+func batchServer(t *testing.T, n int, echo bool) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		var batch []rocksdbclient.Request
+		for len(batch) < n {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var req rocksdbclient.Request
+			json.Unmarshal(line, &req)
+			batch = append(batch, req)
+		}
+		for i := range batch {
+			req := batch[i]
+			response := ok(*req.Key)
+			if echo {
+				req = batch[n-1-i]
+				response = rocksdbclient.Response{Success: true, Result: *req.Key, RequestID: req.RequestID}
+			}
+			writeLine(conn, response)
+		}
+		reader.ReadBytes('\n')
+	}()
+	return listener.Addr().String()
+}
+
+func TestPipelining(t *testing.T) {
+	for _, echo := range []bool{true, false} {
+		t.Run(fmt.Sprintf("echo=%v", echo), func(t *testing.T) {
+			const n = 5
+			client := rocksdbclient.NewClient(batchServer(t, n, echo),
+				rocksdbclient.WithPipelining(),
+				rocksdbclient.WithRequestTimeout(2*time.Second),
+			)
+			defer client.Close()
+
+			var wg sync.WaitGroup
+			for i := range n {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					key := fmt.Sprintf("k%d", i)
+					response, err := client.Get(&key, nil, nil, nil)
+					if err != nil || response.Result != key {
+						t.Errorf("expected %s, got %v, %v", key, response, err)
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestPipeliningTimeout(t *testing.T) {
+	client := rocksdbclient.NewClient(batchServer(t, 2, true),
+		rocksdbclient.WithPipelining(),
+		rocksdbclient.WithRequestTimeout(50*time.Millisecond),
+	)
+	defer client.Close()
+
+	// The server holds the first request back until the second arrives, so
+	// it times out without breaking the connection for the second one.
+	if _, err := client.Get(stringPtr("a"), nil, nil, nil); err == nil {
+		t.Fatal("expected the first request to time out")
+	}
+	response, err := client.Get(stringPtr("b"), nil, nil, nil)
+	if err != nil || response.Result != "b" {
+		t.Fatalf("expected the second request on the same connection, got %v, %v", response, err)
+	}
+}
+
+func TestPipeliningAfterHandshake(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "hello" {
+			return ok(`{"client_id":"c-1"}`)
+		}
+		time.Sleep(300 * time.Millisecond)
+		return rocksdbclient.Response{Success: true, Result: "v", RequestID: req.RequestID}
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(),
+		rocksdbclient.WithPipelining(),
+		rocksdbclient.WithClientInfo("billing", ""),
+		rocksdbclient.WithTimeout(200*time.Millisecond),
+	)
+	defer client.Close()
+
+	// The handshake's deadline must not apply to the responses read later.
+	if response, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil || response.Result != "v" {
+		t.Fatalf("expected a response after the handshake deadline, got %v, %v", response, err)
+	}
+}
@@ -0,0 +1,38 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestSupportedActions(t *testing.T) {
+	actions := rocksdbclient.SupportedActions()
+	byName := map[string]rocksdbclient.ActionInfo{}
+	for _, a := range actions {
+		if _, dup := byName[a.Name]; dup {
+			t.Fatalf("duplicate action %q", a.Name)
+		}
+		byName[a.Name] = a
+	}
+
+	put, found := byName[rocksdbclient.ActionPut]
+	if !found || !put.Mutates {
+		t.Fatalf("expected put to be a mutating action, got %+v", put)
+	}
+	if get := byName[rocksdbclient.ActionGet]; get.Mutates {
+		t.Fatal("expected get not to mutate")
+	}
+	hasTTL := false
+	for _, option := range put.Options {
+		hasTTL = hasTTL || option == rocksdbclient.OptionTTL
+	}
+	if !hasTTL {
+		t.Fatalf("expected put to accept %q, got %v", rocksdbclient.OptionTTL, put.Options)
+	}
+
+	actions[0].Options[0] = "changed"
+	if rocksdbclient.SupportedActions()[0].Options[0] == "changed" {
+		t.Fatal("expected SupportedActions to return a copy")
+	}
+}
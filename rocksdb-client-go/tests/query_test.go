@@ -0,0 +1,77 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestQuery(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`{"entries":[{"key":"user:2","value":"{\"age\":30}"}],"cursor":"c1"}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	q := rocksdbclient.NewQuery().
+		Prefix("user:").
+		Where("$.age", rocksdbclient.FilterGte, 18).
+		Where("$.email", rocksdbclient.FilterExists, nil).
+		OrderDesc().
+		Limit(10)
+	page, err := client.Query(q)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Key != "user:2" || !page.HasMore() {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	req := server.received()[0]
+	want := map[string]string{
+		"prefix": "user:",
+		"filter": `[{"path":"$.age","op":"gte","value":18},{"path":"$.email","op":"exists"}]`,
+		"order":  "desc",
+		"limit":  "10",
+	}
+	for k, v := range want {
+		if req.Options[k] != v {
+			t.Fatalf("option %s: expected %q, got %q", k, v, req.Options[k])
+		}
+	}
+
+	if _, err := client.Query(q.Cursor(page.Next)); err != nil {
+		t.Fatalf("next page failed: %v", err)
+	}
+	if cursor := server.received()[1].Options["cursor"]; cursor != "c1" {
+		t.Fatalf("expected server cursor c1, got %q", cursor)
+	}
+	other := rocksdbclient.NewQuery().Prefix("order:").Cursor(page.Next)
+	if _, err := client.Query(other); !errors.Is(err, rocksdbclient.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for a foreign cursor, got %v", err)
+	}
+}
+
+func TestQueryValidation(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`{"entries":[]}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	invalid := []*rocksdbclient.Query{
+		rocksdbclient.NewQuery().Where("age", rocksdbclient.FilterEq, 1),
+		rocksdbclient.NewQuery().Where("$.age", "like", 1),
+		rocksdbclient.NewQuery().Where("$.fn", rocksdbclient.FilterEq, func() {}),
+		rocksdbclient.NewQuery().Limit(-1),
+	}
+	for i, q := range invalid {
+		if _, err := client.Query(q); err == nil {
+			t.Fatalf("query %d: expected validation error", i)
+		}
+	}
+	if len(server.received()) != 0 {
+		t.Fatal("expected invalid queries not to be sent")
+	}
+}
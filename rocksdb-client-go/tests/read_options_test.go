@@ -0,0 +1,51 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestReadOptions(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "multi_get":
+			return ok(`{"a":"1"}`)
+		case "create_iterator":
+			return ok("1")
+		}
+		return ok("v")
+	})
+	client := server.client()
+	defer client.Close()
+
+	paranoid := rocksdbclient.WithReadOptions(rocksdbclient.ReadOptions{VerifyChecksums: true})
+	if _, err := client.GetWith("k", nil, paranoid); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	cached := rocksdbclient.WithReadOptions(rocksdbclient.ReadOptions{Tier: rocksdbclient.ReadTierBlockCache})
+	if _, err := client.MultiGetWith([]string{"a"}, nil, cached); err != nil {
+		t.Fatalf("multi_get failed: %v", err)
+	}
+	it, err := client.NewIteratorWithOptions(rocksdbclient.IteratorOptions{Read: rocksdbclient.ReadOptions{NoFillCache: true}})
+	if err != nil {
+		t.Fatalf("create_iterator failed: %v", err)
+	}
+	it.Close()
+
+	want := []map[string]string{
+		{"verify_checksums": "true"},
+		{"read_tier": "block_cache"},
+		{"fill_cache": "false"},
+	}
+	for i, req := range server.received()[:3] {
+		if len(req.Options) != len(want[i]) {
+			t.Fatalf("%s: expected options %v, got %v", req.Action, want[i], req.Options)
+		}
+		for k, v := range want[i] {
+			if req.Options[k] != v {
+				t.Fatalf("%s: expected options %v, got %v", req.Action, want[i], req.Options)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestRegistry(t *testing.T) {
+	kv := newFakeKV()
+	leased := map[string]bool{}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "lease_grant":
+			return ok(`{"id":"l1","ttl":10}`)
+		case "lease_keepalive":
+			return ok("")
+		case "lease_revoke":
+			for key := range leased {
+				kv.handle(rocksdbclient.Request{Action: "delete", Key: &key})
+			}
+			return ok("")
+		case "put":
+			if req.Options["lease_id"] == "l1" {
+				leased[*req.Key] = true
+			}
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+
+	registry := rocksdbclient.NewRegistry(client, rocksdbclient.RegistryOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := registry.Watch(ctx, "api", 10*time.Millisecond)
+	if addrs := <-updates; len(addrs) != 0 {
+		t.Fatalf("expected no instances, got %v", addrs)
+	}
+
+	for _, addr := range []string{"10.0.0.2:80", "10.0.0.1:80"} {
+		if err := registry.Register("api", addr); err != nil {
+			t.Fatalf("failed to register: %v", err)
+		}
+	}
+	if err := registry.Register("apiv2", "10.0.0.3:80"); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if !strings.HasPrefix(*server.received()[len(server.received())-1].Key, "services/apiv2/") {
+		t.Fatal("expected registration under the services prefix")
+	}
+
+	want := []string{"10.0.0.1:80", "10.0.0.2:80"}
+	addrs, err := registry.Discover("api")
+	if err != nil || !reflect.DeepEqual(addrs, want) {
+		t.Fatalf("expected %v, got %v, %v", want, addrs, err)
+	}
+	for addrs := range updates {
+		if len(addrs) == 2 {
+			if !reflect.DeepEqual(addrs, want) {
+				t.Fatalf("expected %v, got %v", want, addrs)
+			}
+			break
+		}
+	}
+
+	if err := registry.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if addrs, err := registry.Discover("api"); err != nil || len(addrs) != 0 {
+		t.Fatalf("expected registrations to be removed, got %v, %v", addrs, err)
+	}
+}
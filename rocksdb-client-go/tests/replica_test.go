@@ -0,0 +1,118 @@
+package rocksdbclient_test
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// replicaHandler answers reads with name and rejects reads that require a
+// sequence number above applied.
+func replicaHandler(name string, applied uint64) func(rocksdbclient.Request) rocksdbclient.Response {
+	return func(req rocksdbclient.Request) rocksdbclient.Response {
+		if minSeq, _ := strconv.ParseUint(req.Options["min_seq"], 10, 64); minSeq > applied {
+			return fail("Replica behind: applied " + strconv.FormatUint(applied, 10))
+		}
+		switch req.Action {
+		case "get_latest_sequence_number":
+			return ok("7")
+		case "begin_transaction":
+			return ok("txn")
+		case "get", "exists":
+			return ok(name)
+		}
+		return ok("")
+	}
+}
+
+func TestReplicaRouter(t *testing.T) {
+	primary := newFakeServer(t, replicaHandler("primary", 7))
+	replica1 := newFakeServer(t, replicaHandler("replica1", 0))
+	replica2 := newFakeServer(t, replicaHandler("replica2", 0))
+	client := primary.client()
+	defer client.Close()
+
+	router := rocksdbclient.NewReplicaRouter(client, []string{
+		replica1.listener.Addr().String(),
+		replica2.listener.Addr().String(),
+	}, rocksdbclient.ReplicaOptions{})
+	defer router.Close()
+
+	var served []string
+	for i := 0; i < 4; i++ {
+		v, err := client.Get(stringPtr("k"), nil, nil, nil)
+		if err != nil {
+			t.Fatalf("get failed: %v", err)
+		}
+		served = append(served, v.Result)
+	}
+	if served[0] != "replica1" || served[1] != "replica2" || served[2] != "replica1" {
+		t.Fatalf("expected round-robin reads, got %v", served)
+	}
+	if _, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if len(primary.received()) != 1 || primary.received()[0].Action != "put" {
+		t.Fatalf("expected only the write on the primary, got %+v", primary.received())
+	}
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if v, err := txn.Get("k", nil); err != nil || v != "primary" {
+		t.Fatalf("expected transactional read on the primary, got %q, %v", v, err)
+	}
+}
+
+func TestReplicaRouterReadYourWrites(t *testing.T) {
+	primary := newFakeServer(t, replicaHandler("primary", 7))
+	replica := newFakeServer(t, replicaHandler("replica", 5))
+	client := primary.client()
+	defer client.Close()
+
+	router := rocksdbclient.NewReplicaRouter(client, []string{replica.listener.Addr().String()},
+		rocksdbclient.ReplicaOptions{ReadYourWrites: true})
+	defer router.Close()
+
+	if v, _ := client.Get(stringPtr("k"), nil, nil, nil); v == nil || v.Result != "replica" {
+		t.Fatalf("expected the read on the replica before any write, got %+v", v)
+	}
+	if _, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if v, _ := client.Get(stringPtr("k"), nil, nil, nil); v == nil || v.Result != "primary" {
+		t.Fatalf("expected the lagging replica to be bypassed, got %+v", v)
+	}
+	last := replica.received()[len(replica.received())-1]
+	if last.Options["min_seq"] != "7" {
+		t.Fatalf("expected min_seq 7, got %+v", last.Options)
+	}
+}
+
+func TestReplicaRouterUnreachable(t *testing.T) {
+	primary := newFakeServer(t, replicaHandler("primary", 0))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	client := primary.client()
+	defer client.Close()
+
+	router := rocksdbclient.NewReplicaRouter(client, []string{addr}, rocksdbclient.ReplicaOptions{
+		Strategy:      rocksdbclient.LowestLatency,
+		ClientOptions: []rocksdbclient.Option{rocksdbclient.WithTimeout(20 * time.Millisecond)},
+	})
+	defer router.Close()
+
+	for i := 0; i < 2; i++ {
+		if v, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil || v.Result != "primary" {
+			t.Fatalf("expected fallback to the primary, got %+v, %v", v, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCFRoutes(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "multi_get" {
+			return ok("{}")
+		}
+		return ok("")
+	})
+	addr := server.listener.Addr().String()
+	client := rocksdbclient.NewClient(addr,
+		rocksdbclient.WithDefaultCF("main"),
+		rocksdbclient.WithCFRoutes(
+			rocksdbclient.CFRoute{Prefix: "session:", CF: "sessions"},
+			rocksdbclient.CFRoute{Prefix: "session:admin:", CF: "admin"},
+		),
+	)
+	defer client.Close()
+
+	client.Put(stringPtr("session:1"), stringPtr("v"), nil, nil)
+	client.Get(stringPtr("session:admin:1"), nil, nil, nil)
+	client.Delete(stringPtr("user:1"), nil, nil)
+	client.Put(stringPtr("session:2"), stringPtr("v"), stringPtr("explicit"), nil)
+	client.BatchWrite([]rocksdbclient.Operation{
+		{Type: rocksdbclient.OpPut, Key: "session:3", Value: stringPtr("v")},
+		{Type: rocksdbclient.OpDelete, Key: "user:2"},
+	})
+	if _, err := client.MultiGet([]string{"session:4", "session:5"}, nil); err != nil {
+		t.Fatalf("failed to multi_get routed keys: %v", err)
+	}
+	if _, err := client.MultiGet([]string{"session:4", "user:5"}, nil); err == nil {
+		t.Fatal("expected multi_get across column families to fail")
+	}
+
+	want := []string{"sessions", "admin", "main", "explicit", "main", "sessions"}
+	received := server.received()
+	if len(received) != len(want) {
+		t.Fatalf("expected %d requests, got %d", len(want), len(received))
+	}
+	for i, req := range received {
+		if req.CfName == nil || *req.CfName != want[i] {
+			t.Fatalf("request %d (%s): expected column family %s, got %v", i, req.Action, want[i], req.CfName)
+		}
+	}
+	ops := received[4].Operations
+	if *ops[0].CfName != "sessions" || ops[1].CfName != nil {
+		t.Fatalf("unexpected batch routing %+v", ops)
+	}
+	if cf := client.RouteCF("session:admin:x"); cf == nil || *cf != "admin" {
+		t.Fatalf("expected longest prefix to win, got %v", cf)
+	}
+}
+
+func TestCFRoutesBatchWriteKeepsOperationCF(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	addr := server.listener.Addr().String()
+	ops := []rocksdbclient.Operation{{Type: rocksdbclient.OpDelete, Key: "user:1"}}
+	for _, opts := range [][]rocksdbclient.Option{
+		{rocksdbclient.WithDefaultCF("main")},
+		{rocksdbclient.WithDefaultCF("main"), rocksdbclient.WithCFRoutes(rocksdbclient.CFRoute{Prefix: "session:", CF: "sessions"})},
+	} {
+		client := rocksdbclient.NewClient(addr, opts...)
+		if _, err := client.BatchWrite(ops); err != nil {
+			t.Fatalf("failed to batch write: %v", err)
+		}
+		client.Close()
+	}
+	for _, req := range server.received() {
+		if req.Operations[0].CfName != nil {
+			t.Fatalf("expected the operation to keep its column family, got %v", *req.Operations[0].CfName)
+		}
+	}
+}
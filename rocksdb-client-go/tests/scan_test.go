@@ -0,0 +1,51 @@
+package rocksdbclient_test
+
+import (
+	"reflect"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestScan(t *testing.T) {
+	kv := newFakeKV()
+	for _, k := range []string{"a", "user:1", "user:2", "user:3", "user:4", "v"} {
+		kv.data[k] = "v-" + k
+	}
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	keys := func(entries []rocksdbclient.KeyValue) []string {
+		var keys []string
+		for _, e := range entries {
+			keys = append(keys, e.Key)
+		}
+		return keys
+	}
+
+	cases := []struct {
+		opts rocksdbclient.ScanOptions
+		want []string
+	}{
+		{rocksdbclient.ScanOptions{Prefix: "user:"}, []string{"user:1", "user:2", "user:3", "user:4"}},
+		{rocksdbclient.ScanOptions{Prefix: "user:", Start: "user:2", End: "user:4"}, []string{"user:2", "user:3"}},
+		{rocksdbclient.ScanOptions{Prefix: "user:", Reverse: true, Limit: 3}, []string{"user:4", "user:3", "user:2"}},
+		{rocksdbclient.ScanOptions{End: "user:2", Reverse: true}, []string{"user:1", "a"}},
+		{rocksdbclient.ScanOptions{Reverse: true, Limit: 1}, []string{"v"}},
+	}
+	for _, tc := range cases {
+		entries, err := client.Scan(tc.opts)
+		if err != nil {
+			t.Fatalf("%+v: scan failed: %v", tc.opts, err)
+		}
+		if got := keys(entries); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%+v: expected %v, got %v", tc.opts, tc.want, got)
+		}
+	}
+
+	entries, _ := client.Scan(rocksdbclient.ScanOptions{Prefix: "user:1"})
+	if len(entries) != 1 || entries[0].Value != "v-user:1" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
@@ -0,0 +1,69 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCapabilityCheck(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "hello":
+			if req.Options["protocol_version"] != "1" {
+				return fail("missing protocol version")
+			}
+			return ok(`{"client_id":"c-1","protocol_version":1,"server_version":"0.9.0","actions":["hello","get"]}`)
+		case "get":
+			return ok("v")
+		}
+		return fail("Unknown action")
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(), rocksdbclient.WithCapabilityCheck())
+	defer client.Close()
+
+	if _, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	_, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil)
+	if !errors.Is(err, rocksdbclient.ErrUnsupportedAction) {
+		t.Fatalf("expected ErrUnsupportedAction, got %v", err)
+	}
+	if n := len(server.received()); n != 2 {
+		t.Fatalf("expected the put not to be sent, got %d requests", n)
+	}
+
+	info, err := client.ServerInfo()
+	if err != nil {
+		t.Fatalf("server info failed: %v", err)
+	}
+	if info.ProtocolVersion != 1 || info.Version != "0.9.0" || !info.Supports("get") || info.Supports("put") {
+		t.Fatalf("unexpected server info %+v", info)
+	}
+	if n := len(server.received()); n != 2 {
+		t.Fatalf("expected the handshake to be reused, got %d requests", n)
+	}
+}
+
+func TestServerInfoOldServer(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get" {
+			return ok("v")
+		}
+		return fail("Unknown action")
+	})
+	client := server.client()
+	defer client.Close()
+
+	info, err := client.ServerInfo()
+	if err != nil {
+		t.Fatalf("expected servers without hello to be tolerated, got %v", err)
+	}
+	if info.ProtocolVersion != 0 || !info.Supports("put") {
+		t.Fatalf("unexpected server info %+v", info)
+	}
+	if _, err := client.Put(stringPtr("k"), stringPtr("v"), nil, nil); !errors.Is(err, rocksdbclient.ErrUnsupportedAction) {
+		t.Fatalf("expected unknown actions to map to ErrUnsupportedAction, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestSetOptions(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	cfOptions := map[string]string{"write_buffer_size": "134217728"}
+	if err := client.SetOptions(stringPtr("logs"), cfOptions); err != nil {
+		t.Fatalf("set_options failed: %v", err)
+	}
+	dbOptions := map[string]string{"max_background_jobs": "8"}
+	if err := client.SetDBOptions(dbOptions); err != nil {
+		t.Fatalf("set_db_options failed: %v", err)
+	}
+	if err := client.SetDBOptions(nil); err == nil {
+		t.Fatal("expected an error for empty options")
+	}
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for i, want := range []map[string]string{cfOptions, dbOptions} {
+		var sent map[string]string
+		json.Unmarshal([]byte(*requests[i].Value), &sent)
+		if !reflect.DeepEqual(sent, want) {
+			t.Fatalf("%s: expected %v, got %v", requests[i].Action, want, sent)
+		}
+	}
+	if requests[0].Action != "set_options" || *requests[0].CfName != "logs" || requests[1].Action != "set_db_options" {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+}
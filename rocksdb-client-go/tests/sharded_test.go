@@ -0,0 +1,79 @@
+package rocksdbclient_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestShardedClient(t *testing.T) {
+	var kvs []*fakeKV
+	var endpoints []string
+	for i := 0; i < 3; i++ {
+		kv := newFakeKV()
+		kvs = append(kvs, kv)
+		endpoints = append(endpoints, newFakeServer(t, kv.handle).listener.Addr().String())
+	}
+	sharded, err := rocksdbclient.NewShardedClient(endpoints, nil)
+	if err != nil {
+		t.Fatalf("failed to create sharded client: %v", err)
+	}
+	defer sharded.Close()
+
+	var keys []string
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user:%03d", i)
+		keys = append(keys, key)
+		if err := sharded.Put(key, "v"+key, nil); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	total := 0
+	for i, kv := range kvs {
+		if len(kv.data) == 0 {
+			t.Fatalf("shard %d received no keys", i)
+		}
+		total += len(kv.data)
+	}
+	if total != 100 {
+		t.Fatalf("expected every key on exactly one shard, got %d entries", total)
+	}
+	if v, err := sharded.Get("user:042", nil); err != nil || v != "vuser:042" {
+		t.Fatalf("unexpected get %q, %v", v, err)
+	}
+
+	values, err := sharded.MultiGet(append(keys[:10:10], "missing"), nil)
+	if err != nil {
+		t.Fatalf("multi get failed: %v", err)
+	}
+	if len(values) != 11 || values["missing"] != nil || values["user:007"] == nil || *values["user:007"] != "vuser:007" {
+		t.Fatalf("unexpected multi get result %v", values)
+	}
+
+	all, err := sharded.Keys("user:")
+	if err != nil {
+		t.Fatalf("keys failed: %v", err)
+	}
+	if !slices.Equal(all, keys) {
+		t.Fatalf("expected all keys in order, got %d keys", len(all))
+	}
+
+	reversed := slices.Clone(endpoints)
+	slices.Reverse(reversed)
+	other, err := rocksdbclient.NewShardedClient(reversed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	for _, key := range keys {
+		if sharded.Shard(key).Endpoints()[0] != other.Shard(key).Endpoints()[0] {
+			t.Fatalf("key %q routed differently depending on endpoint order", key)
+		}
+	}
+
+	if _, err := rocksdbclient.NewShardedClient([]string{endpoints[0], endpoints[0]}, nil); err == nil {
+		t.Fatal("expected duplicate endpoints to be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestSLOTracker(t *testing.T) {
+	calls := 0
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		calls++
+		switch {
+		case req.Action == "put" && calls%4 == 0:
+			return fail("IO error: No space left on device")
+		case req.Action == "get" && *req.Key == "slow":
+			time.Sleep(30 * time.Millisecond)
+		case req.Action == "get" && *req.Key == "missing":
+			return fail("Key not found")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+	tracker := rocksdbclient.NewSLOTracker(client, rocksdbclient.SLOOptions{
+		Targets: []rocksdbclient.SLOTarget{
+			{Action: "get", SuccessRate: 0.9, Latency: 20 * time.Millisecond},
+			{SuccessRate: 0.99},
+		},
+		Windows: []time.Duration{time.Hour, time.Minute},
+	})
+
+	for range 8 {
+		client.Put(stringPtr("k"), stringPtr("v"), nil, nil)
+	}
+	for _, key := range []string{"a", "missing", "slow", "b"} {
+		client.Get(stringPtr(key), nil, nil, nil)
+	}
+
+	stats := tracker.Stats()
+	if len(stats) != 2 || stats[0].Action != "get" || stats[1].Action != "put" {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	get, put := stats[0], stats[1]
+	if get.Total != 4 || get.Bad != 1 || get.SuccessRate != 0.75 || get.Met() {
+		t.Fatalf("unexpected get status %+v", get)
+	}
+	if put.Total != 8 || put.Bad != 2 || put.Target.SuccessRate != 0.99 {
+		t.Fatalf("unexpected put status %+v", put)
+	}
+	if len(put.Windows) != 2 || put.Windows[0].Window != time.Minute || put.Windows[0].Total != 8 {
+		t.Fatalf("unexpected windows %+v", put.Windows)
+	}
+	if burn := put.Windows[1].BurnRate; math.Abs(burn-25) > 1e-9 {
+		t.Fatalf("expected a burn rate of 25, got %v", burn)
+	}
+	if math.Abs(get.BudgetRemaining-(-1.5)) > 1e-9 {
+		t.Fatalf("expected the get budget to be exceeded, got %v", get.BudgetRemaining)
+	}
+
+	if rocksdbclient.IsSLOFailure(rocksdbclient.ErrKeyNotFound) || !rocksdbclient.IsSLOFailure(errors.New("connection refused")) {
+		t.Fatal("unexpected IsSLOFailure classification")
+	}
+	tracker.Reset()
+	if len(tracker.Stats()) != 0 {
+		t.Fatal("expected no stats after reset")
+	}
+}
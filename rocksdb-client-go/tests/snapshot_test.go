@@ -0,0 +1,67 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestRetainedSnapshots(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "retain_snapshot":
+			return ok(`{"label":"` + req.Options["label"] + `","seq":42,"created_at":"2026-01-31T23:59:59Z"}`)
+		case "list_snapshots":
+			return ok(`[{"label":"end-of-month","seq":42,"created_at":"2026-01-31T23:59:59Z"}]`)
+		case "get":
+			if req.Options["snapshot"] != "end-of-month" {
+				return fail("Snapshot not found: " + req.Options["snapshot"])
+			}
+			return ok("old")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	snapshot, err := client.RetainSnapshot("end-of-month", 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("retain failed: %v", err)
+	}
+	if snapshot.Label != "end-of-month" || snapshot.Seq != 42 || snapshot.CreatedAt.Month() != time.January {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if v, err := client.GetAt("end-of-month", "k", nil); err != nil || v != "old" {
+		t.Fatalf("unexpected read %q, %v", v, err)
+	}
+	if _, err := client.GetAt("missing", "k", nil); !errors.Is(err, rocksdbclient.ErrSnapshotNotFound) {
+		t.Fatalf("expected ErrSnapshotNotFound, got %v", err)
+	}
+	snapshots, err := client.ListSnapshots()
+	if err != nil || len(snapshots) != 1 || snapshots[0].Label != "end-of-month" {
+		t.Fatalf("unexpected snapshots %+v, %v", snapshots, err)
+	}
+	if err := client.SetSnapshotGCPolicy(rocksdbclient.SnapshotGCPolicy{MaxSnapshots: 12, MaxAge: time.Hour}); err != nil {
+		t.Fatalf("set policy failed: %v", err)
+	}
+	if err := client.ReleaseSnapshot("end-of-month"); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+
+	requests := server.received()
+	if requests[0].Options["ttl"] != "7776000" {
+		t.Fatalf("unexpected retain request %+v", requests[0])
+	}
+	policy := requests[4]
+	if policy.Action != "set_snapshot_policy" || policy.Options["max_snapshots"] != "12" || policy.Options["max_age"] != "3600" {
+		t.Fatalf("unexpected policy request %+v", policy)
+	}
+	if release := requests[5]; release.Action != "release_snapshot" || release.Options["label"] != "end-of-month" {
+		t.Fatalf("unexpected release request %+v", release)
+	}
+	if _, err := client.RetainSnapshot("", 0); err == nil {
+		t.Fatal("expected an empty label to be rejected")
+	}
+}
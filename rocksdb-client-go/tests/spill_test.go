@@ -0,0 +1,74 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestAllSpilled(t *testing.T) {
+	var all []string
+	for i := 0; i < 25; i++ {
+		all = append(all, fmt.Sprintf("key\n%02d", i))
+	}
+
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "multi_get" {
+			values := map[string]string{}
+			for _, key := range req.Keys {
+				values[key] = "value of " + key
+			}
+			data, _ := json.Marshal(values)
+			return ok(string(data))
+		}
+		start, _ := strconv.Atoi(req.Options["start"])
+		limit, _ := strconv.Atoi(req.Options["limit"])
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		if start > end {
+			start = end
+		}
+		data, _ := json.Marshal(all[start:end])
+		return ok(string(data))
+	})
+	client := server.client()
+	defer client.Close()
+
+	store, err := client.AllSpilled("", rocksdbclient.SpillOptions{Dir: t.TempDir(), PageSize: 10})
+	if err != nil {
+		t.Fatalf("failed to spill keys: %v", err)
+	}
+	defer store.Close()
+
+	if store.Len() != len(all) {
+		t.Fatalf("expected %d records, got %d", len(all), store.Len())
+	}
+	if got := len(server.received()); got != 6 {
+		t.Fatalf("expected 3 pages of keys and values, got %d requests", got)
+	}
+
+	it, err := store.Iterator()
+	if err != nil {
+		t.Fatalf("failed to open iterator: %v", err)
+	}
+	defer it.Close()
+
+	var i int
+	for it.Next() {
+		if it.Key() != all[i] || it.Value() != "value of "+all[i] {
+			t.Fatalf("expected %q, got %q = %q", all[i], it.Key(), it.Value())
+		}
+		i++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterator failed: %v", err)
+	}
+	if i != len(all) {
+		t.Fatalf("expected %d keys, got %d", len(all), i)
+	}
+}
@@ -0,0 +1,52 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestStat(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if *req.Key != "k" {
+			return fail("Key not found")
+		}
+		return ok(`{"key":"k","size":42,"sequence":1234,"modified_at":"2024-05-01T10:00:00Z"}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	stat, err := client.Stat("k", nil)
+	if err != nil {
+		t.Fatalf("failed to stat: %v", err)
+	}
+	if stat.Size != 42 || stat.Sequence != 1234 || stat.ModifiedAt == nil || stat.CreatedAt != nil {
+		t.Fatalf("unexpected stat %+v", stat)
+	}
+	if _, err := client.Stat("missing", nil); !errors.Is(err, rocksdbclient.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["k"] = "large value"
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		_, found := kv.data[*req.Key]
+		return ok(strconv.FormatBool(found))
+	})
+	client := server.client()
+	defer client.Close()
+
+	for key, want := range map[string]bool{"k": true, "missing": false} {
+		exists, err := client.Exists(key, nil)
+		if err != nil || exists != want {
+			t.Fatalf("%s: expected %v, got %v, %v", key, want, exists, err)
+		}
+	}
+	if action := server.received()[0].Action; action != "exists" {
+		t.Fatalf("unexpected action %q", action)
+	}
+}
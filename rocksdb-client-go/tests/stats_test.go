@@ -0,0 +1,57 @@
+package rocksdbclient_test
+
+import (
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestStats(t *testing.T) {
+	properties := map[string]string{
+		"rocksdb.cur-size-all-mem-tables":           "4096",
+		"rocksdb.block-cache-usage":                 "8192",
+		"rocksdb.estimate-pending-compaction-bytes": "100",
+		"rocksdb.num-running-compactions":           "1",
+		"rocksdb.estimate-num-keys":                 "42",
+		"rocksdb.num-files-at-level0":               "3",
+		"rocksdb.num-files-at-level2":               "9",
+	}
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if value, found := properties[*req.Value]; found {
+			return ok(value)
+		}
+		return ok("0")
+	})
+	client := server.client()
+	defer client.Close()
+
+	stats, err := client.Stats(nil)
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if stats.MemtableSize != 4096 || stats.BlockCacheUsage != 8192 || stats.PendingCompactionBytes != 100 ||
+		stats.RunningCompactions != 1 || stats.EstimatedKeys != 42 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.LevelFiles[0] != 3 || stats.LevelFiles[2] != 9 || stats.LevelFiles[6] != 0 {
+		t.Fatalf("unexpected level files %v", stats.LevelFiles)
+	}
+	for _, req := range server.received() {
+		if req.Action != "get_property" || !strings.HasPrefix(*req.Value, "rocksdb.") {
+			t.Fatalf("unexpected request %+v", req)
+		}
+	}
+}
+
+func TestStatsMalformedProperty(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("n/a")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if _, err := client.Stats(nil); err == nil || !strings.Contains(err.Error(), "rocksdb.cur-size-all-mem-tables") {
+		t.Fatalf("expected a decode error naming the property, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package rocksdbclient_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// streamServer accepts stream connections and hands each request to serve,
+// which writes the acknowledgement and events itself.
+func streamServer(t *testing.T, serve func(conn net.Conn, req rocksdbclient.Request)) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadBytes('\n')
+				if err != nil {
+					return
+				}
+				var req rocksdbclient.Request
+				json.Unmarshal(line, &req)
+				serve(conn, req)
+			}()
+		}
+	}()
+	return listener
+}
+
+func writeLine(conn net.Conn, v any) {
+	data, _ := json.Marshal(v)
+	conn.Write(append(data, '\n'))
+}
+
+func TestSubscribe(t *testing.T) {
+	requests := make(chan rocksdbclient.Request, 4)
+	listener := streamServer(t, func(conn net.Conn, req rocksdbclient.Request) {
+		requests <- req
+		writeLine(conn, ok(""))
+		value := "v"
+		if req.Options["since_seq"] == "" {
+			// Drop the connection after two events to force a reconnect.
+			writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpPut, Key: "user:1", Value: &value, Seq: 10})
+			writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpDelete, Key: "user:2", Seq: 11})
+			return
+		}
+		writeLine(conn, rocksdbclient.ChangeEvent{Type: rocksdbclient.OpMerge, Key: "user:3", Value: &value, Seq: 12})
+		time.Sleep(time.Second)
+	})
+	client := rocksdbclient.NewClient(listener.Addr().String(), rocksdbclient.WithRetryInterval(10*time.Millisecond))
+	defer client.Close()
+
+	sub, err := client.Subscribe(context.Background(), "user:")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	var keys []string
+	for event := range sub.Events() {
+		keys = append(keys, event.Key)
+		if len(keys) == 3 {
+			break
+		}
+	}
+	sub.Close()
+	if len(keys) != 3 || keys[2] != "user:3" || sub.LastSeq() != 12 || sub.Err() != nil {
+		t.Fatalf("unexpected events %v (last seq %d, err %v)", keys, sub.LastSeq(), sub.Err())
+	}
+
+	first, second := <-requests, <-requests
+	if first.Action != "subscribe" || first.Options["prefix"] != "user:" {
+		t.Fatalf("unexpected subscribe request %+v", first)
+	}
+	if second.Options["since_seq"] != "12" {
+		t.Fatalf("expected resume after seq 11, got %+v", second.Options)
+	}
+}
+
+func TestSubscribeRejected(t *testing.T) {
+	listener := streamServer(t, func(conn net.Conn, req rocksdbclient.Request) {
+		writeLine(conn, fail("Unauthorized"))
+	})
+	client := rocksdbclient.NewClient(listener.Addr().String())
+	defer client.Close()
+
+	if _, err := client.Subscribe(context.Background(), ""); !errors.Is(err, rocksdbclient.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestSubscribeContext(t *testing.T) {
+	listener := streamServer(t, func(conn net.Conn, req rocksdbclient.Request) {
+		writeLine(conn, ok(""))
+		time.Sleep(time.Second)
+	})
+	client := rocksdbclient.NewClient(listener.Addr().String())
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := client.Subscribe(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	cancel()
+	select {
+	case _, open := <-sub.Events():
+		if open {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("subscription did not end with its context")
+	}
+}
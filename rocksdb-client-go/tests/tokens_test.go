@@ -0,0 +1,58 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestCreateToken(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(`{"id":"t1","name":"ci","secret":"s3cret","scopes":[{"access":"read","cf_name":"users"}],"created_at":"2024-01-02T03:04:05Z"}`)
+	})
+	client := server.client()
+	defer client.Close()
+
+	scopes := []rocksdbclient.TokenScope{{Access: rocksdbclient.AccessRead, ColumnFamily: "users"}}
+	token, err := client.CreateToken("ci", scopes, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	if token.ID != "t1" || token.Secret != "s3cret" || len(token.Scopes) != 1 || token.Scopes[0] != scopes[0] {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	req := server.received()[0]
+	if req.Action != "create_token" || req.Value == nil {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	var payload struct {
+		Name      string                     `json:"name"`
+		Scopes    []rocksdbclient.TokenScope `json:"scopes"`
+		ExpiresAt *time.Time                 `json:"expires_at"`
+	}
+	if err := json.Unmarshal([]byte(*req.Value), &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Name != "ci" || len(payload.Scopes) != 1 || payload.ExpiresAt == nil {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.RevokeToken("t1"); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+	req := server.received()[0]
+	if req.Action != "revoke_token" || *req.Value != `{"id":"t1"}` {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
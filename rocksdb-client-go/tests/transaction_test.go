@@ -0,0 +1,137 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestTransactionHandle(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("txn-7")
+		case "get":
+			return ok("value")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if txn.ID() != "txn-7" {
+		t.Fatalf("unexpected transaction id %q", txn.ID())
+	}
+	if err := txn.Put("k", "v", nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if v, err := txn.Get("k", nil); err != nil || v != "value" {
+		t.Fatalf("unexpected get result %q, %v", v, err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := txn.Put("k", "v", nil); !errors.Is(err, rocksdbclient.ErrTxnDone) {
+		t.Fatalf("expected ErrTxnDone, got %v", err)
+	}
+
+	for _, req := range server.received()[1:] {
+		if req.TxnID == nil || *req.TxnID != "txn-7" || req.Txn == nil || !*req.Txn {
+			t.Fatalf("request %s is not scoped to the transaction", req.Action)
+		}
+	}
+}
+
+func TestWithTransactionRetriesConflicts(t *testing.T) {
+	var commits int
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("txn")
+		case "commit_transaction":
+			commits++
+			if commits < 3 {
+				return fail("Resource busy: ")
+			}
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+	client.SetTransactionRetryPolicy(rocksdbclient.RetryPolicy{MaxRetries: 5, InitialBackoff: time.Millisecond})
+
+	var attempts int
+	err := client.WithTransaction(context.Background(), func(txn *rocksdbclient.Transaction) error {
+		attempts++
+		return txn.Put("k", "v", nil)
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	var rollbacks int
+	for _, req := range server.received() {
+		if req.Action == "rollback_transaction" {
+			rollbacks++
+		}
+	}
+	if rollbacks != 2 {
+		t.Fatalf("expected failed attempts to be rolled back, got %d rollbacks", rollbacks)
+	}
+
+	boom := errors.New("boom")
+	err = client.WithTransaction(context.Background(), func(txn *rocksdbclient.Transaction) error {
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+}
+
+func TestLockConflictError(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("txn-1")
+		case "put":
+			return fail("Operation timed out: Timeout waiting to lock key (holder=txn-9)")
+		case "get":
+			return fail("Deadlock")
+		}
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	cf := "users"
+	err = txn.Put("k", "v", &cf)
+	var conflict *rocksdbclient.LockConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *LockConflictError, got %v", err)
+	}
+	if conflict.Key != "k" || conflict.CF != "users" || conflict.TxnID != "txn-1" || conflict.Holder != "txn-9" || conflict.Deadlock {
+		t.Fatalf("unexpected conflict %+v", conflict)
+	}
+	if !errors.Is(err, rocksdbclient.ErrLockConflict) || !errors.Is(err, rocksdbclient.ErrTxnConflict) {
+		t.Fatalf("conflict does not match the sentinels: %v", err)
+	}
+
+	_, err = txn.GetForUpdate("k", nil)
+	if !errors.As(err, &conflict) || !conflict.Deadlock || conflict.Holder != "" {
+		t.Fatalf("expected deadlock conflict, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package rocksdbclient_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+var _ rocksdbclient.ValueTransformer = xorTransformer(0)
+
+// xorTransformer flips every byte with a fixed mask.
+type xorTransformer byte
+
+func (x xorTransformer) Name() string { return "xor" }
+
+func (x xorTransformer) Encode(value []byte) ([]byte, error) {
+	out := make([]byte, len(value))
+	for i, b := range value {
+		out[i] = b ^ byte(x)
+	}
+	return out, nil
+}
+
+func (x xorTransformer) Decode(value []byte) ([]byte, error) {
+	return x.Encode(value)
+}
+
+func TestValueTransformers(t *testing.T) {
+	kv := newFakeKV()
+	kv.data["legacy"] = "plain"
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	client.UseValueTransformers(xorTransformer(0x5a))
+
+	if _, err := client.Put(stringPtr("k"), stringPtr("secret"), nil, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if stored := kv.data["k"]; !strings.HasPrefix(stored, "\x1evt1:xor:") || strings.Contains(stored, "secret") {
+		t.Fatalf("expected transformed value, got %q", stored)
+	}
+
+	for key, want := range map[string]string{"k": "secret", "legacy": "plain"} {
+		response, err := client.Get(stringPtr(key), nil, nil, nil)
+		if err != nil || response.Result != want {
+			t.Fatalf("expected %q for %s, got %v, %v", want, key, response, err)
+		}
+	}
+
+	it, err := client.NewIterator()
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	defer it.Close()
+	if !it.Seek("k") || it.Key() != "k" || it.Value() != "secret" {
+		t.Fatalf("unexpected iterator entry %q=%q (%v)", it.Key(), it.Value(), it.Err())
+	}
+	if !it.SeekForPrev("k") || it.Key() != "k" || it.Value() != "secret" {
+		t.Fatalf("unexpected reverse iterator entry %q=%q (%v)", it.Key(), it.Value(), it.Err())
+	}
+}
+
+func TestCompressionTransformer(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+	client.UseValueTransformers(rocksdbclient.NewCompressionTransformer(rocksdbclient.CompressionOptions{Threshold: 64}))
+
+	large := strings.Repeat("compressible ", 100)
+	values := map[string]string{"small": "tiny", "large": large}
+	for key, value := range values {
+		if _, err := client.Put(stringPtr(key), stringPtr(value), nil, nil); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+
+	if kv.data["small"] != "tiny" {
+		t.Fatalf("expected small value to be stored as is, got %q", kv.data["small"])
+	}
+	if stored := kv.data["large"]; !strings.HasPrefix(stored, "\x1evt1:snappy:") || len(stored) >= len(large) {
+		t.Fatalf("expected compressed value, got %d bytes", len(stored))
+	}
+	for key, want := range values {
+		response, err := client.Get(stringPtr(key), nil, nil, nil)
+		if err != nil || response.Result != want {
+			t.Fatalf("unexpected value for %s: %v", key, err)
+		}
+	}
+}
+
+func TestCompressionTransformerSnappy(t *testing.T) {
+	transformer := rocksdbclient.NewCompressionTransformer(rocksdbclient.CompressionOptions{Threshold: 1})
+
+	// "abcd" as a literal, then a 12 byte copy at offset 4 in the 4 byte
+	// offset form, which the encoder does not emit itself.
+	block := []byte{16, 3 << 2, 'a', 'b', 'c', 'd', 11<<2 | 3, 4, 0, 0, 0}
+	decoded, err := transformer.Decode(block)
+	if err != nil || string(decoded) != "abcdabcdabcdabcd" {
+		t.Fatalf("unexpected decoded block %q, %v", decoded, err)
+	}
+	for _, corrupt := range [][]byte{block[:len(block)-1], {16, 11<<2 | 2, 4, 0}, {0xff}} {
+		if _, err := transformer.Decode(corrupt); err == nil {
+			t.Fatalf("expected %v to be rejected", corrupt)
+		}
+	}
+
+	random := make([]byte, 5000)
+	for i := range random {
+		random[i] = byte(i * 7919 >> 3)
+	}
+	for _, value := range [][]byte{
+		[]byte(strings.Repeat("a", 100000)),
+		[]byte(strings.Repeat("0123456789abcdef", 300) + strings.Repeat("x", 3000) + strings.Repeat("0123456789abcdef", 300)),
+		append(random, random...),
+	} {
+		encoded, err := transformer.Encode(value)
+		if err != nil {
+			t.Fatalf("failed to encode %d bytes: %v", len(value), err)
+		}
+		decoded, err := transformer.Decode(encoded)
+		if err != nil || string(decoded) != string(value) {
+			t.Fatalf("round trip of %d bytes failed: %v", len(value), err)
+		}
+	}
+}
+
+func TestValueTransformersCompareAndSwap(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "get" && req.Options["if_modified_since"] != "" {
+			data, _ := json.Marshal(map[string]any{"value": kv.data[*req.Key], "sequence": 5})
+			return ok(string(data))
+		}
+		if req.Action == "put" && req.Options["return_old"] == "true" {
+			old, _ := json.Marshal(map[string]string{"old": kv.data[*req.Key]})
+			kv.handle(req)
+			return ok(string(old))
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+	client.UseValueTransformers(xorTransformer(0x5a))
+
+	if swapped, _, err := client.CAS("k", nil, "1", nil); err != nil || !swapped {
+		t.Fatalf("failed to create with CAS: %v, %v", swapped, err)
+	}
+	if !strings.HasPrefix(kv.data["k"], "\x1evt1:xor:") {
+		t.Fatalf("expected transformed value, got %q", kv.data["k"])
+	}
+	swapped, current, err := client.CAS("k", stringPtr("0"), "2", nil)
+	if err != nil || swapped || current == nil || *current != "1" {
+		t.Fatalf("expected a failed swap reporting 1, got %v, %v, %v", swapped, current, err)
+	}
+	if swapped, _, err := client.CAS("k", stringPtr("1"), "2", nil); err != nil || !swapped {
+		t.Fatalf("expected the swap to match the encoded value: %v, %v", swapped, err)
+	}
+
+	increment := func(existing *string) (string, error) {
+		n, _ := strconv.Atoi(*existing)
+		return strconv.Itoa(n + 1), nil
+	}
+	if value, err := client.Upsert("k", increment, nil); err != nil || value != "3" {
+		t.Fatalf("expected 3, got %q, %v", value, err)
+	}
+
+	result, err := client.PutWith("k", "4", nil, rocksdbclient.WithReturnOld())
+	if err != nil || result.Old == nil || *result.Old != "3" {
+		t.Fatalf("expected the decoded old value, got %+v, %v", result, err)
+	}
+	read, err := client.GetWith("k", nil, rocksdbclient.WithIfModifiedSince(1))
+	if err != nil || read.Value != "4" || read.Sequence != 5 {
+		t.Fatalf("expected the decoded conditional read, got %+v, %v", read, err)
+	}
+}
@@ -0,0 +1,49 @@
+package rocksdbclient_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestPutWithTTL(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.PutWithTTL("session", "abc", 1500*time.Millisecond, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	req := server.received()[0]
+	if req.Action != "put" || *req.Key != "session" || *req.Value != "abc" || req.Options["ttl"] != "2" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	if err := client.PutWithTTL("session", "abc", 0, nil); err == nil {
+		t.Fatal("expected error for zero ttl")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		return ok(strconv.Itoa(len(req.Keys) - 1))
+	})
+	client := server.client()
+	defer client.Close()
+
+	touched, err := client.Touch([]string{"s1", "s2", "gone"}, 30*time.Minute, nil)
+	if err != nil {
+		t.Fatalf("failed to touch: %v", err)
+	}
+	if touched != 2 {
+		t.Fatalf("expected 2 touched keys, got %d", touched)
+	}
+	received := server.received()
+	if len(received) != 1 || received[0].Action != "touch" || received[0].Options["ttl"] != "1800" || len(received[0].Keys) != 3 {
+		t.Fatalf("unexpected requests %+v", received)
+	}
+}
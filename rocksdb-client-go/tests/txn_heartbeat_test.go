@@ -0,0 +1,118 @@
+package rocksdbclient_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// keepAliveCounter answers transaction requests and counts keep-alives.
+type keepAliveCounter struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (k *keepAliveCounter) handle(req rocksdbclient.Request) rocksdbclient.Response {
+	switch req.Action {
+	case "begin_transaction":
+		return ok("txn-1")
+	case "transaction_keepalive":
+		if req.TxnID == nil || *req.TxnID != "txn-1" {
+			return fail("Transaction not found")
+		}
+		k.mu.Lock()
+		k.count++
+		k.mu.Unlock()
+	}
+	return ok("")
+}
+
+func (k *keepAliveCounter) get() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.count
+}
+
+func TestTransactionHeartbeat(t *testing.T) {
+	counter := &keepAliveCounter{}
+	server := newFakeServer(t, counter.handle)
+	client := server.client()
+	defer client.Close()
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	txn.StartHeartbeat(context.Background(), rocksdbclient.HeartbeatOptions{Interval: 10 * time.Millisecond})
+	time.Sleep(60 * time.Millisecond)
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	sent := counter.get()
+	if sent == 0 {
+		t.Fatal("expected keep-alives while the transaction was open")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if counter.get() != sent {
+		t.Fatal("heartbeat kept running after commit")
+	}
+	if err := txn.HeartbeatErr(); err != nil {
+		t.Fatalf("unexpected heartbeat error: %v", err)
+	}
+}
+
+func TestTransactionHeartbeatStops(t *testing.T) {
+	server := newFakeServer(t, (&keepAliveCounter{}).handle)
+	client := server.client()
+	defer client.Close()
+
+	txn, err := client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	txn.StartHeartbeat(context.Background(), rocksdbclient.HeartbeatOptions{
+		Interval:    5 * time.Millisecond,
+		MaxLifetime: 20 * time.Millisecond,
+	})
+	time.Sleep(50 * time.Millisecond)
+	if err := txn.HeartbeatErr(); !errors.Is(err, rocksdbclient.ErrTxnMaxLifetime) {
+		t.Fatalf("expected ErrTxnMaxLifetime, got %v", err)
+	}
+	txn.Rollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txn, err = client.BeginTransaction()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	txn.StartHeartbeat(ctx, rocksdbclient.HeartbeatOptions{Interval: 5 * time.Millisecond})
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	if err := txn.HeartbeatErr(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	txn.Rollback()
+}
+
+func TestWithTransactionHeartbeat(t *testing.T) {
+	counter := &keepAliveCounter{}
+	server := newFakeServer(t, counter.handle)
+	client := rocksdbclient.NewClient(server.listener.Addr().String(),
+		rocksdbclient.WithTransactionHeartbeat(rocksdbclient.HeartbeatOptions{Interval: 5 * time.Millisecond}))
+	defer client.Close()
+
+	err := client.WithTransaction(context.Background(), func(txn *rocksdbclient.Transaction) error {
+		time.Sleep(40 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	if counter.get() == 0 {
+		t.Fatal("expected keep-alives during WithTransaction")
+	}
+}
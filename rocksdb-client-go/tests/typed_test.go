@@ -0,0 +1,35 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+type employee struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func TestGetAsPutAs(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	in := employee{FirstName: "john", LastName: "doe"}
+	if err := rocksdbclient.PutAs(client, "emp:1", in, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if kv.data["emp:1"] != `{"first_name":"john","last_name":"doe"}` {
+		t.Fatalf("unexpected stored value %q", kv.data["emp:1"])
+	}
+
+	out, err := rocksdbclient.GetAs[employee](client, "emp:1", nil)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if out != in {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
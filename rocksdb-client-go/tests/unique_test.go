@@ -0,0 +1,42 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestEnsureUnique(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		switch req.Action {
+		case "begin_transaction":
+			return ok("txn")
+		case "commit_transaction", "rollback_transaction":
+			return ok("")
+		}
+		return kv.handle(req)
+	})
+	client := server.client()
+	defer client.Close()
+
+	if err := client.EnsureUnique("idx", "email", "a@example.com", "user:1"); err != nil {
+		t.Fatalf("failed to claim: %v", err)
+	}
+	if owner := kv.data["email:a@example.com"]; owner != "user:1" {
+		t.Fatalf("expected index entry for user:1, got %q", owner)
+	}
+	if err := client.EnsureUnique("idx", "email", "a@example.com", "user:1"); err != nil {
+		t.Fatalf("expected reclaiming by the owner to succeed, got %v", err)
+	}
+	if err := client.EnsureUnique("idx", "email", "a@example.com", "user:2"); !errors.Is(err, rocksdbclient.ErrNotUnique) {
+		t.Fatalf("expected ErrNotUnique, got %v", err)
+	}
+
+	for _, req := range server.received() {
+		if req.Action == "get" && (req.Options["for_update"] != "true" || *req.CfName != "idx" || req.TxnID == nil) {
+			t.Fatalf("expected a locking read in idx within the transaction, got %+v", req)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package rocksdbclient_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+var errNoEmail = errors.New("missing email")
+
+func TestValidators(t *testing.T) {
+	kv := newFakeKV()
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	client.UseValidators(
+		rocksdbclient.ValidationRule{Prefix: "user:", Validator: rocksdbclient.ValidJSON},
+		rocksdbclient.ValidationRule{Prefix: "user:", Validator: rocksdbclient.ValidatorFunc(func(key, value string) error {
+			if !strings.Contains(value, `"email"`) {
+				return errNoEmail
+			}
+			return nil
+		})},
+		rocksdbclient.ValidationRule{CF: "blobs", Validator: rocksdbclient.MaxValueSize(4)},
+	)
+
+	if _, err := client.Put(stringPtr("user:1"), stringPtr(`{"email":"a@b"}`), nil, nil); err != nil {
+		t.Fatalf("failed to put valid value: %v", err)
+	}
+	if _, err := client.Put(stringPtr("other"), stringPtr("not json"), nil, nil); err != nil {
+		t.Fatalf("expected unmatched key to pass, got %v", err)
+	}
+
+	_, err := client.Put(stringPtr("user:2"), stringPtr("{broken"), nil, nil)
+	var validationErr *rocksdbclient.ValidationError
+	if !errors.Is(err, rocksdbclient.ErrValidation) || !errors.As(err, &validationErr) ||
+		validationErr.Key != "user:2" || validationErr.CF != "default" || validationErr.Action != "put" {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := client.Merge(stringPtr("user:3"), stringPtr(`{"name":"x"}`), nil, nil); !errors.Is(err, errNoEmail) {
+		t.Fatalf("expected merge to be rejected by the second rule, got %v", err)
+	}
+	if _, err := client.Put(stringPtr("big"), stringPtr("12345"), stringPtr("blobs"), nil); !errors.Is(err, rocksdbclient.ErrValidation) {
+		t.Fatalf("expected oversized value to be rejected, got %v", err)
+	}
+
+	_, err = client.BatchWrite([]rocksdbclient.Operation{
+		{Type: rocksdbclient.OpPut, Key: "user:4", Value: stringPtr(`{"email":"c@d"}`)},
+		{Type: rocksdbclient.OpDelete, Key: "user:1"},
+		{Type: rocksdbclient.OpPut, Key: "blob", Value: stringPtr("123456"), CfName: stringPtr("blobs")},
+	})
+	if !errors.As(err, &validationErr) || validationErr.Key != "blob" || validationErr.CF != "blobs" {
+		t.Fatalf("expected batch to be rejected, got %v", err)
+	}
+	if _, found := kv.data["user:4"]; found {
+		t.Fatal("rejected batch must not be sent")
+	}
+
+	for _, req := range server.received() {
+		if req.Key != nil && (*req.Key == "user:2" || *req.Key == "user:3" || *req.Key == "big") {
+			t.Fatalf("rejected write reached the server: %+v", req)
+		}
+	}
+}
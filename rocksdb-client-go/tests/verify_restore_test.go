@@ -0,0 +1,61 @@
+package rocksdbclient_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestVerifyRestore(t *testing.T) {
+	kv := newFakeKV()
+	for i := 0; i < 10; i++ {
+		kv.data[fmt.Sprintf("user:%02d", i)] = fmt.Sprintf("v%d", i)
+	}
+	kv.data["other"] = "x"
+	server := newFakeServer(t, kv.handle)
+	client := server.client()
+	defer client.Close()
+
+	manifest, err := client.BuildHashManifest(rocksdbclient.ScanOptions{Prefix: "user:"})
+	if err != nil {
+		t.Fatalf("failed to build manifest: %v", err)
+	}
+	if len(manifest.Hashes) != 10 || manifest.Hashes["user:03"] != rocksdbclient.ValueHash("v3") {
+		t.Fatalf("unexpected manifest %v", manifest.Hashes)
+	}
+
+	report, err := client.VerifyRestore(manifest, rocksdbclient.VerifyRestoreOptions{BatchSize: 3})
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if !report.Passed() || report.Checked != 10 {
+		t.Fatalf("expected a passing report, got %s", report)
+	}
+
+	kv.mu.Lock()
+	delete(kv.data, "user:01")
+	kv.data["user:05"] = "changed"
+	kv.mu.Unlock()
+
+	report, err = client.VerifyRestore(manifest, rocksdbclient.VerifyRestoreOptions{})
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if report.Passed() || len(report.Missing) != 1 || report.Missing[0] != "user:01" ||
+		len(report.Mismatched) != 1 || report.Mismatched[0] != "user:05" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if !strings.HasPrefix(report.String(), "FAIL: 10 keys checked, 1 missing, 1 mismatched") {
+		t.Fatalf("unexpected summary %q", report)
+	}
+
+	report, err = client.VerifyRestore(manifest, rocksdbclient.VerifyRestoreOptions{Sample: 4})
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if report.Checked != 4 {
+		t.Fatalf("expected 4 sampled keys, got %d", report.Checked)
+	}
+}
@@ -0,0 +1,189 @@
+package rocksdbclient_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+// jsonCodec is encoding/json as a rocksdbclient.JSONCodec.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// framedServer accepts the wire encoding and framing requested in the hello
+// handshake and then serves length-prefixed requests encoded with codec.
+func framedServer(t *testing.T, codec rocksdbclient.JSONCodec, handler func(rocksdbclient.Request) rocksdbclient.Response) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var hello rocksdbclient.Request
+		json.Unmarshal(line, &hello)
+		result, _ := json.Marshal(map[string]string{
+			"client_id":     "1",
+			"wire_encoding": hello.Options["wire_encoding"],
+			"framing":       hello.Options["framing"],
+		})
+		writeLine(conn, ok(string(result)))
+		for {
+			var prefix [4]byte
+			if _, err := io.ReadFull(reader, prefix[:]); err != nil {
+				return
+			}
+			payload := make([]byte, binary.BigEndian.Uint32(prefix[:]))
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			var req rocksdbclient.Request
+			if err := codec.Unmarshal(payload, &req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+				return
+			}
+			response := handler(req)
+			response.RequestID = req.RequestID
+			data, _ := codec.Marshal(response)
+			conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(data))), data...))
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestMsgpackWireEncoding(t *testing.T) {
+	kv := newFakeKV()
+	client := rocksdbclient.NewClient(framedServer(t, rocksdbclient.MsgpackCodec{}, kv.handle),
+		rocksdbclient.WithWireEncoding(rocksdbclient.WireMsgpack))
+	defer client.Close()
+
+	value := "line\none\x00\xff\xfe binary"
+	if _, err := client.Put(stringPtr("k"), &value, nil, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if client.WireEncoding() != rocksdbclient.WireMsgpack {
+		t.Fatalf("expected msgpack to be negotiated, got %s", client.WireEncoding())
+	}
+	response, err := client.Get(stringPtr("k"), nil, nil, nil)
+	if err != nil || response.Result != value {
+		t.Fatalf("expected %q, got %v, %v", value, response, err)
+	}
+	// Results that are JSON documents themselves, such as multi_get's, can
+	// only carry valid UTF-8.
+	client.Put(stringPtr("text"), stringPtr("a\nb"), nil, nil)
+	values, err := client.MultiGet([]string{"text", "missing"}, nil)
+	if err != nil || *values["text"] != "a\nb" || values["missing"] != nil {
+		t.Fatalf("unexpected multi_get result %v, %v", values, err)
+	}
+}
+
+func TestFraming(t *testing.T) {
+	kv := newFakeKV()
+	client := rocksdbclient.NewClient(framedServer(t, jsonCodec{}, kv.handle), rocksdbclient.WithFraming())
+	defer client.Close()
+
+	value := strings.Repeat("line\n", 100000)
+	if _, err := client.Put(stringPtr("k"), &value, nil, nil); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if !client.Framed() || client.WireEncoding() != rocksdbclient.WireJSON {
+		t.Fatalf("expected framed JSON, got framed=%v %s", client.Framed(), client.WireEncoding())
+	}
+	response, err := client.Get(stringPtr("k"), nil, nil, nil)
+	if err != nil || response.Result != value {
+		t.Fatalf("expected the value back, got %v", err)
+	}
+}
+
+func TestWireEncodingFallback(t *testing.T) {
+	server := newFakeServer(t, func(req rocksdbclient.Request) rocksdbclient.Response {
+		if req.Action == "hello" {
+			return fail("Unknown action")
+		}
+		return ok("v")
+	})
+	client := rocksdbclient.NewClient(server.listener.Addr().String(),
+		rocksdbclient.WithWireEncoding(rocksdbclient.WireMsgpack), rocksdbclient.WithFraming())
+	defer client.Close()
+
+	if response, err := client.Get(stringPtr("k"), nil, nil, nil); err != nil || response.Result != "v" {
+		t.Fatalf("expected JSON fallback, got %v, %v", response, err)
+	}
+	if client.WireEncoding() != rocksdbclient.WireJSON || client.Framed() {
+		t.Fatalf("expected JSON, got %s", client.WireEncoding())
+	}
+	if hello := server.received()[0]; hello.Action != "hello" || hello.Options["wire_encoding"] != "msgpack" || hello.Options["framing"] != "length" {
+		t.Fatalf("unexpected handshake %+v", hello)
+	}
+}
+
+func TestMsgpackCodec(t *testing.T) {
+	txn := true
+	request := rocksdbclient.Request{
+		Action:       "batch_write",
+		Key:          stringPtr("k"),
+		Value:        stringPtr("\xff\x00"),
+		CfName:       stringPtr("cf"),
+		DefaultValue: stringPtr(""),
+		Options:      map[string]string{"sync": "true", "ttl": "60"},
+		Token:        stringPtr("secret"),
+		Txn:          &txn,
+		TxnID:        stringPtr("7"),
+		Keys:         []string{"a", "b"},
+		Operations: []rocksdbclient.Operation{
+			{Type: rocksdbclient.OpPut, Key: "x", Value: stringPtr("y"), CfName: stringPtr("cf")},
+			{Type: rocksdbclient.OpDelete, Key: "z"},
+		},
+		RequestID: "9",
+	}
+	codec := rocksdbclient.MsgpackCodec{}
+	data, err := codec.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var decoded rocksdbclient.Request
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, request) {
+		t.Fatalf("round trip mismatch:\n%+v\nwant:\n%+v", decoded, request)
+	}
+	if err := codec.Unmarshal(data[:len(data)-3], &decoded); err == nil {
+		t.Fatal("expected truncated data to fail")
+	}
+
+	// Options are sorted, so equal requests encode identically.
+	request.Options = map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"}
+	first, _ := codec.Marshal(request)
+	for range 20 {
+		if again, _ := codec.Marshal(request); !bytes.Equal(again, first) {
+			t.Fatal("expected a deterministic encoding")
+		}
+	}
+
+	// A map with a keys array claiming 2^32-1 elements.
+	huge := []byte{0x81, 0xa4, 'k', 'e', 'y', 's', 0xdd, 0xff, 0xff, 0xff, 0xff}
+	if err := codec.Unmarshal(huge, &decoded); err == nil {
+		t.Fatal("expected an oversized length to fail")
+	}
+}
@@ -0,0 +1,49 @@
+package rocksdbclient_test
+
+import (
+	"testing"
+
+	rocksdbclient "github.com/s00d/RocksDBFusion/rocksdb-client-go/src"
+)
+
+func TestWriteOptions(t *testing.T) {
+	server := newFakeServer(t, func(rocksdbclient.Request) rocksdbclient.Response {
+		return ok("")
+	})
+	client := server.client()
+	defer client.Close()
+
+	bulk := rocksdbclient.WithWriteOptions(rocksdbclient.WriteOptions{DisableWAL: true, LowPri: true})
+	if _, err := client.PutWith("k", "v", nil, bulk); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, err := client.MergeWith("k", "[]", nil, bulk); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	durable := rocksdbclient.WithWriteOptions(rocksdbclient.WriteOptions{Sync: true})
+	if _, err := client.DeleteWith("k", nil, durable); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	batch := client.NewWriteBatch(rocksdbclient.WriteBatchOptions{Write: rocksdbclient.WriteOptions{DisableWAL: true}})
+	batch.Put("a", "1", nil)
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	want := []map[string]string{
+		{"disable_wal": "true", "low_pri": "true"},
+		{"disable_wal": "true", "low_pri": "true"},
+		{"sync": "true"},
+		{"disable_wal": "true"},
+	}
+	for i, req := range server.received() {
+		if len(req.Options) != len(want[i]) {
+			t.Fatalf("%s: expected options %v, got %v", req.Action, want[i], req.Options)
+		}
+		for k, v := range want[i] {
+			if req.Options[k] != v {
+				t.Fatalf("%s: expected options %v, got %v", req.Action, want[i], req.Options)
+			}
+		}
+	}
+}